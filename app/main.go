@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"dozenChairs/internal/metrics"
 	"dozenChairs/pkg/app"
 	"dozenChairs/pkg/config"
@@ -9,6 +10,7 @@ import (
 	"dozenChairs/pkg/logger"
 	"go.uber.org/zap"
 	"net/http"
+	"os"
 )
 
 func main() {
@@ -24,9 +26,25 @@ func main() {
 	conn := db.MustConnectDB(cfg, log)
 	defer conn.Close()
 
+	// Подкоманда импорта товаров: app import [-dry-run] <file>
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := app.RunImport(context.Background(), conn, os.Args[2:], os.Stdout); err != nil {
+			log.Error("import failed", zap.Error(err))
+			conn.Close()
+			logger.Sync()
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Сборка зависимостей и роутера
 	r := app.SetupRouter(cfg, log, conn)
 
+	// Фоновые задачи (планировщик публикаций, очистка корзины)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app.StartBackgroundJobs(ctx, cfg, log, conn)
+
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
 		Handler: r,
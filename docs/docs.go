@@ -127,6 +127,12 @@ const docTemplate = `{
                         "in": "formData",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ID варианта товара, если изображение относится к варианту",
+                        "name": "variant_id",
+                        "in": "formData"
+                    },
                     {
                         "type": "file",
                         "description": "Файлы изображений (можно несколько)",
@@ -154,6 +160,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "404": {
+                        "description": "Вариант не найден или принадлежит другому товару",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -166,205 +181,300 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/auth/callback/{provider}": {
-            "get": {
-                "description": "Обрабатывает код, полученный от VK, Google или Yandex, и возвращает JWT токены",
+        "/api/v1/admin/feeds/{name}/regenerate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Строит фид заново, не дожидаясь истечения срока кэша. В skipped перечислены товары, пропущенные из-за незаполненных обязательных полей.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Admin"
                 ],
-                "summary": "Callback от OAuth-провайдера",
+                "summary": "Перегенерировать фид (админка)",
                 "parameters": [
                     {
-                        "enum": [
-                            "google",
-                            "yandex",
-                            "vk"
-                        ],
                         "type": "string",
-                        "description": "OAuth-провайдер",
-                        "name": "provider",
+                        "description": "Имя фида (yandex.yml, google.xml)",
+                        "name": "name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Код от OAuth-провайдера",
-                        "name": "code",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.AuthResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.FeedInfo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/login": {
-            "post": {
-                "description": "Принимает email и пароль, возвращает access и refresh токены",
-                "consumes": [
-                    "application/json"
+        "/api/v1/admin/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Только для админов. Заказы с фильтрами, новые первыми.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Orders"
                 ],
-                "summary": "Авторизация пользователя",
+                "summary": "Список заказов",
                 "parameters": [
                     {
-                        "description": "Данные авторизации",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.LoginRequest"
-                        }
+                        "enum": [
+                            "new",
+                            "awaiting_payment",
+                            "paid",
+                            "assembling",
+                            "shipped",
+                            "delivered",
+                            "cancelled",
+                            "refunded"
+                        ],
+                        "type": "string",
+                        "description": "Статус",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID покупателя",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Оформлен не раньше (YYYY-MM-DD или RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Оформлен не позже (YYYY-MM-DD — включая этот день, или RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Номер заказа или часть имени, email, телефона",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит (по умолчанию 20, не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (по умолчанию 0)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.AuthResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ListMeta"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/auth/logout": {
-            "post": {
-                "description": "Удаляет refresh токен из хранилища и куки",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Выход пользователя",
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/me": {
+        "/api/v1/admin/orders/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает профиль пользователя по access токену",
+                "description": "Только для админов. Заказ с историей статусов и списком статусов, в которые его можно перевести.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Orders"
+                ],
+                "summary": "Получить заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить информацию о текущем пользователе",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.UserResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/oauth/{provider}": {
+        "/api/v1/admin/orders/{id}/payments": {
             "get": {
-                "description": "Редиректит пользователя на страницу авторизации Google, VK или Yandex",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Все платежи по заказу, последний первым.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Payments"
                 ],
-                "summary": "Перенаправление на OAuth-провайдера",
+                "summary": "Платежи заказа",
                 "parameters": [
                     {
-                        "enum": [
-                            "google",
-                            "yandex",
-                            "vk"
-                        ],
                         "type": "string",
-                        "description": "OAuth-провайдер",
-                        "name": "provider",
+                        "description": "ID заказа",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "307": {
-                        "description": "Redirect",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Payment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/refresh": {
+        "/api/v1/admin/orders/{id}/refund": {
             "post": {
-                "description": "Обновляет access токен по refresh токену из куки",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Возвращает через провайдера всю не возвращённую ещё сумму оплаченного платежа\nи переводит заказ в refunded; если заказ ещё не отгружен, товары возвращаются на склад.\nЕсли у заказа нет оплаченного платежа через провайдера (409), например он оплачен наличными,\nего переводят в refunded вручную через /admin/orders/{id}/transitions.",
                 "consumes": [
                     "application/json"
                 ],
@@ -372,28 +482,80 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Payments"
+                ],
+                "summary": "Вернуть деньги за заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина возврата",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.OrderRefundRequest"
+                        }
+                    }
                 ],
-                "summary": "Обновление access токена",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.AccessTokenResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/auth/register": {
+        "/api/v1/admin/orders/{id}/transitions": {
             "post": {
-                "description": "Создаёт нового пользователя и возвращает access и refresh токены",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Допустимые переходы: new → awaiting_payment | cancelled; awaiting_payment → paid | cancelled;\npaid → assembling | refunded; assembling → shipped | refunded; shipped → delivered | refunded; delivered → refunded.\nПереход записывается в историю заказа с автором и комментарием. Отмена снимает резерв товаров,\nоплата списывает зарезервированные товары со склада, а возврат денег до отгрузки возвращает их на склад.\nЗаказ с платежом через провайдера вручную в paid и refunded не переводится (409): оплату подтверждает провайдер,\nа деньги возвращаются через POST /admin/orders/{id}/refund.",
                 "consumes": [
                     "application/json"
                 ],
@@ -401,60 +563,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Orders"
                 ],
-                "summary": "Регистрация пользователя",
+                "summary": "Сменить статус заказа",
                 "parameters": [
                     {
-                        "description": "Данные регистрации",
-                        "name": "input",
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.RegisterRequest"
+                            "$ref": "#/definitions/dozenChairs_internal_dto.OrderTransitionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.AuthResponse"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/categories": {
-            "get": {
-                "description": "Возвращает все уникальные категории товаров",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Products"
-                ],
-                "summary": "Получить список категорий",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
                     "500": {
@@ -466,17 +630,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/products": {
+        "/api/v1/admin/products": {
             "get": {
-                "description": "Возвращает список товаров или наборов. Доступна фильтрация по типу, категории и наличию, а также сортировка по цене и дате создания.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Как и публичный список, но включает черновики и архивные товары. Фасеты не считаются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Получить список товаров",
+                "summary": "Получить все товары (админка)",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Статус (draft, published, archived)",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Тип товара (product или set)",
@@ -485,19 +660,19 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Категория",
+                        "description": "Категория (slug или название), включая подкатегории",
                         "name": "category",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Есть в наличии",
-                        "name": "inStock",
+                        "type": "string",
+                        "description": "Поисковая строка",
+                        "name": "q",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Сортировка (price или createdAt)",
+                        "description": "Сортировка (price, createdAt; префикс - для обратного порядка, по умолчанию -createdAt)",
                         "name": "sort",
                         "in": "query"
                     },
@@ -512,16 +687,43 @@ const docTemplate = `{
                         "description": "Смещение (по умолчанию 0)",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (meta.next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ProductListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
                     "500": {
@@ -531,44 +733,108 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/admin/products/import": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Только для админов. Создаёт новый товар или набор. У набора нужно указать поле ` + "`" + `includes` + "`" + `, а у обычного товара — ` + "`" + `unitCount` + "`" + ` и ` + "`" + `attributes` + "`" + `.",
+                "description": "Только для админов. Первая строка файла — заголовок с именами полей товара (slug, type, title, category, categoryId, description, price, oldPrice, inStock, unitCount, tags, includes, setDiscountPercent, status, publishAt, unpublishAt), атрибуты — колонки attr.\u003cключ\u003e.\nСтроки сопоставляются с товарами по slug (если он не указан — по slug из title): существующие товары обновляются заполненными ячейками, остальные создаются.\ntags и includes перечисляются через запятую или точку с запятой, состав набора — в виде slug:количество.\nВсе строки сохраняются в одной транзакции, ошибочные строки пропускаются и попадают в отчёт. С dryRun=true изменения только проверяются.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Создать товар",
+                "summary": "Импорт товаров из CSV/XLSX",
                 "parameters": [
                     {
-                        "description": "Данные нового товара или набора",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
+                        "type": "file",
+                        "description": "Файл .csv или .xlsx",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Проверить без сохранения",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.ImportReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     }
+                }
+            }
+        },
+        "/api/v1/admin/products/{slug}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Возвращает товар в любом статусе.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Получить товар по slug (админка)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/dozenChairs_internal_models.Product"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
@@ -582,27 +848,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/products/new": {
+        "/api/v1/admin/products/{slug}/revisions": {
             "get": {
-                "description": "Возвращает список недавно добавленных товаров — либо последние N штук, либо за последние X дней",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Журнал изменений товара и его изображений от новых к старым: кто, когда и какие поля изменил. Снимки состояния в списке не возвращаются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Revisions"
                 ],
-                "summary": "Получить список новинок",
+                "summary": "История правок товара",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "Лимит (по умолчанию 10)",
+                        "description": "Лимит (по умолчанию 20)",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "За сколько последних дней брать товары",
-                        "name": "days",
+                        "description": "Смещение (по умолчанию 0)",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
@@ -610,10 +888,31 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.ProductRevision"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
                     "500": {
@@ -625,16 +924,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/products/{slug}": {
+        "/api/v1/admin/products/{slug}/revisions/{id}": {
             "get": {
-                "description": "Возвращает один товар по его уникальному slug. Включает изображения, атрибуты и, при типе set — включённые товары.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Возвращает ревизию вместе со снимком состояния товара после изменения.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Revisions"
                 ],
-                "summary": "Получить товар по slug",
+                "summary": "Получить ревизию товара",
                 "parameters": [
                     {
                         "type": "string",
@@ -642,13 +946,20 @@ const docTemplate = `{
                         "name": "slug",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID ревизии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductRevision"
                         }
                     },
                     "404": {
@@ -664,24 +975,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/admin/products/{slug}/revisions/{id}/rollback": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Только для админов. Обновляет данные товара по slug. Все поля можно изменить, включая изображения, состав набора и атрибуты.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Только для админов. Возвращает поля товара к состоянию из снимка ревизии. Изображения и варианты не меняются. Откат записывается в историю новой ревизией.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Revisions"
                 ],
-                "summary": "Обновить товар",
+                "summary": "Откатить товар к ревизии",
                 "parameters": [
                     {
                         "type": "string",
@@ -691,13 +1001,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Обновлённые данные товара",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
-                        }
+                        "type": "string",
+                        "description": "ID ревизии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -713,6 +1021,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -720,21 +1040,23 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/admin/products/{slug}/stock": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Только для админов. Удаляет товар по slug. При удалении набора удаляется только сам набор, не включённые в него товары.",
+                "description": "Только для админов. Остатки товара по складам: без варианта и по каждому варианту.\nreserved — зарезервировано под неоплаченные заказы, available — доступно для заказа.\nПустой список — остаток товара не учитывается.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Stock"
                 ],
-                "summary": "Удалить товар",
+                "summary": "Остатки товара",
                 "parameters": [
                     {
                         "type": "string",
@@ -745,8 +1067,32 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.StockLevel"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -757,38 +1103,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sets": {
+        "/api/v1/admin/products/{slug}/stock/movements": {
             "get": {
-                "description": "Возвращает все товары типа set. Наборы включают список вложенных товаров (` + "`" + `includes` + "`" + `).",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Движения остатков товара от новых к старым: поступления, резервы и их снятие,\nпродажи, возвраты и корректировки — с остатком после движения, причиной, заказом и автором.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sets"
+                    "Stock"
                 ],
-                "summary": "Получить список наборов",
+                "summary": "Журнал движений по складу",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Есть в наличии",
-                        "name": "inStock",
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID варианта",
+                        "name": "variantId",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Сортировка (price или createdAt)",
-                        "name": "sort",
+                        "description": "ID склада",
+                        "name": "warehouseId",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Лимит на страницу",
-                        "name": "limit",
+                        "enum": [
+                            "receipt",
+                            "reservation",
+                            "release",
+                            "sale",
+                            "return",
+                            "adjustment"
+                        ],
+                        "type": "string",
+                        "description": "Вид движения",
+                        "name": "type",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Смещение",
+                        "description": "Лимит (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (по умолчанию 0)",
                         "name": "offset",
                         "in": "query"
                     }
@@ -797,10 +1169,111 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.StockMovement"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Поступление (receipt), возврат (return) или корректировка (adjustment, quantity со знаком)\nна складе warehouseId. Перемещение между складами проводится двумя корректировками.\nДля товара с вариантами нужен variantId; у наборов остаток складывается из компонентов и не ведётся.\nЕсли остаток стал бы меньше нуля или зарезервированного, возвращается 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Провести движение по складу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Движение",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.StockMovementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.StockLevel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
                     "500": {
@@ -812,21 +1285,138 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/sets/{slug}": {
+        "/api/v1/admin/promo-codes": {
             "get": {
-                "description": "Возвращает товар типа set по его slug. Если товар не является набором, будет возвращена ошибка.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Все промокоды со счётчиком применений, новые первыми.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sets"
+                    "PromoCodes"
                 ],
-                "summary": "Получить набор по slug",
+                "summary": "Список промокодов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.PromoCode"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Код сохраняется в верхнем регистре и вводится покупателями без учёта регистра.\nБез categoryIds и productIds промокод действует на все товары заказа.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PromoCodes"
+                ],
+                "summary": "Создать промокод",
+                "parameters": [
+                    {
+                        "description": "Данные промокода",
+                        "name": "promoCode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.PromoCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.PromoCode"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/promo-codes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PromoCodes"
+                ],
+                "summary": "Получить промокод",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Slug набора",
-                        "name": "slug",
+                        "description": "ID промокода",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -835,7 +1425,87 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.PromoCode"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Заменяет настройки промокода целиком; счётчик применений сохраняется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PromoCodes"
+                ],
+                "summary": "Обновить промокод",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID промокода",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные промокода",
+                        "name": "promoCode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.PromoCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.PromoCode"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -850,6 +1520,53 @@ const docTemplate = `{
                             "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Вместе с промокодом удаляется история его применений; чтобы её сохранить, выключите промокод.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PromoCodes"
+                ],
+                "summary": "Удалить промокод",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID промокода",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -858,221 +1575,5521 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
+        },
+        "/api/v1/admin/promotions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Все акции, включая выключенные и завершившиеся, от большего приоритета к меньшему.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Список акций",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Promotion"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Условия categoryIds (с подкатегориями), tags и productIds объединяются через «или»,\nproductType дополнительно ограничивает тип товара; без условий акция действует на весь каталог.\nСкидка percent — процент от цены, fixed — сумма в рублях.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Создать акцию",
+                "parameters": [
+                    {
+                        "description": "Данные акции",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Promotion"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/promotions/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Возвращает витринные товары, попадающие под условия акции из тела запроса,\nс ценой до и после скидки. Акция не сохраняется; другие акции и период действия не учитываются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Предпросмотр акции",
+                "parameters": [
+                    {
+                        "description": "Данные акции",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.PromotionPreviewItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/promotions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Получить акцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID акции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Promotion"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Заменяет акцию целиком.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Обновить акцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID акции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные акции",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Promotion"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Удалить акцию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID акции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/promotions/{id}/preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Товары, на которые действует акция, с ценой до и после скидки.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Предпросмотр сохранённой акции",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID акции",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.PromotionPreviewItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Удалённые товары, которые ещё можно восстановить. По истечении срока хранения они удаляются окончательно вместе с файлами изображений.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Корзина товаров",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип товара (product или set)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поисковая строка",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит на страницу (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (по умолчанию 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (meta.next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ProductListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/trash/{slug}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Товар возвращается с прежним slug, статусом и изображениями. Набор восстанавливается только после всех входящих в него товаров.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Восстановить товар из корзины",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/warehouses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Все склады и шоурумы, включая выключенные, в порядке резервирования товара под заказ.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Список складов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Склады с меньшим priority резервируют товар под заказ первыми (после складов города покупателя).\ndeliveryDays — срок курьерской доставки по городу склада, intercityDeliveryDays — в другие города;\nбез них склад под доставку не резервирует. pickup — склад является пунктом самовывоза.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Создать склад",
+                "parameters": [
+                    {
+                        "description": "Данные склада",
+                        "name": "warehouse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/warehouses/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Получить склад",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID склада",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Заменяет склад целиком. Остатки выключенного склада не продаются\nи не входят в наличие товаров; уже созданные резервы сохраняются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Обновить склад",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID склада",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные склада",
+                        "name": "warehouse",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Склад, по которому были движения остатков или оформлены заказы, удалить нельзя — его можно выключить.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Удалить склад",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID склада",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/callback/{provider}": {
+            "get": {
+                "description": "Обрабатывает код, полученный от VK, Google или Yandex, и возвращает JWT токены",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Callback от OAuth-провайдера",
+                "parameters": [
+                    {
+                        "enum": [
+                            "google",
+                            "yandex",
+                            "vk"
+                        ],
+                        "type": "string",
+                        "description": "OAuth-провайдер",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код от OAuth-провайдера",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/login": {
+            "post": {
+                "description": "Принимает email и пароль, возвращает access и refresh токены.\nГостевая корзина из cookie cart_id переносится в корзину пользователя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Авторизация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные авторизации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/logout": {
+            "post": {
+                "description": "Удаляет refresh токен из хранилища и куки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выход пользователя",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает профиль пользователя по access токену",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Получить информацию о текущем пользователе",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.UserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/oauth/{provider}": {
+            "get": {
+                "description": "Редиректит пользователя на страницу авторизации Google, VK или Yandex",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Перенаправление на OAuth-провайдера",
+                "parameters": [
+                    {
+                        "enum": [
+                            "google",
+                            "yandex",
+                            "vk"
+                        ],
+                        "type": "string",
+                        "description": "OAuth-провайдер",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "307": {
+                        "description": "Redirect",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "Обновляет access токен по refresh токену из куки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновление access токена",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.AccessTokenResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/register": {
+            "post": {
+                "description": "Создаёт нового пользователя и возвращает access и refresh токены",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cart": {
+            "get": {
+                "description": "Корзина вошедшего пользователя или гостевая корзина из cookie cart_id.\nЦены и суммы пересчитываются по текущим ценам витрины с учётом акций;\nпозиции, которые нельзя заказать (сняты с продажи, нет в наличии), перечислены в warnings и в суммы не входят.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Корзина покупателя",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Cart"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Очистить корзину",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cart/items": {
+            "post": {
+                "description": "Кладёт товар, набор или вариант товара в корзину; если он уже в корзине, количество увеличивается.\nГостю при первом добавлении выдаётся cookie cart_id; после входа гостевая корзина переносится в корзину пользователя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Добавить товар в корзину",
+                "parameters": [
+                    {
+                        "description": "Товар и количество",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.CartItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Cart"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cart/items/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Изменить количество в корзине",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID позиции корзины",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое количество",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.CartQuantityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Cart"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Удалить позицию из корзины",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID позиции корзины",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Cart"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories": {
+            "get": {
+                "description": "Возвращает все категории плоским списком, упорядоченным по sortOrder и названию",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Получить список категорий",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Для вложенной категории укажите ` + "`" + `parentId` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Создать категорию",
+                "parameters": [
+                    {
+                        "description": "Данные категории",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories/tree": {
+            "get": {
+                "description": "Возвращает корневые категории с вложенными подкатегориями (` + "`" + `children` + "`" + `)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Получить дерево категорий",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Позволяет переименовать категорию, сменить slug или перенести её под другого родителя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Обновить категорию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные категории",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Категорию с подкатегориями или товарами удалить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Удалить категорию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID категории",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/categories/{slug}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Получить категорию по slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug категории",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/feeds/{name}": {
+            "get": {
+                "description": "Отдаёт сохранённый фид (yandex.yml — YML для Яндекс Маркета, google.xml — RSS для Google Merchant Center). Устаревший фид перегенерируется при запросе.\nПоддерживаются условные запросы (If-Modified-Since) и Range.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Фид каталога для маркетплейса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя фида (yandex.yml, google.xml)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заказы текущего пользователя, новые первыми.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Мои заказы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Лимит (по умолчанию 20, не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (по умолчанию 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Оформляет заказ из корзины покупателя: позиции, названия и цены (с акциями и промокодом) сохраняются снимком,\nкорзина очищается. Заказ создаётся в статусе new, товары резервируются на складе до reservedUntil:\nесли заказ не оплачен к этому времени, он отменяется, резерв снимается, а применённый промокод освобождается.\nТовары резервируются на складах, отгружающих в город заказа (сначала в самом городе), а при самовывозе —\nв выбранном пункте pickupPointId (список — GET /pickup-points); город и адрес заказа берутся из пункта.\nЕсли в корзине есть позиции, которые нельзя заказать, товара не хватает на складе\nили корзина изменилась во время оформления, возвращается 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Оформить заказ",
+                "parameters": [
+                    {
+                        "description": "Контакты, доставка и промокод",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.CheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заказ текущего пользователя с историей статусов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Мой заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Покупатель может отменить свой заказ, пока он не оплачен (статусы new и awaiting_payment). Резерв товаров снимается, применённый промокод снова становится доступен.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Отменить заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отмены",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.OrderCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Order"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/{id}/payment": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Последний платёж по заказу текущего пользователя; статус незавершённого платежа сверяется с провайдером.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Оплата моего заказа",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Payment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/orders/{id}/payments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Начинает оплату заказа в статусе new или awaiting_payment: заказ переводится в awaiting_payment,\nу платёжного провайдера создаётся платёж на сумму заказа. Покупателя нужно отправить на confirmationUrl;\nкогда провайдер сообщит об оплате, заказ станет paid. Пока прошлый платёж не завершён, возвращается он же,\nпоэтому повторный запрос не создаёт второй платёж.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Оплатить заказ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID заказа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.Payment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/payments/webhook/{provider}": {
+            "post": {
+                "description": "Принимает уведомления о платежах в формате YooKassa. Уведомления YooKassa принимаются только с её адресов\n(YOOKASSA_WEBHOOK_IPS; за reverse proxy адрес берётся из заголовка PAYMENT_WEBHOOK_REAL_IP_HEADER).\nУведомление о платеже, которого нет в базе, отклоняется без обращения к провайдеру. Статус платежа\nперепроверяется у провайдера, оплаченный заказ переводится в paid; повторные уведомления ничего не меняют.\nНа ответ не 200 провайдер повторяет уведомление позже. Принимаются уведомления только настроенного провайдера;\nвстроенный провайдер fake в production (APP_ENV=production) недоступен.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payments"
+                ],
+                "summary": "Уведомление платёжного провайдера",
+                "parameters": [
+                    {
+                        "enum": [
+                            "yookassa",
+                            "fake"
+                        ],
+                        "type": "string",
+                        "description": "Провайдер",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/pickup-points": {
+            "get": {
+                "description": "Работающие склады и шоурумы, где можно забрать заказ, со сроком готовности заказа к выдаче.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Пункты самовывоза",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Город",
+                        "name": "city",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products": {
+            "get": {
+                "description": "Возвращает список опубликованных товаров или наборов вместе с фасетами (количество товаров по значениям атрибутов и диапазон цен). Доступна фильтрация по типу, категории, наличию, цене, тегам и атрибутам, а также сортировка по цене и дате создания. При указании ` + "`" + `q` + "`" + ` выполняется полнотекстовый поиск, результаты ранжируются по релевантности и содержат подсветку совпадений. Цены в ответе учитывают действующие акции (` + "`" + `price` + "`" + ` — цена со скидкой, ` + "`" + `oldPrice` + "`" + ` — до неё), а фильтр priceMin/priceMax, сортировка по цене и фасет цен работают по базовой цене товара без акций.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Получить список товаров",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковая строка",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип товара (product или set)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Категория (slug или название), включая подкатегории",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть в наличии",
+                        "name": "inStock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Город: inStock проверяется по остаткам складов этого города",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код или ID склада: inStock проверяется по остаткам этого склада",
+                        "name": "warehouse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная цена",
+                        "name": "priceMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная цена",
+                        "name": "priceMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Теги через запятую (товар должен содержать все)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по атрибуту: одно или несколько значений через запятую (например, attr.color=white,black)",
+                        "name": "attr.{key}",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Нижняя граница числового атрибута (например, attr.seat_height.min=45)",
+                        "name": "attr.{key}.min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Верхняя граница числового атрибута",
+                        "name": "attr.{key}.max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка (price, createdAt; префикс - для обратного порядка, по умолчанию -createdAt)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит на страницу (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (по умолчанию 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (meta.next_cursor), используется вместо offset",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ProductListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Создаёт новый товар или набор. Категория задаётся через ` + "`" + `categoryId` + "`" + ` либо slug/название в ` + "`" + `category` + "`" + `. У набора нужно указать поле ` + "`" + `includes` + "`" + `, а у обычного товара — ` + "`" + `unitCount` + "`" + ` и ` + "`" + `attributes` + "`" + `. Без ` + "`" + `status` + "`" + ` товар публикуется сразу, а при ` + "`" + `publishAt` + "`" + ` в будущем сохраняется черновиком и публикуется автоматически; по ` + "`" + `unpublishAt` + "`" + ` товар уходит в архив. Если ` + "`" + `slug` + "`" + ` не указан, он генерируется из названия (транслитерация по ГОСТ 7.79-2000) с числовым суффиксом при совпадении. Включённые в набор товары должны существовать, иметь тип product и не иметь вариантов; наличие набора считается по их остаткам, а при заданном ` + "`" + `setDiscountPercent` + "`" + ` — и цена.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Создать товар",
+                "parameters": [
+                    {
+                        "description": "Данные нового товара или набора",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/new": {
+            "get": {
+                "description": "Возвращает список недавно добавленных товаров — либо последние N штук, либо за последние X дней",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Получить список новинок",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Лимит (по умолчанию 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "За сколько последних дней брать товары",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (meta.next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ProductListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/search": {
+            "get": {
+                "description": "Ищет товары по названию, описанию, тегам и значениям атрибутов с учётом русской морфологии. Результаты ранжируются по релевантности и содержат подсветку совпадений (` + "`" + `\u003cmark\u003e` + "`" + `). Цены в ответе учитывают действующие акции (` + "`" + `price` + "`" + ` — цена со скидкой, ` + "`" + `oldPrice` + "`" + ` — до неё), а фильтр priceMin/priceMax, сортировка по цене и фасет цен работают по базовой цене товара без акций.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Полнотекстовый поиск товаров",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковая строка",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип товара (product или set)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Категория (slug или название), включая подкатегории",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Есть в наличии",
+                        "name": "inStock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Город: inStock проверяется по остаткам складов этого города",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код или ID склада: inStock проверяется по остаткам этого склада",
+                        "name": "warehouse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Минимальная цена",
+                        "name": "priceMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальная цена",
+                        "name": "priceMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Теги через запятую (товар должен содержать все)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по атрибуту: одно или несколько значений через запятую (например, attr.color=white,black)",
+                        "name": "attr.{key}",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Нижняя граница числового атрибута (например, attr.seat_height.min=45)",
+                        "name": "attr.{key}.min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Верхняя граница числового атрибута",
+                        "name": "attr.{key}.max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка (price, createdAt; префикс - для обратного порядка), по умолчанию — по релевантности",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит на страницу (по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (по умолчанию 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.ProductSearchResult"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ProductListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{slug}": {
+            "get": {
+                "description": "Возвращает один товар по его уникальному slug. Включает изображения, атрибуты, варианты с диапазоном цен и, при типе set — включённые товары. Черновики и архивные товары не отдаются. Для прежнего slug переименованного товара отвечает 301 на текущий адрес.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Получить товар по slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    },
+                    "301": {
+                        "description": "Товар переименован, Location содержит текущий адрес"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Обновляет данные товара по slug. Все поля можно изменить, включая изображения, состав набора и атрибуты. Новый ` + "`" + `slug` + "`" + ` переименовывает товар: прежний продолжает работать через 301-редирект. Пустой ` + "`" + `slug` + "`" + ` оставляет текущий. Требуется заголовок ` + "`" + `If-Match` + "`" + ` с ETag товара (из GET): если товар успел измениться, возвращается 412 с его актуальным состоянием.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Обновить товар",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag товара, например \\",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные товара",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия товара"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Переносит товар в корзину: он пропадает с витрины, но его можно восстановить вместе с изображениями, пока корзина не очищена. При удалении набора удаляется только сам набор, не включённые в него товары. Товар, входящий в какой-либо набор, удалить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Удалить товар",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Принимает JSON Merge Patch (` + "`" + `application/merge-patch+json` + "`" + `, RFC 7396) или JSON Patch (` + "`" + `application/json-patch+json` + "`" + `, RFC 6902). Патч применяется к текущему представлению товара, результат проверяется целиком, а сохраняются только изменившиеся поля. Поля id, version, createdAt, updatedAt, images и variants менять нельзя. Заголовок ` + "`" + `If-Match` + "`" + ` необязателен: если он передан и товар успел измениться, возвращается 412 с актуальным состоянием.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Частично обновить товар",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag товара, например \\",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge Patch (объект) или JSON Patch (массив операций)",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия товара"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{slug}/availability": {
+            "get": {
+                "description": "Где товар есть, где его можно забрать самовывозом и за сколько дней его доставят в город city.\nДля товара с вариантами без variantId остатки вариантов складываются; набор доступен на складе\nв количестве комплектов, которые собираются из его остатков. Если остаток товара не учитывается\nпо складам (tracked = false), наличие определяется только флагом inStock.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Наличие товара по складам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID варианта",
+                        "name": "variantId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Город покупателя",
+                        "name": "city",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.ProductAvailability"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{slug}/price-history": {
+            "get": {
+                "description": "Изменения цены за последние days дней от новых к старым; последней идёт цена, действовавшая в начале периода.\nМинимальная цена за 30 дней отдаётся в самом товаре в поле lowestPrice30Days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "История цены товара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Период в днях (по умолчанию 30, не больше 365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.PricePoint"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{slug}/variants": {
+            "get": {
+                "description": "Возвращает варианты (SKU) товара с опциями, ценой, остатком и изображениями",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Variants"
+                ],
+                "summary": "Получить варианты товара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dozenChairs_internal_models.ProductVariant"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Добавляет вариант с собственным SKU, опциями (например, цвет и обивка), ценой и остатком. Если цена не указана, используется цена товара. Товару, входящему в набор, вариант добавить нельзя (409).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Variants"
+                ],
+                "summary": "Создать вариант товара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные варианта",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductVariant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{slug}/variants/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Остаток и наличие товара пересчитываются по всем его вариантам.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Variants"
+                ],
+                "summary": "Обновить вариант товара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID варианта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновлённые данные варианта",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductVariant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductVariant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Только для админов. Изображения варианта остаются привязанными к товару.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Variants"
+                ],
+                "summary": "Удалить вариант товара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug товара",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID варианта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/promo-codes/validate": {
+            "post": {
+                "description": "Проверяет промокод для позиций заказа и возвращает суммы со скидкой. Цены берутся с витрины с учётом акций.\nАвторизация необязательна: для вошедшего покупателя дополнительно проверяется лимит применений на одного покупателя.\nПромокод при этом не расходуется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PromoCodes"
+                ],
+                "summary": "Проверить промокод",
+                "parameters": [
+                    {
+                        "description": "Промокод и позиции заказа",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_dto.PromoCodeValidateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dozenChairs_internal_models.PromoCodeQuote"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sets": {
+            "get": {
+                "description": "Возвращает все товары типа set. Наборы включают список вложенных товаров (` + "`" + `includes` + "`" + `).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sets"
+                ],
+                "summary": "Получить список наборов",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Есть в наличии",
+                        "name": "inStock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Город: inStock проверяется по остаткам складов этого города",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код или ID склада: inStock проверяется по остаткам этого склада",
+                        "name": "warehouse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сортировка (price, createdAt; префикс - для обратного порядка)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Лимит на страницу",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (meta.next_cursor)",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/dozenChairs_internal_dto.ProductListMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/sets/{slug}": {
+            "get": {
+                "description": "Возвращает товар типа set по его slug с раскрытыми данными входящих в него товаров (` + "`" + `includes[].product` + "`" + `). Наличие и остаток набора рассчитываются по компонентам. Если товар не является набором, будет возвращена ошибка. Для прежнего slug отвечает 301 на текущий адрес.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sets"
+                ],
+                "summary": "Получить набор по slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug набора",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    },
+                    "301": {
+                        "description": "Набор переименован, Location содержит текущий адрес"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/robots.txt": {
+            "get": {
+                "description": "Содержимое файла ROBOTS_TXT_PATH или правила по умолчанию (закрыты пути из ROBOTS_DISALLOW) со ссылкой на sitemap.xml",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "SEO"
+                ],
+                "summary": "robots.txt",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemap-{n}.xml": {
+            "get": {
+                "description": "Файл из индекса sitemap.xml, когда адресов больше 50 000.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "SEO"
+                ],
+                "summary": "Часть sitemap",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Номер части, начиная с 1",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemap.xml": {
+            "get": {
+                "description": "Страницы категорий и опубликованных товаров с lastmod и изображениями (image sitemap).\nЕсли адресов больше 50 000, возвращается индекс со ссылками на /sitemap-N.xml.\nПерегенерируется при изменении товаров, изображений или категорий.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "SEO"
+                ],
+                "summary": "sitemap.xml витрины",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dozenChairs_pkg_httphelper.APIResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
     "definitions": {
         "dozenChairs_internal_dto.AccessTokenResponse": {
             "type": "object",
             "properties": {
-                "accessToken": {
+                "accessToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dozenChairs_internal_dto.UserResponse"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.CartItemRequest": {
+            "type": "object",
+            "required": [
+                "productId",
+                "quantity"
+            ],
+            "properties": {
+                "productId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 999
+                },
+                "variantId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.CartQuantityRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 999
+                }
+            }
+        },
+        "dozenChairs_internal_dto.CheckoutRequest": {
+            "type": "object",
+            "required": [
+                "customerName",
+                "deliveryMethod",
+                "email",
+                "phone"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Address обязателен для доставки курьером",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "city": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "customerName": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "deliveryMethod": {
+                    "enum": [
+                        "courier",
+                        "pickup"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.DeliveryMethod"
+                        }
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 5
+                },
+                "pickupPointId": {
+                    "description": "PickupPointID — склад или шоурум для самовывоза; город и адрес заказа берутся из него",
+                    "type": "string"
+                },
+                "promoCode": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "dozenChairs_internal_dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.ListMeta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "dozenChairs_internal_dto.OrderCancelRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "dozenChairs_internal_dto.OrderRefundRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "dozenChairs_internal_dto.OrderTransitionRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "status": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.OrderStatus"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.ProductListMeta": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.ProductFacets"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.PromoCodeValidateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "items"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.OrderItemInput"
+                    }
+                }
+            }
+        },
+        "dozenChairs_internal_dto.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string",
+                    "minLength": 3
+                }
+            }
+        },
+        "dozenChairs_internal_dto.StockMovementRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "reason",
+                "type",
+                "warehouseId"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "type": {
+                    "enum": [
+                        "receipt",
+                        "return",
+                        "adjustment"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.StockMovementType"
+                        }
+                    ]
+                },
+                "variantId": {
+                    "type": "string"
+                },
+                "warehouseId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_dto.UserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.AppliedPromotion": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.Cart": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID пуст, пока в корзину ничего не добавляли",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.CartItem"
+                    }
+                },
+                "quantity": {
+                    "description": "Quantity — общее количество единиц товара в корзине",
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "description": "Subtotal — стоимость доступных к заказу позиций по ценам без скидок, Total — с учётом акций",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "description": "Warnings — позиции, которые нельзя заказать в текущем виде; они не входят в суммы",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.CartWarning"
+                    }
+                }
+            }
+        },
+        "dozenChairs_internal_models.CartItem": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
+                    "type": "string"
+                },
+                "available": {
+                    "description": "Available — позицию можно заказать: товар опубликован и есть в наличии в нужном количестве",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "lineTotal": {
+                    "type": "integer"
+                },
+                "oldPrice": {
+                    "type": "integer"
+                },
+                "options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.ProductType"
+                },
+                "variantId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.CartWarning": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Available — сколько единиц есть в наличии (для insufficient_stock)",
+                    "type": "integer"
+                },
+                "code": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.CartWarningCode"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "productId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.CartWarningCode": {
+            "type": "string",
+            "enum": [
+                "unavailable",
+                "out_of_stock",
+                "insufficient_stock"
+            ],
+            "x-enum-comments": {
+                "CartItemInsufficient": "в наличии меньше, чем в корзине",
+                "CartItemOutOfStock": "товара нет в наличии",
+                "CartItemUnavailable": "товар снят с продажи или удалён"
+            },
+            "x-enum-descriptions": [
+                "товар снят с продажи или удалён",
+                "товара нет в наличии",
+                "в наличии меньше, чем в корзине"
+            ],
+            "x-enum-varnames": [
+                "CartItemUnavailable",
+                "CartItemOutOfStock",
+                "CartItemInsufficient"
+            ]
+        },
+        "dozenChairs_internal_models.Category": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "children": {
+                    "description": "заполняется только в дереве категорий",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.Category"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.DeliveryMethod": {
+            "type": "string",
+            "enum": [
+                "courier",
+                "pickup"
+            ],
+            "x-enum-varnames": [
+                "DeliveryCourier",
+                "DeliveryPickup"
+            ]
+        },
+        "dozenChairs_internal_models.DiscountType": {
+            "type": "string",
+            "enum": [
+                "percent",
+                "fixed"
+            ],
+            "x-enum-comments": {
+                "DiscountFixed": "фиксированная сумма в рублях",
+                "DiscountPercent": "процент от цены"
+            },
+            "x-enum-descriptions": [
+                "процент от цены",
+                "фиксированная сумма в рублях"
+            ],
+            "x-enum-varnames": [
+                "DiscountPercent",
+                "DiscountFixed"
+            ]
+        },
+        "dozenChairs_internal_models.FacetValue": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.FeedInfo": {
+            "type": "object",
+            "properties": {
+                "generatedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "offers": {
+                    "description": "количество выгруженных товаров",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "размер файла в байтах",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Skipped — товары, не попавшие в фид из-за незаполненных обязательных полей",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.FeedSkippedItem"
+                    }
+                }
+            }
+        },
+        "dozenChairs_internal_models.FeedSkippedItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "missing": {
+                    "description": "незаполненные обязательные поля фида",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {}
+            }
+        },
+        "dozenChairs_internal_models.Image": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "description": "изменения проверены, но не сохранены",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.ImportRowResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_models.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "row": {
+                    "description": "номер строки в файле, считая заголовок",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.ImportRowStatus"
+                }
+            }
+        },
+        "dozenChairs_internal_models.ImportRowStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "updated",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ImportCreated",
+                "ImportUpdated",
+                "ImportFailed"
+            ]
+        },
+        "dozenChairs_internal_models.IncludeItem": {
+            "type": "object",
+            "required": [
+                "productId",
+                "quantity"
+            ],
+            "properties": {
+                "product": {
+                    "description": "раскрытые данные компонента (GET набора)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    ]
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_models.Order": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "customerName": {
+                    "type": "string"
+                },
+                "deliveryMethod": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.DeliveryMethod"
+                },
+                "discount": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.OrderStatusChange"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.OrderItem"
+                    }
+                },
+                "nextStatuses": {
+                    "description": "NextStatuses — статусы, в которые заказ можно перевести сейчас",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.OrderStatus"
+                    }
+                },
+                "number": {
+                    "description": "Number — короткий номер заказа для покупателя и поддержки",
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "pickupPointId": {
+                    "description": "склад или шоурум, где покупатель заберёт заказ (DeliveryPickup)",
+                    "type": "string"
+                },
+                "promoCode": {
+                    "type": "string"
+                },
+                "promoDiscount": {
+                    "type": "integer"
+                },
+                "reservedUntil": {
+                    "description": "до этого времени товары зарезервированы; неоплаченный заказ затем отменяется",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.OrderStatus"
+                },
+                "subtotal": {
+                    "description": "Subtotal — стоимость по ценам без скидок, Discount — скидка по акциям,\nPromoDiscount — скидка по промокоду PromoCode; Total — к оплате",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.OrderItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "lineTotal": {
+                    "type": "integer"
+                },
+                "oldPrice": {
+                    "type": "integer"
+                },
+                "options": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "description": "Price — цена витрины с учётом акций, OldPrice — зачёркнутая цена",
+                    "type": "integer"
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "promoDiscount": {
+                    "description": "PromoDiscount — доля скидки по промокоду, LineTotal — стоимость позиции с её учётом",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.ProductType"
+                },
+                "variantId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.OrderItemInput": {
+            "type": "object",
+            "required": [
+                "productId",
+                "quantity"
+            ],
+            "properties": {
+                "productId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "variantId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "new",
+                "awaiting_payment",
+                "paid",
+                "assembling",
+                "shipped",
+                "delivered",
+                "cancelled",
+                "refunded"
+            ],
+            "x-enum-comments": {
+                "OrderAssembling": "собирается на складе",
+                "OrderAwaitingPayment": "ждёт оплаты",
+                "OrderCancelled": "отменён до оплаты",
+                "OrderDelivered": "получен покупателем",
+                "OrderNew": "оформлен, ждёт подтверждения",
+                "OrderPaid": "оплачен",
+                "OrderRefunded": "деньги за оплаченный заказ возвращены",
+                "OrderShipped": "передан в доставку"
+            },
+            "x-enum-descriptions": [
+                "оформлен, ждёт подтверждения",
+                "ждёт оплаты",
+                "оплачен",
+                "собирается на складе",
+                "передан в доставку",
+                "получен покупателем",
+                "отменён до оплаты",
+                "деньги за оплаченный заказ возвращены"
+            ],
+            "x-enum-varnames": [
+                "OrderNew",
+                "OrderAwaitingPayment",
+                "OrderPaid",
+                "OrderAssembling",
+                "OrderShipped",
+                "OrderDelivered",
+                "OrderCancelled",
+                "OrderRefunded"
+            ]
+        },
+        "dozenChairs_internal_models.OrderStatusChange": {
+            "type": "object",
+            "properties": {
+                "changedAt": {
+                    "type": "string"
+                },
+                "changedBy": {
+                    "description": "ChangedBy — ID пользователя; nil — переход выполнила система",
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "from": {
+                    "description": "From пуст у записи о создании заказа",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.OrderStatus"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "to": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.OrderStatus"
+                }
+            }
+        },
+        "dozenChairs_internal_models.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "confirmationUrl": {
+                    "description": "ConfirmationURL — страница оплаты, на которую нужно отправить покупателя",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "externalId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "orderId": {
+                    "type": "string"
+                },
+                "paidAt": {
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Provider — имя провайдера, ExternalID — ID платежа у провайдера",
+                    "type": "string"
+                },
+                "refundedAmount": {
+                    "description": "RefundedAmount — сумма проведённых возвратов",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.PaymentStatus"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "waiting_for_capture",
+                "succeeded",
+                "canceled"
+            ],
+            "x-enum-comments": {
+                "PaymentCanceled": "отменён или не прошёл",
+                "PaymentPending": "создан, покупатель ещё не оплатил",
+                "PaymentSucceeded": "оплачен",
+                "PaymentWaitingForCapture": "деньги заблокированы, ждут списания"
+            },
+            "x-enum-descriptions": [
+                "создан, покупатель ещё не оплатил",
+                "деньги заблокированы, ждут списания",
+                "оплачен",
+                "отменён или не прошёл"
+            ],
+            "x-enum-varnames": [
+                "PaymentPending",
+                "PaymentWaitingForCapture",
+                "PaymentSucceeded",
+                "PaymentCanceled"
+            ]
+        },
+        "dozenChairs_internal_models.PricePoint": {
+            "type": "object",
+            "properties": {
+                "changedAt": {
+                    "type": "string"
+                },
+                "oldPrice": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_models.PriceRange": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "integer"
+                },
+                "min": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_models.Product": {
+            "type": "object",
+            "required": [
+                "id",
+                "title",
+                "type"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "category": {
+                    "description": "название категории; при создании можно передать slug вместо categoryId",
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "время перемещения в корзину",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "можно добавить ` + "`" + `uuid4` + "`" + ` при необходимости",
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.Image"
+                    }
+                },
+                "inStock": {
+                    "type": "boolean"
+                },
+                "includes": {
+                    "description": "только для sets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.IncludeItem"
+                    }
+                },
+                "lowestPrice30Days": {
+                    "description": "LowestPrice30Days — минимальная цена за последние 30 дней по истории цен (только для чтения)",
+                    "type": "integer"
+                },
+                "oldPrice": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "priceRange": {
+                    "description": "диапазон цен по вариантам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.PriceRange"
+                        }
+                    ]
+                },
+                "promotions": {
+                    "description": "Promotions — акции, учтённые в Price (только для чтения, на витрине)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.AppliedPromotion"
+                    }
+                },
+                "publishAt": {
+                    "description": "черновик будет опубликован в это время",
+                    "type": "string"
+                },
+                "setDiscountPercent": {
+                    "description": "SetDiscountPercent — скидка набора в процентах; если задана, цена набора считается как сумма компонентов минус скидка",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "slug": {
+                    "description": "если не указан, генерируется из title",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductStatus"
+                        }
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "product",
+                        "set"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductType"
+                        }
+                    ]
+                },
+                "unitCount": {
+                    "description": "доступный остаток по журналу склада; nil — остаток не учитывается",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "unpublishAt": {
+                    "description": "опубликованный товар уйдёт в архив в это время",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.ProductVariant"
+                    }
+                },
+                "version": {
+                    "description": "растёт при каждом изменении, отдаётся в ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_models.ProductAvailability": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "deliveryDays": {
+                    "description": "DeliveryDays — минимальный срок курьерской доставки в город City; nil — доставка невозможна",
+                    "type": "integer"
+                },
+                "inStock": {
+                    "type": "boolean"
+                },
+                "pickupPoints": {
+                    "description": "PickupPoints — пункты самовывоза, где товар есть (в городе City, если он задан)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.WarehouseAvailability"
+                    }
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "tracked": {
+                    "description": "Tracked — остаток товара учитывается по складам; иначе наличие задано только флагом InStock",
+                    "type": "boolean"
+                },
+                "variantId": {
+                    "type": "string"
+                },
+                "warehouses": {
+                    "description": "Warehouses — склады, на которых товар есть, в порядке резервирования",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.WarehouseAvailability"
+                    }
+                }
+            }
+        },
+        "dozenChairs_internal_models.ProductFacets": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/dozenChairs_internal_models.FacetValue"
+                        }
+                    }
+                },
+                "price": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.PriceRange"
+                }
+            }
+        },
+        "dozenChairs_internal_models.ProductRevision": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.RevisionAction"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.FieldChange"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "snapshot": {
+                    "description": "Snapshot — состояние товара после изменения; по нему выполняется откат",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.Product"
+                        }
+                    ]
+                },
+                "sourceRevisionId": {
+                    "description": "SourceRevisionID — ревизия, к которой выполнен откат (для action = rollback)",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.ProductSearchResult": {
+            "type": "object",
+            "required": [
+                "id",
+                "title",
+                "type"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "category": {
+                    "description": "название категории; при создании можно передать slug вместо categoryId",
+                    "type": "string"
+                },
+                "categoryId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "время перемещения в корзину",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "highlight": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.SearchHighlight"
+                },
+                "id": {
+                    "description": "можно добавить ` + "`" + `uuid4` + "`" + ` при необходимости",
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.Image"
+                    }
+                },
+                "inStock": {
+                    "type": "boolean"
+                },
+                "includes": {
+                    "description": "только для sets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.IncludeItem"
+                    }
+                },
+                "lowestPrice30Days": {
+                    "description": "LowestPrice30Days — минимальная цена за последние 30 дней по истории цен (только для чтения)",
+                    "type": "integer"
+                },
+                "oldPrice": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "priceRange": {
+                    "description": "диапазон цен по вариантам",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.PriceRange"
+                        }
+                    ]
+                },
+                "promotions": {
+                    "description": "Promotions — акции, учтённые в Price (только для чтения, на витрине)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.AppliedPromotion"
+                    }
+                },
+                "publishAt": {
+                    "description": "черновик будет опубликован в это время",
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "setDiscountPercent": {
+                    "description": "SetDiscountPercent — скидка набора в процентах; если задана, цена набора считается как сумма компонентов минус скидка",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "slug": {
+                    "description": "если не указан, генерируется из title",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductStatus"
+                        }
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "product",
+                        "set"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductType"
+                        }
+                    ]
+                },
+                "unitCount": {
+                    "description": "доступный остаток по журналу склада; nil — остаток не учитывается",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "unpublishAt": {
+                    "description": "опубликованный товар уйдёт в архив в это время",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.ProductVariant"
+                    }
+                },
+                "version": {
+                    "description": "растёт при каждом изменении, отдаётся в ETag",
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_models.ProductStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "published",
+                "archived"
+            ],
+            "x-enum-varnames": [
+                "StatusDraft",
+                "StatusPublished",
+                "StatusArchived"
+            ]
+        },
+        "dozenChairs_internal_models.ProductType": {
+            "type": "string",
+            "enum": [
+                "product",
+                "set"
+            ],
+            "x-enum-varnames": [
+                "TypeProduct",
+                "TypeSet"
+            ]
+        },
+        "dozenChairs_internal_models.ProductVariant": {
+            "type": "object",
+            "required": [
+                "options",
+                "sku"
+            ],
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.Image"
+                    }
+                },
+                "inStock": {
+                    "type": "boolean"
+                },
+                "oldPrice": {
+                    "description": "если не задана — старая цена товара",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "options": {
+                    "description": "например, {\"color\": \"белый\", \"fabric\": \"велюр\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "price": {
+                    "description": "если не задана — цена товара",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "sortOrder": {
+                    "type": "integer"
+                },
+                "unitCount": {
+                    "description": "доступный остаток по журналу склада; при создании — начальный",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "dozenChairs_internal_models.PromoCode": {
+            "type": "object",
+            "required": [
+                "code",
+                "discountType",
+                "discountValue"
+            ],
+            "properties": {
+                "categoryIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discountType": {
+                    "enum": [
+                        "percent",
+                        "fixed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.DiscountType"
+                        }
+                    ]
+                },
+                "discountValue": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "minOrderAmount": {
+                    "description": "MinOrderAmount — минимальная сумма заказа (с учётом акций, без промокода)",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "perUserLimit": {
+                    "type": "integer"
+                },
+                "productIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "usageLimit": {
+                    "description": "UsageLimit — сколько раз код можно применить всего, PerUserLimit — одному покупателю; nil — без ограничения",
+                    "type": "integer"
+                },
+                "usedCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dozenChairs_internal_models.PromoCodeQuote": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
+                },
+                "discount": {
+                    "type": "integer"
+                },
+                "eligibleSubtotal": {
+                    "description": "EligibleSubtotal — сумма позиций, на которые действует промокод",
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dozenChairs_internal_models.PromoCodeQuoteItem"
+                    }
+                },
+                "subtotal": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "dozenChairs_internal_dto.AuthResponse": {
+        "dozenChairs_internal_models.PromoCodeQuoteItem": {
             "type": "object",
             "properties": {
-                "accessToken": {
+                "discount": {
+                    "type": "integer"
+                },
+                "eligible": {
+                    "type": "boolean"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "productId": {
                     "type": "string"
                 },
-                "refreshToken": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/dozenChairs_internal_dto.UserResponse"
+                "total": {
+                    "type": "integer"
+                },
+                "variantId": {
+                    "type": "string"
                 }
             }
         },
-        "dozenChairs_internal_dto.ErrorResponse": {
+        "dozenChairs_internal_models.Promotion": {
             "type": "object",
+            "required": [
+                "discountType",
+                "discountValue",
+                "name"
+            ],
             "properties": {
-                "message": {
+                "categoryIds": {
+                    "description": "включая подкатегории",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discountType": {
+                    "enum": [
+                        "percent",
+                        "fixed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.DiscountType"
+                        }
+                    ]
+                },
+                "discountValue": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Priority — акции с большим приоритетом применяются первыми",
+                    "type": "integer"
+                },
+                "productIds": {
+                    "description": "товары и наборы",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "productType": {
+                    "enum": [
+                        "product",
+                        "set"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dozenChairs_internal_models.ProductType"
+                        }
+                    ]
+                },
+                "stackable": {
+                    "description": "Stackable — акция суммируется с другими суммируемыми акциями;\nнесуммируемая акция с наибольшим приоритетом действует одна",
+                    "type": "boolean"
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "dozenChairs_internal_dto.LoginRequest": {
+        "dozenChairs_internal_models.PromotionPreviewItem": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "discountPrice": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "productId": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.ProductType"
                 }
             }
         },
-        "dozenChairs_internal_dto.RegisterRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
+        "dozenChairs_internal_models.RevisionAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete",
+                "restore",
+                "rollback",
+                "image_added",
+                "image_deleted"
             ],
+            "x-enum-varnames": [
+                "RevisionCreate",
+                "RevisionUpdate",
+                "RevisionDelete",
+                "RevisionRestore",
+                "RevisionRollback",
+                "RevisionImageAdded",
+                "RevisionImageDeleted"
+            ]
+        },
+        "dozenChairs_internal_models.SearchHighlight": {
+            "type": "object",
             "properties": {
-                "email": {
+                "description": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
-                },
-                "username": {
-                    "type": "string",
-                    "minLength": 3
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "dozenChairs_internal_dto.UserResponse": {
+        "dozenChairs_internal_models.StockLevel": {
             "type": "object",
             "properties": {
-                "email": {
+                "available": {
+                    "type": "integer"
+                },
+                "onHand": {
+                    "description": "OnHand — единиц на складе, Reserved — из них зарезервировано под неоплаченные заказы,\nAvailable — доступно для заказа",
+                    "type": "integer"
+                },
+                "productId": {
                     "type": "string"
                 },
-                "id": {
+                "reserved": {
+                    "type": "integer"
+                },
+                "updatedAt": {
                     "type": "string"
                 },
-                "name": {
+                "variantId": {
                     "type": "string"
                 },
-                "role": {
+                "warehouseId": {
                     "type": "string"
                 }
             }
         },
-        "dozenChairs_internal_models.Image": {
+        "dozenChairs_internal_models.StockMovement": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "actorId": {
+                    "description": "ActorID — ID пользователя; nil — движение выполнила система",
                     "type": "string"
                 },
-                "filename": {
+                "createdAt": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "product_id": {
-                    "type": "string"
+                "onHandAfter": {
+                    "description": "OnHandAfter и ReservedAfter — остаток после движения",
+                    "type": "integer"
                 },
-                "url": {
+                "orderId": {
                     "type": "string"
-                }
-            }
-        },
-        "dozenChairs_internal_models.IncludeItem": {
-            "type": "object",
-            "required": [
-                "productId",
-                "quantity"
-            ],
-            "properties": {
+                },
                 "productId": {
                     "type": "string"
                 },
                 "quantity": {
                     "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reservedAfter": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.StockMovementType"
+                },
+                "variantId": {
+                    "type": "string"
+                },
+                "warehouseId": {
+                    "description": "WarehouseID — склад, остаток которого изменился",
+                    "type": "string"
                 }
             }
         },
-        "dozenChairs_internal_models.Product": {
+        "dozenChairs_internal_models.StockMovementType": {
+            "type": "string",
+            "enum": [
+                "receipt",
+                "reservation",
+                "release",
+                "sale",
+                "return",
+                "adjustment"
+            ],
+            "x-enum-comments": {
+                "StockAdjustment": "ручная корректировка (инвентаризация, брак)",
+                "StockReceipt": "поступление на склад",
+                "StockRelease": "снятие резерва: заказ отменён или резерв истёк",
+                "StockReservation": "резерв под оформленный заказ",
+                "StockReturn": "возврат товара на склад",
+                "StockSale": "продажа: заказ оплачен, резерв списан со склада"
+            },
+            "x-enum-descriptions": [
+                "поступление на склад",
+                "резерв под оформленный заказ",
+                "снятие резерва: заказ отменён или резерв истёк",
+                "продажа: заказ оплачен, резерв списан со склада",
+                "возврат товара на склад",
+                "ручная корректировка (инвентаризация, брак)"
+            ],
+            "x-enum-varnames": [
+                "StockReceipt",
+                "StockReservation",
+                "StockRelease",
+                "StockSale",
+                "StockReturn",
+                "StockAdjustment"
+            ]
+        },
+        "dozenChairs_internal_models.Warehouse": {
             "type": "object",
             "required": [
-                "category",
-                "id",
-                "slug",
-                "title",
-                "type"
+                "city",
+                "code",
+                "kind",
+                "name"
             ],
             "properties": {
-                "attributes": {
-                    "type": "object",
-                    "additionalProperties": true
+                "active": {
+                    "description": "Active — склад работает; остатки неактивного склада не продаются",
+                    "type": "boolean"
                 },
-                "category": {
+                "address": {
                     "type": "string"
                 },
-                "createdAt": {
+                "city": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "code": {
+                    "type": "string",
+                    "maxLength": 50
                 },
-                "id": {
-                    "description": "можно добавить ` + "`" + `uuid4` + "`" + ` при необходимости",
+                "createdAt": {
                     "type": "string"
                 },
-                "images": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dozenChairs_internal_models.Image"
-                    }
-                },
-                "inStock": {
-                    "type": "boolean"
-                },
-                "includes": {
-                    "description": "только для sets",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dozenChairs_internal_models.IncludeItem"
-                    }
-                },
-                "oldPrice": {
-                    "type": "integer",
-                    "minimum": 0
-                },
-                "price": {
+                "deliveryDays": {
+                    "description": "DeliveryDays — срок курьерской доставки по городу склада, IntercityDeliveryDays — в другие города;\nnil — склад туда не отгружает",
                     "type": "integer",
                     "minimum": 0
                 },
-                "slug": {
-                    "description": "можно добавить custom slug-валидацию",
+                "id": {
                     "type": "string"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "title": {
-                    "type": "string"
+                "intercityDeliveryDays": {
+                    "type": "integer",
+                    "minimum": 0
                 },
-                "type": {
+                "kind": {
                     "enum": [
-                        "product",
-                        "set"
+                        "warehouse",
+                        "showroom"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/dozenChairs_internal_models.ProductType"
+                            "$ref": "#/definitions/dozenChairs_internal_models.WarehouseKind"
                         }
                     ]
                 },
-                "unitCount": {
+                "name": {
+                    "type": "string"
+                },
+                "pickup": {
+                    "description": "Pickup — здесь можно забрать заказ самовывозом; PickupDays — через сколько дней заказ готов к выдаче",
+                    "type": "boolean"
+                },
+                "pickupDays": {
                     "type": "integer",
                     "minimum": 0
                 },
+                "priority": {
+                    "description": "Priority — склады с меньшим приоритетом резервируют товар под заказ первыми",
+                    "type": "integer"
+                },
                 "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "dozenChairs_internal_models.ProductType": {
+        "dozenChairs_internal_models.WarehouseAvailability": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "deliveryDays": {
+                    "description": "DeliveryDays — срок доставки с этого склада в запрошенный город; nil — доставки нет или город не задан",
+                    "type": "integer"
+                },
+                "warehouse": {
+                    "$ref": "#/definitions/dozenChairs_internal_models.Warehouse"
+                }
+            }
+        },
+        "dozenChairs_internal_models.WarehouseKind": {
             "type": "string",
             "enum": [
-                "product",
-                "set"
+                "warehouse",
+                "showroom"
+            ],
+            "x-enum-comments": {
+                "WarehouseShowroom": "шоурум: выставочные образцы, обычно с самовывозом",
+                "WarehouseStock": "склад"
+            },
+            "x-enum-descriptions": [
+                "склад",
+                "шоурум: выставочные образцы, обычно с самовывозом"
             ],
             "x-enum-varnames": [
-                "TypeProduct",
-                "TypeSet"
+                "WarehouseStock",
+                "WarehouseShowroom"
             ]
         },
         "dozenChairs_pkg_httphelper.APIResponse": {
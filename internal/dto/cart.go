@@ -0,0 +1,13 @@
+package dto
+
+// CartItemRequest — товар, который покупатель кладёт в корзину; для товара с вариантами нужен variantId
+type CartItemRequest struct {
+	ProductID string  `json:"productId" validate:"required"`
+	VariantID *string `json:"variantId,omitempty" validate:"omitempty,uuid"`
+	Quantity  int     `json:"quantity" validate:"required,gt=0,lte=999"`
+}
+
+// CartQuantityRequest — новое количество товара в позиции корзины
+type CartQuantityRequest struct {
+	Quantity int `json:"quantity" validate:"required,gt=0,lte=999"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "dozenChairs/internal/models"
+
+// CheckoutRequest — контакты и доставка для оформления заказа из корзины покупателя
+type CheckoutRequest struct {
+	CustomerName   string                `json:"customerName" validate:"required,max=200"`
+	Email          string                `json:"email" validate:"required,email"`
+	Phone          string                `json:"phone" validate:"required,min=5,max=32"`
+	DeliveryMethod models.DeliveryMethod `json:"deliveryMethod" validate:"required,oneof=courier pickup"`
+	City           string                `json:"city" validate:"required_if=DeliveryMethod courier,max=200"`
+	// Address обязателен для доставки курьером
+	Address   string `json:"address" validate:"required_if=DeliveryMethod courier,max=500"`
+	Comment   string `json:"comment" validate:"max=1000"`
+	PromoCode string `json:"promoCode,omitempty" validate:"max=64"`
+	// PickupPointID — склад или шоурум для самовывоза; город и адрес заказа берутся из него
+	PickupPointID string `json:"pickupPointId,omitempty" validate:"required_if=DeliveryMethod pickup,omitempty,uuid"`
+}
+
+// OrderTransitionRequest — новый статус заказа и комментарий к переходу
+type OrderTransitionRequest struct {
+	Status  models.OrderStatus `json:"status" validate:"required"`
+	Comment string             `json:"comment" validate:"max=1000"`
+}
+
+// OrderCancelRequest — причина отмены заказа покупателем
+type OrderCancelRequest struct {
+	Comment string `json:"comment" validate:"max=1000"`
+}
+
+// OrderRefundRequest — причина возврата денег за заказ
+type OrderRefundRequest struct {
+	Comment string `json:"comment" validate:"max=1000"`
+}
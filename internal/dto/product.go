@@ -0,0 +1,19 @@
+package dto
+
+import "dozenChairs/internal/models"
+
+// ProductListMeta — метаданные ответа со списком товаров
+type ProductListMeta struct {
+	Total      int                   `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	Facets     *models.ProductFacets `json:"facets,omitempty"`
+}
+
+// ListMeta — метаданные постраничного списка с пагинацией по смещению
+type ListMeta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "dozenChairs/internal/models"
+
+// PromoCodeValidateRequest — промокод и позиции заказа, к которым его нужно применить
+type PromoCodeValidateRequest struct {
+	Code  string                  `json:"code" validate:"required"`
+	Items []models.OrderItemInput `json:"items" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "dozenChairs/internal/models"
+
+// StockMovementRequest — ручное движение по складу. Quantity у поступления и возврата — число единиц,
+// у корректировки — изменение остатка со знаком.
+type StockMovementRequest struct {
+	WarehouseID string                   `json:"warehouseId" validate:"required,uuid"`
+	VariantID   *string                  `json:"variantId" validate:"omitempty,uuid"`
+	Type        models.StockMovementType `json:"type" validate:"required,oneof=receipt return adjustment"`
+	Quantity    int                      `json:"quantity" validate:"required"`
+	Reason      string                   `json:"reason" validate:"required,max=500"`
+}
@@ -24,16 +24,34 @@ type AuthHandler struct {
 	service    services.AuthService
 	logger     logger.Logger
 	jwtManager *auth.JWTManager
+	carts      services.CartService
+	cartCookie CartCookie
 }
 
-func NewAuthHandler(s services.AuthService, l logger.Logger, jwtManager *auth.JWTManager) *AuthHandler {
+func NewAuthHandler(s services.AuthService, l logger.Logger, jwtManager *auth.JWTManager, carts services.CartService, cartCookie CartCookie) *AuthHandler {
 	return &AuthHandler{
 		service:    s,
 		logger:     l,
 		jwtManager: jwtManager,
+		carts:      carts,
+		cartCookie: cartCookie,
 	}
 }
 
+// mergeGuestCart переносит гостевую корзину из cookie в корзину вошедшего пользователя.
+// Ошибка переноса не мешает входу: cookie остаётся, и перенос повторится при следующем входе.
+func (h *AuthHandler) mergeGuestCart(w http.ResponseWriter, r *http.Request, userID string) {
+	cartID := h.cartCookie.Read(r)
+	if cartID == "" {
+		return
+	}
+	if err := h.carts.Merge(r.Context(), cartID, userID); err != nil {
+		h.logger.Error("failed to merge guest cart", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+	h.cartCookie.Clear(w)
+}
+
 // Register godoc
 // @Summary      Регистрация пользователя
 // @Description  Создаёт нового пользователя и возвращает access и refresh токены
@@ -86,6 +104,8 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Expires:  time.Now().Add(h.jwtManager.RefreshTTL),
 	})
 
+	h.mergeGuestCart(w, r, user.ID)
+
 	// Лог и ответ
 	h.logger.Info("user registered", zap.String("id", user.ID), zap.String("email", user.Email))
 	httphelper.WriteSuccess(w, http.StatusOK, map[string]interface{}{
@@ -103,7 +123,8 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 // Login godoc
 // @Summary      Авторизация пользователя
-// @Description  Принимает email и пароль, возвращает access и refresh токены
+// @Description  Принимает email и пароль, возвращает access и refresh токены.
+// @Description  Гостевая корзина из cookie cart_id переносится в корзину пользователя.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -143,6 +164,8 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Expires:  time.Now().Add(h.jwtManager.RefreshTTL),
 	})
 
+	h.mergeGuestCart(w, r, user.ID)
+
 	// return access token
 	h.logger.Info("user logged in", zap.String("id", user.ID))
 	httphelper.WriteSuccess(w, http.StatusOK, map[string]interface{}{
@@ -408,6 +431,8 @@ func (h *AuthHandler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
 		Expires:  time.Now().Add(h.jwtManager.RefreshTTL),
 	})
 
+	h.mergeGuestCart(w, r, user.ID)
+
 	httphelper.WriteSuccess(w, http.StatusOK, map[string]interface{}{
 		"access_token": accessToken,
 		"user": map[string]interface{}{
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/middlewares"
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/security"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// cartCookieName — cookie с подписанным ID гостевой корзины
+const cartCookieName = "cart_id"
+
+// CartCookie читает и пишет cookie гостевой корзины покупателя
+type CartCookie struct {
+	Secret string
+	TTL    time.Duration
+}
+
+// Read возвращает ID гостевой корзины из cookie или пустую строку, если cookie нет или подпись неверна
+func (c CartCookie) Read(r *http.Request) string {
+	cookie, err := r.Cookie(cartCookieName)
+	if err != nil {
+		return ""
+	}
+	id, ok := security.Verify(cookie.Value, c.Secret)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+func (c CartCookie) Set(w http.ResponseWriter, cartID string) error {
+	value, err := security.Sign(cartID, c.Secret)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     cartCookieName,
+		Value:    value,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+		MaxAge:   int(c.TTL.Seconds()),
+	})
+	return nil
+}
+
+func (c CartCookie) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     cartCookieName,
+		Value:    "",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+type CartHandler struct {
+	service services.CartService
+	logger  logger.Logger
+	cookie  CartCookie
+}
+
+func NewCartHandler(s services.CartService, l logger.Logger, cookie CartCookie) *CartHandler {
+	return &CartHandler{
+		service: s,
+		logger:  l,
+		cookie:  cookie,
+	}
+}
+
+// Get godoc
+// @Summary      Корзина покупателя
+// @Description  Корзина вошедшего пользователя или гостевая корзина из cookie cart_id.
+// @Description  Цены и суммы пересчитываются по текущим ценам витрины с учётом акций;
+// @Description  позиции, которые нельзя заказать (сняты с продажи, нет в наличии), перечислены в warnings и в суммы не входят.
+// @Tags         Cart
+// @Produce      json
+// @Success      200  {object}  httphelper.APIResponse{data=models.Cart}
+// @Failure      401  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/cart [get]
+func (h *CartHandler) Get(w http.ResponseWriter, r *http.Request) {
+	cart, err := h.service.Get(r.Context(), h.owner(r))
+	if err != nil {
+		h.writeServiceError(w, "failed to get cart", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, cart)
+}
+
+// AddItem godoc
+// @Summary      Добавить товар в корзину
+// @Description  Кладёт товар, набор или вариант товара в корзину; если он уже в корзине, количество увеличивается.
+// @Description  Гостю при первом добавлении выдаётся cookie cart_id; после входа гостевая корзина переносится в корзину пользователя.
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param        item  body      dto.CartItemRequest  true  "Товар и количество"
+// @Success      200   {object}  httphelper.APIResponse{data=models.Cart}
+// @Failure      400   {object}  httphelper.APIResponse
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      409   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/cart/items [post]
+func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	var req dto.CartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if err := validation.ValidateStruct(req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	owner := h.owner(r)
+	cart, err := h.service.AddItem(r.Context(), owner, req)
+	if err != nil {
+		h.writeServiceError(w, "failed to add cart item", err)
+		return
+	}
+	h.keepGuestCookie(w, owner, cart.ID)
+	httphelper.WriteSuccess(w, http.StatusOK, cart)
+}
+
+// UpdateItem godoc
+// @Summary      Изменить количество в корзине
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                   true  "ID позиции корзины"
+// @Param        item  body      dto.CartQuantityRequest  true  "Новое количество"
+// @Success      200   {object}  httphelper.APIResponse{data=models.Cart}
+// @Failure      400   {object}  httphelper.APIResponse
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/cart/items/{id} [put]
+func (h *CartHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	var req dto.CartQuantityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if err := validation.ValidateStruct(req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	owner := h.owner(r)
+	cart, err := h.service.UpdateItem(r.Context(), owner, chi.URLParam(r, "id"), req.Quantity)
+	if err != nil {
+		h.writeServiceError(w, "failed to update cart item", err)
+		return
+	}
+	h.keepGuestCookie(w, owner, cart.ID)
+	httphelper.WriteSuccess(w, http.StatusOK, cart)
+}
+
+// RemoveItem godoc
+// @Summary      Удалить позицию из корзины
+// @Tags         Cart
+// @Produce      json
+// @Param        id   path      string  true  "ID позиции корзины"
+// @Success      200  {object}  httphelper.APIResponse{data=models.Cart}
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/cart/items/{id} [delete]
+func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	owner := h.owner(r)
+	cart, err := h.service.RemoveItem(r.Context(), owner, chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to remove cart item", err)
+		return
+	}
+	h.keepGuestCookie(w, owner, cart.ID)
+	httphelper.WriteSuccess(w, http.StatusOK, cart)
+}
+
+// Clear godoc
+// @Summary      Очистить корзину
+// @Tags         Cart
+// @Success      204  "No Content"
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/cart [delete]
+func (h *CartHandler) Clear(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Clear(r.Context(), h.owner(r)); err != nil {
+		h.writeServiceError(w, "failed to clear cart", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// owner определяет корзину запроса: пользователя, если он вошёл, иначе гостевую из cookie
+func (h *CartHandler) owner(r *http.Request) services.CartOwner {
+	if userID := middlewares.CurrentUserID(r.Context()); userID != "" {
+		return services.CartOwner{UserID: userID}
+	}
+	return services.CartOwner{CartID: h.cookie.Read(r)}
+}
+
+// keepGuestCookie выдаёт гостю cookie новой корзины и продлевает срок действия существующей
+func (h *CartHandler) keepGuestCookie(w http.ResponseWriter, owner services.CartOwner, cartID string) {
+	if owner.UserID == "" && cartID != "" {
+		if err := h.cookie.Set(w, cartID); err != nil {
+			h.logger.Error("failed to sign guest cart cookie", zap.Error(err))
+		}
+	}
+}
+
+func (h *CartHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrCartItemNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Cart item not found")
+	case errors.Is(err, services.ErrCartProductUnavailable), errors.Is(err, services.ErrVariantNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrCartOutOfStock):
+		httphelper.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrCartVariantRequired):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process cart")
+	}
+}
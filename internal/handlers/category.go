@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type CategoryHandler struct {
+	service services.CategoryService
+	logger  logger.Logger
+}
+
+func NewCategoryHandler(s services.CategoryService, l logger.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		service: s,
+		logger:  l,
+	}
+}
+
+// GetAll godoc
+// @Summary      Получить список категорий
+// @Description  Возвращает все категории плоским списком, упорядоченным по sortOrder и названию
+// @Tags         Categories
+// @Produce      json
+// @Success      200  {array}  models.Category
+// @Failure      500  {object} httphelper.APIResponse
+// @Router       /api/v1/categories [get]
+func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get categories", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load categories")
+		return
+	}
+
+	h.logger.Info("categories fetched", zap.Int("count", len(categories)))
+	httphelper.WriteSuccess(w, http.StatusOK, categories)
+}
+
+// GetTree godoc
+// @Summary      Получить дерево категорий
+// @Description  Возвращает корневые категории с вложенными подкатегориями (`children`)
+// @Tags         Categories
+// @Produce      json
+// @Success      200  {array}  models.Category
+// @Failure      500  {object} httphelper.APIResponse
+// @Router       /api/v1/categories/tree [get]
+func (h *CategoryHandler) GetTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.service.GetTree(r.Context())
+	if err != nil {
+		h.logger.Error("failed to build category tree", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load categories")
+		return
+	}
+
+	httphelper.WriteSuccess(w, http.StatusOK, tree)
+}
+
+// GetBySlug godoc
+// @Summary      Получить категорию по slug
+// @Tags         Categories
+// @Produce      json
+// @Param        slug  path      string  true  "Slug категории"
+// @Success      200   {object}  models.Category
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/categories/{slug} [get]
+func (h *CategoryHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	c, err := h.service.GetBySlug(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			httphelper.WriteError(w, http.StatusNotFound, "Category not found")
+			return
+		}
+		h.logger.Error("failed to get category", zap.String("slug", slug), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load category")
+		return
+	}
+
+	httphelper.WriteSuccess(w, http.StatusOK, c)
+}
+
+// Create godoc
+// @Summary      Создать категорию
+// @Description  Только для админов. Для вложенной категории укажите `parentId`.
+// @Tags         Categories
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        category  body      models.Category  true  "Данные категории"
+// @Success      201       {object}  models.Category
+// @Failure      400       {object}  httphelper.APIResponse
+// @Failure      409       {object}  httphelper.APIResponse
+// @Failure      500       {object}  httphelper.APIResponse
+// @Router       /api/v1/categories [post]
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var c models.Category
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	if err := validation.ValidateStruct(c); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &c); err != nil {
+		h.writeServiceError(w, "category creation failed", err)
+		return
+	}
+
+	h.logger.Info("category created", zap.String("id", c.ID), zap.String("slug", c.Slug))
+	httphelper.WriteSuccess(w, http.StatusCreated, c)
+}
+
+// Update godoc
+// @Summary      Обновить категорию
+// @Description  Только для админов. Позволяет переименовать категорию, сменить slug или перенести её под другого родителя.
+// @Tags         Categories
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string           true  "ID категории"
+// @Param        category  body      models.Category  true  "Обновлённые данные категории"
+// @Success      200       {object}  models.Category
+// @Failure      400       {object}  httphelper.APIResponse
+// @Failure      404       {object}  httphelper.APIResponse
+// @Failure      409       {object}  httphelper.APIResponse
+// @Failure      500       {object}  httphelper.APIResponse
+// @Router       /api/v1/categories/{id} [put]
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var c models.Category
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validation.ValidateStruct(c); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, &c); err != nil {
+		h.writeServiceError(w, "category update failed", err)
+		return
+	}
+
+	h.logger.Info("category updated", zap.String("id", id))
+	httphelper.WriteSuccess(w, http.StatusOK, c)
+}
+
+// Delete godoc
+// @Summary      Удалить категорию
+// @Description  Только для админов. Категорию с подкатегориями или товарами удалить нельзя.
+// @Tags         Categories
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "ID категории"
+// @Success      204  "No Content"
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      409  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/categories/{id} [delete]
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.writeServiceError(w, "category deletion failed", err)
+		return
+	}
+
+	h.logger.Info("category deleted", zap.String("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CategoryHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Category not found")
+	case errors.Is(err, services.ErrParentCategoryNotFound), errors.Is(err, services.ErrCategoryParent):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrCategoryHasChildren), errors.Is(err, services.ErrCategoryHasProducts):
+		httphelper.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, repository.ErrAlreadyExists):
+		httphelper.WriteError(w, http.StatusConflict, "Category slug already exists")
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to save category")
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"dozenChairs/internal/models"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var errBadIfMatch = errors.New("invalid If-Match header")
+
+// setProductETag отдаёт версию товара в заголовке ETag
+func setProductETag(w http.ResponseWriter, p *models.Product) {
+	w.Header().Set("ETag", `"`+strconv.Itoa(p.Version)+`"`)
+}
+
+// parseIfMatch читает ожидаемую версию товара из If-Match.
+// "*" означает любую версию и возвращается как 0; ok = false, если заголовка нет.
+func parseIfMatch(r *http.Request) (version int, ok bool, err error) {
+	raw := strings.TrimSpace(r.Header.Get("If-Match"))
+	if raw == "" {
+		return 0, false, nil
+	}
+	if raw == "*" {
+		return 0, true, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(raw, "W/"), `"`)
+	version, err = strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, true, errBadIfMatch
+	}
+	return version, true, nil
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type FeedHandler struct {
+	service services.FeedService
+	logger  logger.Logger
+	maxAge  time.Duration // сколько клиенты и прокси могут кэшировать фид
+}
+
+func NewFeedHandler(s services.FeedService, l logger.Logger, maxAge time.Duration) *FeedHandler {
+	return &FeedHandler{
+		service: s,
+		logger:  l,
+		maxAge:  maxAge,
+	}
+}
+
+// Get godoc
+// @Summary      Фид каталога для маркетплейса
+// @Description  Отдаёт сохранённый фид (yandex.yml — YML для Яндекс Маркета, google.xml — RSS для Google Merchant Center). Устаревший фид перегенерируется при запросе.
+// @Description  Поддерживаются условные запросы (If-Modified-Since) и Range.
+// @Tags         Feeds
+// @Produce      xml
+// @Param        name  path  string  true  "Имя фида (yandex.yml, google.xml)"
+// @Success      200
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/feeds/{name} [get]
+func (h *FeedHandler) Get(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	f, err := h.service.Open(r.Context(), name)
+	if err != nil {
+		h.writeError(w, name, err)
+		return
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		h.writeError(w, name, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	http.ServeContent(w, r, name, stat.ModTime(), f)
+}
+
+// Regenerate godoc
+// @Summary      Перегенерировать фид (админка)
+// @Description  Только для админов. Строит фид заново, не дожидаясь истечения срока кэша. В skipped перечислены товары, пропущенные из-за незаполненных обязательных полей.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        name  path      string  true  "Имя фида (yandex.yml, google.xml)"
+// @Success      200   {object}  httphelper.APIResponse{data=models.FeedInfo}
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/feeds/{name}/regenerate [post]
+func (h *FeedHandler) Regenerate(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	info, err := h.service.Regenerate(r.Context(), name)
+	if err != nil {
+		h.writeError(w, name, err)
+		return
+	}
+
+	h.logger.Info("feed regenerated", zap.String("feed", name), zap.Int("offers", info.Offers), zap.Int64("size", info.Size))
+	httphelper.WriteSuccess(w, http.StatusOK, info)
+}
+
+func (h *FeedHandler) writeError(w http.ResponseWriter, name string, err error) {
+	if errors.Is(err, services.ErrFeedNotFound) {
+		httphelper.WriteError(w, http.StatusNotFound, "Feed not found")
+		return
+	}
+	h.logger.Error("feed generation failed", zap.String("feed", name), zap.Error(err))
+	httphelper.WriteError(w, http.StatusInternalServerError, "Failed to generate feed")
+}
@@ -5,6 +5,7 @@ import (
 	"dozenChairs/internal/models"
 	"dozenChairs/internal/services"
 	"dozenChairs/pkg/httphelper"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"io"
@@ -27,9 +28,11 @@ func NewImageHandler(s services.ImageService) *ImageHandler {
 // @Accept multipart/form-data
 // @Produce json
 // @Param product_id formData string true "ID товара"
+// @Param variant_id formData string false "ID варианта товара, если изображение относится к варианту"
 // @Param images formData file true "Файлы изображений (можно несколько)"
 // @Success 201 {array} models.Image
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Вариант не найден или принадлежит другому товару"
 // @Failure 500 {object} map[string]string
 // @Router /api/v1//upload [post]
 func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
@@ -40,6 +43,18 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 	}
 
 	productID := r.FormValue("product_id")
+	var variantID *string
+	if v := r.FormValue("variant_id"); v != "" {
+		if err := h.service.CheckVariant(r.Context(), productID, v); err != nil {
+			if errors.Is(err, services.ErrVariantNotFound) {
+				httphelper.WriteError(w, http.StatusNotFound, "Variant not found")
+				return
+			}
+			httphelper.WriteError(w, http.StatusInternalServerError, "Failed to check variant")
+			return
+		}
+		variantID = &v
+	}
 	files := r.MultipartForm.File["images"]
 	if len(files) == 0 {
 		httphelper.WriteError(w, http.StatusBadRequest, "No images provided")
@@ -82,6 +97,7 @@ func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		image := models.Image{
 			ID:        uuid.NewString(),
 			ProductID: productID,
+			VariantID: variantID,
 			URL:       "/uploads/" + filename,
 			Filename:  filename,
 		}
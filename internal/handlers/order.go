@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/middlewares"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type OrderHandler struct {
+	service  services.OrderService
+	payments services.PaymentService
+	logger   logger.Logger
+}
+
+func NewOrderHandler(s services.OrderService, p services.PaymentService, l logger.Logger) *OrderHandler {
+	return &OrderHandler{
+		service:  s,
+		payments: p,
+		logger:   l,
+	}
+}
+
+// Checkout godoc
+// @Summary      Оформить заказ
+// @Description  Оформляет заказ из корзины покупателя: позиции, названия и цены (с акциями и промокодом) сохраняются снимком,
+// @Description  корзина очищается. Заказ создаётся в статусе new, товары резервируются на складе до reservedUntil:
+// @Description  если заказ не оплачен к этому времени, он отменяется, резерв снимается, а применённый промокод освобождается.
+// @Description  Товары резервируются на складах, отгружающих в город заказа (сначала в самом городе), а при самовывозе —
+// @Description  в выбранном пункте pickupPointId (список — GET /pickup-points); город и адрес заказа берутся из пункта.
+// @Description  Если в корзине есть позиции, которые нельзя заказать, товара не хватает на складе
+// @Description  или корзина изменилась во время оформления, возвращается 409.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.CheckoutRequest  true  "Контакты, доставка и промокод"
+// @Success      201      {object}  httphelper.APIResponse{data=models.Order}
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      401      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/orders [post]
+func (h *OrderHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	var req dto.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if err := validation.ValidateStruct(req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.service.Checkout(r.Context(), middlewares.CurrentUserID(r.Context()), req)
+	if err != nil {
+		h.writeServiceError(w, "checkout failed", err)
+		return
+	}
+
+	h.logger.Info("order created", zap.String("id", order.ID), zap.Int64("number", order.Number), zap.Int("total", order.Total))
+	httphelper.WriteSuccess(w, http.StatusCreated, order)
+}
+
+// GetMine godoc
+// @Summary      Мои заказы
+// @Description  Заказы текущего пользователя, новые первыми.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        limit   query     int  false  "Лимит (по умолчанию 20, не больше 100)"
+// @Param        offset  query     int  false  "Смещение (по умолчанию 0)"
+// @Success      200     {object}  httphelper.APIResponse{data=[]models.Order,meta=dto.ListMeta}
+// @Failure      401     {object}  httphelper.APIResponse
+// @Failure      500     {object}  httphelper.APIResponse
+// @Router       /api/v1/orders [get]
+func (h *OrderHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	limit, offset := orderPage(r.URL.Query())
+
+	orders, total, err := h.service.ListForUser(r.Context(), middlewares.CurrentUserID(r.Context()), limit, offset)
+	if err != nil {
+		h.writeServiceError(w, "failed to get user orders", err)
+		return
+	}
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, orders, dto.ListMeta{Total: total, Limit: limit, Offset: offset})
+}
+
+// GetMineByID godoc
+// @Summary      Мой заказ
+// @Description  Заказ текущего пользователя с историей статусов.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID заказа"
+// @Success      200  {object}  httphelper.APIResponse{data=models.Order}
+// @Failure      401  {object}  httphelper.APIResponse
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/orders/{id} [get]
+func (h *OrderHandler) GetMineByID(w http.ResponseWriter, r *http.Request) {
+	order, err := h.service.GetForUser(r.Context(), middlewares.CurrentUserID(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get order", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, order)
+}
+
+// Cancel godoc
+// @Summary      Отменить заказ
+// @Description  Покупатель может отменить свой заказ, пока он не оплачен (статусы new и awaiting_payment). Резерв товаров снимается, применённый промокод снова становится доступен.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true   "ID заказа"
+// @Param        request  body      dto.OrderCancelRequest  false  "Причина отмены"
+// @Success      200      {object}  httphelper.APIResponse{data=models.Order}
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/orders/{id}/cancel [post]
+func (h *OrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	var req dto.OrderCancelRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+			return
+		}
+		if err := validation.ValidateStruct(req); err != nil {
+			httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	order, err := h.service.Cancel(r.Context(), middlewares.CurrentUserID(r.Context()), chi.URLParam(r, "id"), req.Comment)
+	if err != nil {
+		h.writeServiceError(w, "order cancellation failed", err)
+		return
+	}
+
+	h.logger.Info("order cancelled by customer", zap.String("id", order.ID))
+	httphelper.WriteSuccess(w, http.StatusOK, order)
+}
+
+// AdminGetAll godoc
+// @Summary      Список заказов
+// @Description  Только для админов. Заказы с фильтрами, новые первыми.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        status  query     string  false  "Статус"  Enums(new, awaiting_payment, paid, assembling, shipped, delivered, cancelled, refunded)
+// @Param        userId  query     string  false  "ID покупателя"
+// @Param        from    query     string  false  "Оформлен не раньше (YYYY-MM-DD или RFC 3339)"
+// @Param        to      query     string  false  "Оформлен не позже (YYYY-MM-DD — включая этот день, или RFC 3339)"
+// @Param        q       query     string  false  "Номер заказа или часть имени, email, телефона"
+// @Param        limit   query     int     false  "Лимит (по умолчанию 20, не больше 100)"
+// @Param        offset  query     int     false  "Смещение (по умолчанию 0)"
+// @Success      200     {object}  httphelper.APIResponse{data=[]models.Order,meta=dto.ListMeta}
+// @Failure      400     {object}  httphelper.APIResponse
+// @Failure      500     {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/orders [get]
+func (h *OrderHandler) AdminGetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOrderFilter(r.URL.Query())
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orders, total, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		h.writeServiceError(w, "failed to get orders", err)
+		return
+	}
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, orders, dto.ListMeta{Total: total, Limit: filter.Limit, Offset: filter.Offset})
+}
+
+// AdminGetByID godoc
+// @Summary      Получить заказ
+// @Description  Только для админов. Заказ с историей статусов и списком статусов, в которые его можно перевести.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID заказа"
+// @Success      200  {object}  httphelper.APIResponse{data=models.Order}
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/orders/{id} [get]
+func (h *OrderHandler) AdminGetByID(w http.ResponseWriter, r *http.Request) {
+	order, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get order", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, order)
+}
+
+// Transition godoc
+// @Summary      Сменить статус заказа
+// @Description  Только для админов. Допустимые переходы: new → awaiting_payment | cancelled; awaiting_payment → paid | cancelled;
+// @Description  paid → assembling | refunded; assembling → shipped | refunded; shipped → delivered | refunded; delivered → refunded.
+// @Description  Переход записывается в историю заказа с автором и комментарием. Отмена снимает резерв товаров,
+// @Description  оплата списывает зарезервированные товары со склада, а возврат денег до отгрузки возвращает их на склад.
+// @Description  Заказ с платежом через провайдера вручную в paid и refunded не переводится (409): оплату подтверждает провайдер,
+// @Description  а деньги возвращаются через POST /admin/orders/{id}/refund.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                      true  "ID заказа"
+// @Param        request  body      dto.OrderTransitionRequest  true  "Новый статус"
+// @Success      200      {object}  httphelper.APIResponse{data=models.Order}
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/orders/{id}/transitions [post]
+func (h *OrderHandler) Transition(w http.ResponseWriter, r *http.Request) {
+	var req dto.OrderTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if err := validation.ValidateStruct(req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := h.payments.Transition(r.Context(), chi.URLParam(r, "id"), req.Status, req.Comment)
+	if err != nil {
+		h.writeServiceError(w, "order transition failed", err)
+		return
+	}
+
+	h.logger.Info("order status changed", zap.String("id", order.ID), zap.String("status", string(order.Status)))
+	httphelper.WriteSuccess(w, http.StatusOK, order)
+}
+
+// parseOrderFilter читает фильтры списка заказов из query-строки
+func parseOrderFilter(q url.Values) (repository.OrderFilter, error) {
+	filter := repository.OrderFilter{
+		Status: models.OrderStatus(q.Get("status")),
+		UserID: q.Get("userId"),
+		Query:  strings.TrimSpace(q.Get("q")),
+	}
+	filter.Limit, filter.Offset = orderPage(q)
+
+	for _, bound := range []struct {
+		param string
+		dst   **time.Time
+		end   bool
+	}{{"from", &filter.From, false}, {"to", &filter.To, true}} {
+		v := q.Get(bound.param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			day, dayErr := time.Parse(time.DateOnly, v)
+			if dayErr != nil {
+				return filter, fmt.Errorf("invalid %s: %q", bound.param, v)
+			}
+			// дата без времени в to включает весь день
+			if bound.end {
+				day = day.AddDate(0, 0, 1)
+			}
+			t = day
+		}
+		t = t.UTC()
+		*bound.dst = &t
+	}
+	return filter, nil
+}
+
+// orderPage читает пагинацию списка заказов: по умолчанию 20, не больше 100
+func orderPage(q url.Values) (limit, offset int) {
+	limit = httphelper.ParseInt(q.Get("limit"), 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return limit, max(httphelper.ParseInt(q.Get("offset"), 0), 0)
+}
+
+func (h *OrderHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Order not found")
+	case errors.Is(err, services.ErrCartNotOrderable), errors.Is(err, services.ErrCartChanged),
+		errors.Is(err, services.ErrOrderTransition), errors.Is(err, services.ErrOrderStatusConflict),
+		errors.Is(err, services.ErrOrderNotCancellable), errors.Is(err, services.ErrCartOutOfStock),
+		errors.Is(err, services.ErrPromoCodeExhausted), errors.Is(err, services.ErrPromoCodeUserLimit),
+		errors.Is(err, services.ErrProviderPayment):
+		httphelper.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrCartEmpty), errors.Is(err, services.ErrInvalidOrderStatus),
+		errors.Is(err, services.ErrPromoCodeNotFound), errors.Is(err, services.ErrPromoCodeNotStarted),
+		errors.Is(err, services.ErrPromoCodeExpired), errors.Is(err, services.ErrPromoCodeMinOrder),
+		errors.Is(err, services.ErrPromoCodeNotApplicable), errors.Is(err, services.ErrInvalidOrderItems),
+		errors.Is(err, services.ErrPickupPointNotFound):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process order")
+	}
+}
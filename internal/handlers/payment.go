@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/middlewares"
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/payments"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// maxWebhookBody — предельный размер уведомления платёжного провайдера
+const maxWebhookBody = 1 << 20
+
+type PaymentHandler struct {
+	service services.PaymentService
+	logger  logger.Logger
+	// realIPHeader — заголовок reverse proxy с адресом отправителя уведомления; пустой — адрес соединения
+	realIPHeader string
+}
+
+func NewPaymentHandler(s services.PaymentService, l logger.Logger, realIPHeader string) *PaymentHandler {
+	return &PaymentHandler{
+		service:      s,
+		logger:       l,
+		realIPHeader: realIPHeader,
+	}
+}
+
+// Pay godoc
+// @Summary      Оплатить заказ
+// @Description  Начинает оплату заказа в статусе new или awaiting_payment: заказ переводится в awaiting_payment,
+// @Description  у платёжного провайдера создаётся платёж на сумму заказа. Покупателя нужно отправить на confirmationUrl;
+// @Description  когда провайдер сообщит об оплате, заказ станет paid. Пока прошлый платёж не завершён, возвращается он же,
+// @Description  поэтому повторный запрос не создаёт второй платёж.
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID заказа"
+// @Success      201  {object}  httphelper.APIResponse{data=models.Payment}
+// @Failure      401  {object}  httphelper.APIResponse
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      409  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/orders/{id}/payments [post]
+func (h *PaymentHandler) Pay(w http.ResponseWriter, r *http.Request) {
+	p, err := h.service.Pay(r.Context(), middlewares.CurrentUserID(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "payment creation failed", err)
+		return
+	}
+
+	h.logger.Info("payment started", zap.String("id", p.ID), zap.String("order", p.OrderID), zap.String("status", string(p.Status)))
+	httphelper.WriteSuccess(w, http.StatusCreated, p)
+}
+
+// GetMine godoc
+// @Summary      Оплата моего заказа
+// @Description  Последний платёж по заказу текущего пользователя; статус незавершённого платежа сверяется с провайдером.
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID заказа"
+// @Success      200  {object}  httphelper.APIResponse{data=models.Payment}
+// @Failure      401  {object}  httphelper.APIResponse
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/orders/{id}/payment [get]
+func (h *PaymentHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	p, err := h.service.GetForUser(r.Context(), middlewares.CurrentUserID(r.Context()), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get payment", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, p)
+}
+
+// Webhook godoc
+// @Summary      Уведомление платёжного провайдера
+// @Description  Принимает уведомления о платежах в формате YooKassa. Уведомления YooKassa принимаются только с её адресов
+// @Description  (YOOKASSA_WEBHOOK_IPS; за reverse proxy адрес берётся из заголовка PAYMENT_WEBHOOK_REAL_IP_HEADER).
+// @Description  Уведомление о платеже, которого нет в базе, отклоняется без обращения к провайдеру. Статус платежа
+// @Description  перепроверяется у провайдера, оплаченный заказ переводится в paid; повторные уведомления ничего не меняют.
+// @Description  На ответ не 200 провайдер повторяет уведомление позже. Принимаются уведомления только настроенного провайдера;
+// @Description  встроенный провайдер fake в production (APP_ENV=production) недоступен.
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        provider  path  string  true  "Провайдер"  Enums(yookassa, fake)
+// @Success      200       "OK"
+// @Failure      400       {object}  httphelper.APIResponse
+// @Failure      403       {object}  httphelper.APIResponse
+// @Failure      404       {object}  httphelper.APIResponse
+// @Failure      500       {object}  httphelper.APIResponse
+// @Router       /api/v1/payments/webhook/{provider} [post]
+func (h *PaymentHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid body")
+		return
+	}
+
+	provider := chi.URLParam(r, "provider")
+	source := h.webhookSource(r)
+	err = h.service.HandleWebhook(r.Context(), provider, source, body)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(err, payments.ErrUntrustedSource):
+		h.logger.Warn("payment webhook from untrusted address", zap.String("provider", provider), zap.String("source", source.String()))
+		httphelper.WriteError(w, http.StatusForbidden, "Untrusted webhook source")
+	case errors.Is(err, payments.ErrInvalidWebhook):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrUnknownPaymentProvider):
+		httphelper.WriteError(w, http.StatusNotFound, "Unknown payment provider")
+	default:
+		h.writeServiceError(w, "payment webhook failed", err)
+	}
+}
+
+// webhookSource возвращает адрес отправителя уведомления: из заголовка reverse proxy, если он настроен,
+// иначе адрес соединения. Если адрес не разобрать, возвращается пустой netip.Addr — он не входит ни в один список.
+func (h *PaymentHandler) webhookSource(r *http.Request) netip.Addr {
+	if h.realIPHeader != "" {
+		// в X-Forwarded-For последний адрес добавил наш прокси, предыдущие мог подставить отправитель
+		values := strings.Split(r.Header.Get(h.realIPHeader), ",")
+		addr, _ := netip.ParseAddr(strings.TrimSpace(values[len(values)-1]))
+		return addr
+	}
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addrPort.Addr()
+}
+
+// AdminGetByOrder godoc
+// @Summary      Платежи заказа
+// @Description  Только для админов. Все платежи по заказу, последний первым.
+// @Tags         Payments
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID заказа"
+// @Success      200  {object}  httphelper.APIResponse{data=[]models.Payment}
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/orders/{id}/payments [get]
+func (h *PaymentHandler) AdminGetByOrder(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListByOrder(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get order payments", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, list)
+}
+
+// Refund godoc
+// @Summary      Вернуть деньги за заказ
+// @Description  Только для админов. Возвращает через провайдера всю не возвращённую ещё сумму оплаченного платежа
+// @Description  и переводит заказ в refunded; если заказ ещё не отгружен, товары возвращаются на склад.
+// @Description  Если у заказа нет оплаченного платежа через провайдера (409), например он оплачен наличными,
+// @Description  его переводят в refunded вручную через /admin/orders/{id}/transitions.
+// @Tags         Payments
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true   "ID заказа"
+// @Param        request  body      dto.OrderRefundRequest  false  "Причина возврата"
+// @Success      200      {object}  httphelper.APIResponse{data=models.Order}
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/orders/{id}/refund [post]
+func (h *PaymentHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	var req dto.OrderRefundRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+			return
+		}
+		if err := validation.ValidateStruct(req); err != nil {
+			httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	order, err := h.service.Refund(r.Context(), chi.URLParam(r, "id"), req.Comment)
+	if err != nil {
+		h.writeServiceError(w, "order refund failed", err)
+		return
+	}
+
+	h.logger.Info("order refunded", zap.String("id", order.ID), zap.Int("total", order.Total))
+	httphelper.WriteSuccess(w, http.StatusOK, order)
+}
+
+func (h *PaymentHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Order not found")
+	case errors.Is(err, services.ErrPaymentNotFound), errors.Is(err, payments.ErrPaymentNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Payment not found")
+	case errors.Is(err, services.ErrOrderNotPayable), errors.Is(err, services.ErrNothingToRefund),
+		errors.Is(err, services.ErrOrderTransition), errors.Is(err, services.ErrOrderStatusConflict):
+		httphelper.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process payment")
+	}
+}
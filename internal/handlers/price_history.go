@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// maxPriceHistoryDays — самый длинный период истории цен, который можно запросить
+const maxPriceHistoryDays = 365
+
+// GetPriceHistory godoc
+// @Summary      История цены товара
+// @Description  Изменения цены за последние days дней от новых к старым; последней идёт цена, действовавшая в начале периода.
+// @Description  Минимальная цена за 30 дней отдаётся в самом товаре в поле lowestPrice30Days.
+// @Tags         Products
+// @Produce      json
+// @Param        slug  path      string  true   "Slug товара"
+// @Param        days  query     int     false  "Период в днях (по умолчанию 30, не больше 365)"
+// @Success      200   {object}  httphelper.APIResponse{data=[]models.PricePoint}
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/products/{slug}/price-history [get]
+func (h *ProductHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	days := httphelper.ParseInt(r.URL.Query().Get("days"), 30)
+	if days <= 0 || days > maxPriceHistoryDays {
+		days = 30
+	}
+
+	history, err := h.service.GetPriceHistory(r.Context(), slug, time.Now().UTC().AddDate(0, 0, -days))
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		h.logger.Error("failed to get price history", zap.String("slug", slug), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load price history")
+		return
+	}
+
+	httphelper.WriteSuccess(w, http.StatusOK, history)
+}
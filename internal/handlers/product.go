@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"dozenChairs/internal/dto"
 	"dozenChairs/internal/metrics"
 	"dozenChairs/internal/models"
 	"dozenChairs/internal/repository"
@@ -9,7 +10,12 @@ import (
 	"dozenChairs/pkg/logger"
 	"dozenChairs/pkg/validation"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -30,7 +36,7 @@ func NewProductHandler(s services.ProductService, l logger.Logger) *ProductHandl
 
 // Create godoc
 // @Summary      Создать товар
-// @Description  Только для админов. Создаёт новый товар или набор. У набора нужно указать поле `includes`, а у обычного товара — `unitCount` и `attributes`.
+// @Description  Только для админов. Создаёт новый товар или набор. Категория задаётся через `categoryId` либо slug/название в `category`. У набора нужно указать поле `includes`, а у обычного товара — `unitCount` и `attributes`. Без `status` товар публикуется сразу, а при `publishAt` в будущем сохраняется черновиком и публикуется автоматически; по `unpublishAt` товар уходит в архив. Если `slug` не указан, он генерируется из названия (транслитерация по ГОСТ 7.79-2000) с числовым суффиксом при совпадении. Включённые в набор товары должны существовать, иметь тип product и не иметь вариантов; наличие набора считается по их остаткам, а при заданном `setDiscountPercent` — и цена.
 // @Tags         Products
 // @Security     BearerAuth
 // @Accept       json
@@ -38,6 +44,7 @@ func NewProductHandler(s services.ProductService, l logger.Logger) *ProductHandl
 // @Param        product  body      models.Product  true  "Данные нового товара или набора"
 // @Success      201      {object}  models.Product
 // @Failure      400      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
 // @Failure      500      {object}  httphelper.APIResponse
 // @Router       /api/v1/products [post]
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -59,23 +66,37 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Create(&p); err != nil {
+	if err := h.service.Create(r.Context(), &p); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			httphelper.WriteError(w, http.StatusConflict, "Slug already exists")
+			return
+		}
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			httphelper.WriteError(w, http.StatusBadRequest, "Unknown category")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidSetItems) || errors.Is(err, services.ErrInvalidSchedule) {
+			httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		h.logger.Error("product creation failed", zap.String("slug", p.Slug), zap.Error(err))
 		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to create product")
 		return
 	}
 	metrics.ProductsCreated.Inc()
 	h.logger.Info("product created", zap.String("slug", p.Slug))
+	setProductETag(w, &p)
 	httphelper.WriteSuccess(w, http.StatusCreated, p)
 }
 
 // GetBySlug godoc
 // @Summary      Получить товар по slug
-// @Description  Возвращает один товар по его уникальному slug. Включает изображения, атрибуты и, при типе set — включённые товары.
+// @Description  Возвращает один товар по его уникальному slug. Включает изображения, атрибуты, варианты с диапазоном цен и, при типе set — включённые товары. Черновики и архивные товары не отдаются. Для прежнего slug переименованного товара отвечает 301 на текущий адрес.
 // @Tags         Products
 // @Produce      json
 // @Param        slug  path      string  true  "Slug товара"
 // @Success      200   {object}  models.Product
+// @Success      301   "Товар переименован, Location содержит текущий адрес"
 // @Failure      404   {object}  httphelper.APIResponse
 // @Failure      500   {object}  httphelper.APIResponse
 // @Router       /api/v1/products/{slug} [get]
@@ -83,39 +104,169 @@ func (h *ProductHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 
 	p, err := h.service.GetBySlug(slug)
-	if err != nil {
-		h.logger.Error("product not found", zap.String("slug", slug), zap.Error(err))
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		if h.redirectToCurrentSlug(w, r, slug) {
+			return
+		}
+		httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+		return
+	case err != nil:
+		h.logger.Error("failed to get product", zap.String("slug", slug), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load product")
+		return
+	}
+	if !p.Visible(time.Now()) {
 		httphelper.WriteError(w, http.StatusNotFound, "Product not found")
 		return
 	}
 	metrics.ProductFetched.Inc()
 
 	h.logger.Info("product fetched", zap.String("id", p.ID), zap.String("slug", slug))
+	setProductETag(w, p)
 	httphelper.WriteSuccess(w, http.StatusOK, p)
 }
 
 // GetAll godoc
 // @Summary      Получить список товаров
-// @Description  Возвращает список товаров или наборов. Доступна фильтрация по типу, категории и наличию, а также сортировка по цене и дате создания.
+// @Description  Возвращает список опубликованных товаров или наборов вместе с фасетами (количество товаров по значениям атрибутов и диапазон цен). Доступна фильтрация по типу, категории, наличию, цене, тегам и атрибутам, а также сортировка по цене и дате создания. При указании `q` выполняется полнотекстовый поиск, результаты ранжируются по релевантности и содержат подсветку совпадений. Цены в ответе учитывают действующие акции (`price` — цена со скидкой, `oldPrice` — до неё), а фильтр priceMin/priceMax, сортировка по цене и фасет цен работают по базовой цене товара без акций.
 // @Tags         Products
 // @Produce      json
+// @Param        q        query    string  false  "Поисковая строка"
 // @Param        type     query    string  false  "Тип товара (product или set)"
-// @Param        category query    string  false  "Категория"
+// @Param        category query    string  false  "Категория (slug или название), включая подкатегории"
 // @Param        inStock  query    boolean false  "Есть в наличии"
-// @Param        sort     query    string  false  "Сортировка (price или createdAt)"
+// @Param        city     query    string  false  "Город: inStock проверяется по остаткам складов этого города"
+// @Param        warehouse query   string  false  "Код или ID склада: inStock проверяется по остаткам этого склада"
+// @Param        priceMin query    int     false  "Минимальная цена"
+// @Param        priceMax query    int     false  "Максимальная цена"
+// @Param        tags     query    string  false  "Теги через запятую (товар должен содержать все)"
+// @Param        attr.{key}      query  string  false  "Фильтр по атрибуту: одно или несколько значений через запятую (например, attr.color=white,black)"
+// @Param        attr.{key}.min  query  number  false  "Нижняя граница числового атрибута (например, attr.seat_height.min=45)"
+// @Param        attr.{key}.max  query  number  false  "Верхняя граница числового атрибута"
+// @Param        sort     query    string  false  "Сортировка (price, createdAt; префикс - для обратного порядка, по умолчанию -createdAt)"
 // @Param        limit    query    int     false  "Лимит на страницу (по умолчанию 20)"
 // @Param        offset   query    int     false  "Смещение (по умолчанию 0)"
-// @Success      200      {array}  models.Product
+// @Param        cursor   query    string  false  "Курсор следующей страницы (meta.next_cursor), используется вместо offset"
+// @Success      200      {object} httphelper.APIResponse{data=[]models.Product,meta=dto.ProductListMeta}
+// @Failure      400      {object} httphelper.APIResponse
 // @Failure      500      {object} httphelper.APIResponse
 // @Router       /api/v1/products [get]
 func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
+	filter, err := parseProductFilter(r.URL.Query(), 20)
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if filter.Query != "" {
+		h.search(w, filter)
+		return
+	}
+
+	page, err := h.service.GetPage(filter)
+	if err != nil {
+		h.logger.Error("failed to get products", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load products")
+		return
+	}
+	metrics.ProductsFetched.Inc()
+
+	facets, err := h.service.GetFacets(filter)
+	if err != nil {
+		h.logger.Error("failed to get product facets", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load products")
+		return
+	}
+
+	meta := pageMeta(filter, page)
+	meta.Facets = facets
+
+	h.logger.Info("products fetched", zap.Int("count", len(page.Items)), zap.Int("total", page.Total))
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, page.Items, meta)
+}
 
+// Search godoc
+// @Summary      Полнотекстовый поиск товаров
+// @Description  Ищет товары по названию, описанию, тегам и значениям атрибутов с учётом русской морфологии. Результаты ранжируются по релевантности и содержат подсветку совпадений (`<mark>`). Цены в ответе учитывают действующие акции (`price` — цена со скидкой, `oldPrice` — до неё), а фильтр priceMin/priceMax, сортировка по цене и фасет цен работают по базовой цене товара без акций.
+// @Tags         Products
+// @Produce      json
+// @Param        q        query    string  true   "Поисковая строка"
+// @Param        type     query    string  false  "Тип товара (product или set)"
+// @Param        category query    string  false  "Категория (slug или название), включая подкатегории"
+// @Param        inStock  query    boolean false  "Есть в наличии"
+// @Param        city     query    string  false  "Город: inStock проверяется по остаткам складов этого города"
+// @Param        warehouse query   string  false  "Код или ID склада: inStock проверяется по остаткам этого склада"
+// @Param        priceMin query    int     false  "Минимальная цена"
+// @Param        priceMax query    int     false  "Максимальная цена"
+// @Param        tags     query    string  false  "Теги через запятую (товар должен содержать все)"
+// @Param        attr.{key}      query  string  false  "Фильтр по атрибуту: одно или несколько значений через запятую (например, attr.color=white,black)"
+// @Param        attr.{key}.min  query  number  false  "Нижняя граница числового атрибута (например, attr.seat_height.min=45)"
+// @Param        attr.{key}.max  query  number  false  "Верхняя граница числового атрибута"
+// @Param        sort     query    string  false  "Сортировка (price, createdAt; префикс - для обратного порядка), по умолчанию — по релевантности"
+// @Param        limit    query    int     false  "Лимит на страницу (по умолчанию 20)"
+// @Param        offset   query    int     false  "Смещение (по умолчанию 0)"
+// @Success      200      {object} httphelper.APIResponse{data=[]models.ProductSearchResult,meta=dto.ProductListMeta}
+// @Failure      400      {object} httphelper.APIResponse
+// @Failure      500      {object} httphelper.APIResponse
+// @Router       /api/v1/products/search [get]
+func (h *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseProductFilter(r.URL.Query(), 20)
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if filter.Query == "" {
+		httphelper.WriteError(w, http.StatusBadRequest, "Query parameter q is required")
+		return
+	}
+
+	h.search(w, filter)
+}
+
+func (h *ProductHandler) search(w http.ResponseWriter, filter repository.ProductFilter) {
+	results, err := h.service.Search(filter)
+	if err != nil {
+		h.logger.Error("product search failed", zap.String("q", filter.Query), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to search products")
+		return
+	}
+	metrics.ProductsSearched.Inc()
+
+	total, err := h.service.Count(filter)
+	if err != nil {
+		h.logger.Error("failed to count search results", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to search products")
+		return
+	}
+
+	facets, err := h.service.GetFacets(filter)
+	if err != nil {
+		h.logger.Error("failed to get product facets", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to search products")
+		return
+	}
+
+	// результаты поиска упорядочены по релевантности, поэтому пагинация только через offset
+	meta := pageMeta(filter, &models.ProductPage{Total: total})
+	meta.Facets = facets
+
+	h.logger.Info("products searched", zap.String("q", filter.Query), zap.Int("count", len(results)))
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, results, meta)
+}
+
+// parseProductFilter читает параметры фильтрации списка товаров из query-строки.
+// Атрибуты задаются как attr.<ключ>=значение (несколько значений — через запятую или повтором параметра),
+// числовые диапазоны — как attr.<ключ>.min / attr.<ключ>.max.
+func parseProductFilter(q url.Values, defaultLimit int) (repository.ProductFilter, error) {
 	filter := repository.ProductFilter{
 		Type:     q.Get("type"),
 		Category: q.Get("category"),
+		Query:    strings.TrimSpace(q.Get("q")),
+		Tags:     splitValues(q["tags"]),
 		Sort:     q.Get("sort"),
-		Limit:    httphelper.ParseInt(q.Get("limit"), 20),
+		Limit:    httphelper.ParseInt(q.Get("limit"), defaultLimit),
 		Offset:   httphelper.ParseInt(q.Get("offset"), 0),
 	}
 
@@ -123,17 +274,109 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		b := inStockStr == "true"
 		filter.InStock = &b
 	}
+	filter.StockCity = q.Get("city")
+	filter.StockWarehouse = q.Get("warehouse")
+
+	if err := parseCursor(q, &filter); err != nil {
+		return filter, err
+	}
 
-	products, err := h.service.GetAll(filter)
+	for _, bound := range []struct {
+		param string
+		dst   **int
+	}{{"priceMin", &filter.PriceMin}, {"priceMax", &filter.PriceMax}} {
+		if v := q.Get(bound.param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %q", bound.param, v)
+			}
+			*bound.dst = &n
+		}
+	}
+
+	for param, values := range q {
+		key, ok := strings.CutPrefix(param, "attr.")
+		if !ok || key == "" {
+			continue
+		}
+
+		if name, ok := strings.CutSuffix(key, ".min"); ok {
+			if err := setRangeBound(&filter, name, values[0], true); err != nil {
+				return filter, err
+			}
+			continue
+		}
+		if name, ok := strings.CutSuffix(key, ".max"); ok {
+			if err := setRangeBound(&filter, name, values[0], false); err != nil {
+				return filter, err
+			}
+			continue
+		}
+
+		if vals := splitValues(values); len(vals) > 0 {
+			if filter.Attributes == nil {
+				filter.Attributes = map[string][]string{}
+			}
+			filter.Attributes[key] = vals
+		}
+	}
+
+	return filter, nil
+}
+
+// parseCursor читает параметр cursor; курсор должен быть выдан для той же сортировки
+func parseCursor(q url.Values, filter *repository.ProductFilter) error {
+	raw := q.Get("cursor")
+	if raw == "" {
+		return nil
+	}
+	cursor, err := repository.DecodeCursor(raw, filter.Sort)
 	if err != nil {
-		h.logger.Error("failed to get products", zap.Error(err))
-		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load products")
-		return
+		return err
 	}
-	metrics.ProductsFetched.Inc()
+	filter.Cursor = cursor
+	return nil
+}
+
+// pageMeta собирает метаданные пагинации для ответа со списком
+func pageMeta(filter repository.ProductFilter, page *models.ProductPage) dto.ProductListMeta {
+	return dto.ProductListMeta{
+		Total:      page.Total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+		NextCursor: page.NextCursor,
+	}
+}
+
+func setRangeBound(f *repository.ProductFilter, key, value string, isMin bool) error {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid range value for attribute %q: %q", key, value)
+	}
+	if f.AttributeRanges == nil {
+		f.AttributeRanges = map[string]repository.NumericRange{}
+	}
+	rng := f.AttributeRanges[key]
+	if isMin {
+		rng.Min = &n
+	} else {
+		rng.Max = &n
+	}
+	f.AttributeRanges[key] = rng
+	return nil
+}
 
-	h.logger.Info("products fetched", zap.Int("count", len(products)))
-	httphelper.WriteSuccess(w, http.StatusOK, products)
+// splitValues разбирает значения параметра, переданные повтором и/или через запятую
+func splitValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
 }
 
 // GetSets godoc
@@ -142,10 +385,14 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Tags         Sets
 // @Produce      json
 // @Param        inStock query    boolean false  "Есть в наличии"
-// @Param        sort     query    string  false  "Сортировка (price или createdAt)"
+// @Param        city    query    string  false  "Город: inStock проверяется по остаткам складов этого города"
+// @Param        warehouse query  string  false  "Код или ID склада: inStock проверяется по остаткам этого склада"
+// @Param        sort     query    string  false  "Сортировка (price, createdAt; префикс - для обратного порядка)"
 // @Param        limit    query    int     false  "Лимит на страницу"
 // @Param        offset   query    int     false  "Смещение"
-// @Success      200      {array}  models.Product
+// @Param        cursor   query    string  false  "Курсор следующей страницы (meta.next_cursor)"
+// @Success      200      {object} httphelper.APIResponse{data=[]models.Product,meta=dto.ProductListMeta}
+// @Failure      400      {object} httphelper.APIResponse
 // @Failure      500      {object} httphelper.APIResponse
 // @Router       /api/v1/sets [get]
 func (h *ProductHandler) GetSets(w http.ResponseWriter, r *http.Request) {
@@ -162,25 +409,33 @@ func (h *ProductHandler) GetSets(w http.ResponseWriter, r *http.Request) {
 		b := inStockStr == "true"
 		filter.InStock = &b
 	}
+	filter.StockCity = q.Get("city")
+	filter.StockWarehouse = q.Get("warehouse")
 
-	sets, err := h.service.GetAll(filter)
+	if err := parseCursor(q, &filter); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetPage(filter)
 	if err != nil {
 		h.logger.Error("failed to get sets", zap.Error(err))
 		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load sets")
 		return
 	}
 
-	h.logger.Info("sets fetched", zap.Int("count", len(sets)))
-	httphelper.WriteSuccess(w, http.StatusOK, sets)
+	h.logger.Info("sets fetched", zap.Int("count", len(page.Items)))
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, page.Items, pageMeta(filter, page))
 }
 
 // GetSetBySlug godoc
 // @Summary      Получить набор по slug
-// @Description  Возвращает товар типа set по его slug. Если товар не является набором, будет возвращена ошибка.
+// @Description  Возвращает товар типа set по его slug с раскрытыми данными входящих в него товаров (`includes[].product`). Наличие и остаток набора рассчитываются по компонентам. Если товар не является набором, будет возвращена ошибка. Для прежнего slug отвечает 301 на текущий адрес.
 // @Tags         Sets
 // @Produce      json
 // @Param        slug  path      string  true  "Slug набора"
 // @Success      200   {object}  models.Product
+// @Success      301   "Набор переименован, Location содержит текущий адрес"
 // @Failure      400   {object}  httphelper.APIResponse
 // @Failure      404   {object}  httphelper.APIResponse
 // @Failure      500   {object}  httphelper.APIResponse
@@ -188,60 +443,65 @@ func (h *ProductHandler) GetSets(w http.ResponseWriter, r *http.Request) {
 func (h *ProductHandler) GetSetBySlug(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 
-	p, err := h.service.GetBySlug(slug)
-	if err != nil {
-		h.logger.Error("set not found", zap.String("slug", slug), zap.Error(err))
+	p, err := h.service.GetSet(slug)
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		if h.redirectToCurrentSlug(w, r, slug) {
+			return
+		}
 		httphelper.WriteError(w, http.StatusNotFound, "Set not found")
 		return
-	}
-
-	if p.Type != "set" {
+	case errors.Is(err, services.ErrNotASet):
 		h.logger.Warn("not a set", zap.String("slug", slug))
 		httphelper.WriteError(w, http.StatusBadRequest, "Item is not a set")
 		return
+	case err != nil:
+		h.logger.Error("failed to get set", zap.String("slug", slug), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load set")
+		return
 	}
-
-	h.logger.Info("set fetched", zap.String("slug", slug))
-	httphelper.WriteSuccess(w, http.StatusOK, p)
-}
-
-// GetCategories godoc
-// @Summary      Получить список категорий
-// @Description  Возвращает все уникальные категории товаров
-// @Tags         Products
-// @Produce      json
-// @Success      200  {array}  string
-// @Failure      500  {object} httphelper.APIResponse
-// @Router       /api/v1/categories [get]
-func (h *ProductHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.service.GetCategories()
-	if err != nil {
-		h.logger.Error("failed to get categories", zap.Error(err))
-		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load categories")
+	if !p.Visible(time.Now()) {
+		httphelper.WriteError(w, http.StatusNotFound, "Set not found")
 		return
 	}
 
-	h.logger.Info("categories fetched", zap.Int("count", len(categories)))
-	httphelper.WriteSuccess(w, http.StatusOK, categories)
+	h.logger.Info("set fetched", zap.String("slug", slug))
+	httphelper.WriteSuccess(w, http.StatusOK, p)
 }
 
 // Update godoc
 // @Summary      Обновить товар
-// @Description  Только для админов. Обновляет данные товара по slug. Все поля можно изменить, включая изображения, состав набора и атрибуты.
+// @Description  Только для админов. Обновляет данные товара по slug. Все поля можно изменить, включая изображения, состав набора и атрибуты. Новый `slug` переименовывает товар: прежний продолжает работать через 301-редирект. Пустой `slug` оставляет текущий. Требуется заголовок `If-Match` с ETag товара (из GET): если товар успел измениться, возвращается 412 с его актуальным состоянием.
 // @Tags         Products
 // @Security     BearerAuth
 // @Accept       json
 // @Produce      json
 // @Param        slug     path      string          true  "Slug товара"
+// @Param        If-Match header    string          true  "ETag товара, например \"3\""
 // @Param        product  body      models.Product  true  "Обновлённые данные товара"
 // @Success      200      {object}  models.Product
+// @Header       200      {string}  ETag  "Версия товара"
 // @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      412      {object}  models.Product
+// @Failure      428      {object}  httphelper.APIResponse
 // @Failure      500      {object}  httphelper.APIResponse
 // @Router       /api/v1/products/{slug} [put]
 func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
-	var p models.Product
 
+	version, ok, err := parseIfMatch(r)
+	if !ok {
+		httphelper.WriteError(w, http.StatusPreconditionRequired, "If-Match header with product ETag is required")
+		return
+	}
+	if err != nil {
+		h.writeVersionConflict(w, slug)
+		return
+	}
+
+	var p models.Product
 	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
 		h.logger.Error("failed to decode product on update", zap.Error(err))
 		httphelper.WriteError(w, http.StatusBadRequest, "Invalid request body")
@@ -254,31 +514,79 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Update(slug, &p); err != nil {
-		h.logger.Error("failed to update product", zap.String("slug", slug), zap.Error(err))
-		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to update product")
+	p.Version = version
+	if err := h.service.Update(r.Context(), slug, &p); err != nil {
+		h.writeUpdateError(w, slug, err)
 		return
 	}
 
 	h.logger.Info("product updated", zap.String("slug", slug))
+	setProductETag(w, &p)
 	httphelper.WriteSuccess(w, http.StatusOK, p)
 	metrics.ProductsUpdated.Inc()
 }
 
+// writeUpdateError переводит ошибки сохранения товара в HTTP-ответ
+func (h *ProductHandler) writeUpdateError(w http.ResponseWriter, slug string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, repository.ErrVersionConflict):
+		h.writeVersionConflict(w, slug)
+	case errors.Is(err, repository.ErrAlreadyExists):
+		httphelper.WriteError(w, http.StatusConflict, "Slug already exists")
+	case errors.Is(err, services.ErrCategoryNotFound):
+		httphelper.WriteError(w, http.StatusBadRequest, "Unknown category")
+	case errors.Is(err, services.ErrInvalidSetItems), errors.Is(err, services.ErrInvalidSchedule):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error("failed to update product", zap.String("slug", slug), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to update product")
+	}
+}
+
+// writeVersionConflict отвечает 412 с актуальным состоянием товара и его ETag,
+// чтобы клиент мог заново применить свои изменения
+func (h *ProductHandler) writeVersionConflict(w http.ResponseWriter, slug string) {
+	current, err := h.service.GetProduct(slug)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		h.logger.Error("failed to load product after version conflict", zap.String("slug", slug), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to update product")
+		return
+	}
+
+	setProductETag(w, current)
+	httphelper.WriteSuccess(w, http.StatusPreconditionFailed, current)
+}
+
 // Delete godoc
 // @Summary      Удалить товар
-// @Description  Только для админов. Удаляет товар по slug. При удалении набора удаляется только сам набор, не включённые в него товары.
+// @Description  Только для админов. Переносит товар в корзину: он пропадает с витрины, но его можно восстановить вместе с изображениями, пока корзина не очищена. При удалении набора удаляется только сам набор, не включённые в него товары. Товар, входящий в какой-либо набор, удалить нельзя.
 // @Tags         Products
 // @Security     BearerAuth
 // @Produce      json
 // @Param        slug  path  string  true  "Slug товара"
 // @Success      204   "No Content"
+// @Failure      404   {object} httphelper.APIResponse
+// @Failure      409   {object} httphelper.APIResponse
 // @Failure      500   {object} httphelper.APIResponse
 // @Router       /api/v1/products/{slug} [delete]
 func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 
-	if err := h.service.Delete(slug); err != nil {
+	if err := h.service.Delete(r.Context(), slug); err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		if errors.Is(err, repository.ErrReferenced) {
+			httphelper.WriteError(w, http.StatusConflict, "Product is included in a set")
+			return
+		}
 		h.logger.Error("failed to delete product", zap.String("slug", slug), zap.Error(err))
 		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to delete product")
 		return
@@ -294,10 +602,12 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 // @Description  Возвращает список недавно добавленных товаров — либо последние N штук, либо за последние X дней
 // @Tags         Products
 // @Produce      json
-// @Param        limit  query    int  false  "Лимит (по умолчанию 10)"
-// @Param        days   query    int  false  "За сколько последних дней брать товары"
-// @Success      200    {array}  models.Product
-// @Failure      500    {object} httphelper.APIResponse
+// @Param        limit   query    int     false  "Лимит (по умолчанию 10)"
+// @Param        days    query    int     false  "За сколько последних дней брать товары"
+// @Param        cursor  query    string  false  "Курсор следующей страницы (meta.next_cursor)"
+// @Success      200     {object} httphelper.APIResponse{data=[]models.Product,meta=dto.ProductListMeta}
+// @Failure      400     {object} httphelper.APIResponse
+// @Failure      500     {object} httphelper.APIResponse
 // @Router       /api/v1/products/new [get]
 func (h *ProductHandler) GetNew(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
@@ -305,7 +615,7 @@ func (h *ProductHandler) GetNew(w http.ResponseWriter, r *http.Request) {
 	days := httphelper.ParseInt(q.Get("days"), 0)
 
 	filter := repository.ProductFilter{
-		Sort:  "createdAt",
+		Sort:  "-createdAt",
 		Limit: limit,
 	}
 
@@ -313,13 +623,47 @@ func (h *ProductHandler) GetNew(w http.ResponseWriter, r *http.Request) {
 		filter.FromDate = time.Now().AddDate(0, 0, -days)
 	}
 
-	products, err := h.service.GetAll(filter)
+	if err := parseCursor(q, &filter); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetPage(filter)
 	if err != nil {
 		h.logger.Error("failed to get new products", zap.Error(err))
 		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load new products")
 		return
 	}
 
-	h.logger.Info("new products fetched", zap.Int("count", len(products)))
-	httphelper.WriteSuccess(w, http.StatusOK, products)
+	h.logger.Info("new products fetched", zap.Int("count", len(page.Items)))
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, page.Items, pageMeta(filter, page))
+}
+
+// redirectToCurrentSlug отвечает 301 на текущий адрес, если slug — прежний slug переименованного товара.
+// Для товаров, скрытых с витрины, редиректа нет: иначе он раскрыл бы slug черновика
+func (h *ProductHandler) redirectToCurrentSlug(w http.ResponseWriter, r *http.Request, slug string) bool {
+	current, err := h.service.ResolveSlug(slug)
+	if err != nil {
+		if !errors.Is(err, services.ErrProductNotFound) {
+			h.logger.Error("failed to resolve slug", zap.String("slug", slug), zap.Error(err))
+		}
+		return false
+	}
+
+	p, err := h.service.GetProduct(current)
+	if err != nil {
+		if !errors.Is(err, services.ErrProductNotFound) {
+			h.logger.Error("failed to get renamed product", zap.String("slug", current), zap.Error(err))
+		}
+		return false
+	}
+	if !p.Visible(time.Now()) {
+		return false
+	}
+
+	target := *r.URL
+	target.Path = strings.TrimSuffix(r.URL.Path, slug) + current
+	target.RawPath = ""
+	http.Redirect(w, r, target.String(), http.StatusMovedPermanently)
+	return true
 }
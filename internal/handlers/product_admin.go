@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	_ "dozenChairs/internal/dto"    // типы для аннотаций swag
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// AdminGetAll godoc
+// @Summary      Получить все товары (админка)
+// @Description  Только для админов. Как и публичный список, но включает черновики и архивные товары. Фасеты не считаются.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        status   query    string  false  "Статус (draft, published, archived)"
+// @Param        type     query    string  false  "Тип товара (product или set)"
+// @Param        category query    string  false  "Категория (slug или название), включая подкатегории"
+// @Param        q        query    string  false  "Поисковая строка"
+// @Param        sort     query    string  false  "Сортировка (price, createdAt; префикс - для обратного порядка, по умолчанию -createdAt)"
+// @Param        limit    query    int     false  "Лимит на страницу (по умолчанию 20)"
+// @Param        offset   query    int     false  "Смещение (по умолчанию 0)"
+// @Param        cursor   query    string  false  "Курсор следующей страницы (meta.next_cursor)"
+// @Success      200      {object} httphelper.APIResponse{data=[]models.Product,meta=dto.ProductListMeta}
+// @Failure      400      {object} httphelper.APIResponse
+// @Failure      500      {object} httphelper.APIResponse
+// @Router       /api/v1/admin/products [get]
+func (h *ProductHandler) AdminGetAll(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := parseProductFilter(q, 20)
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.WithUnpublished = true
+	filter.Status = q.Get("status")
+
+	page, err := h.service.GetPage(filter)
+	if err != nil {
+		h.logger.Error("failed to get products for admin", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load products")
+		return
+	}
+
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, page.Items, pageMeta(filter, page))
+}
+
+// AdminGetBySlug godoc
+// @Summary      Получить товар по slug (админка)
+// @Description  Только для админов. Возвращает товар в любом статусе.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug  path      string  true  "Slug товара"
+// @Success      200   {object}  models.Product
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/{slug} [get]
+func (h *ProductHandler) AdminGetBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	p, err := h.service.GetProduct(slug)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFound) {
+			httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+			return
+		}
+		h.logger.Error("failed to get product for admin", zap.String("slug", slug), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load product")
+		return
+	}
+
+	setProductETag(w, p)
+	httphelper.WriteSuccess(w, http.StatusOK, p)
+}
+
+// GetTrash godoc
+// @Summary      Корзина товаров
+// @Description  Только для админов. Удалённые товары, которые ещё можно восстановить. По истечении срока хранения они удаляются окончательно вместе с файлами изображений.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        type     query    string  false  "Тип товара (product или set)"
+// @Param        q        query    string  false  "Поисковая строка"
+// @Param        limit    query    int     false  "Лимит на страницу (по умолчанию 20)"
+// @Param        offset   query    int     false  "Смещение (по умолчанию 0)"
+// @Param        cursor   query    string  false  "Курсор следующей страницы (meta.next_cursor)"
+// @Success      200      {object} httphelper.APIResponse{data=[]models.Product,meta=dto.ProductListMeta}
+// @Failure      400      {object} httphelper.APIResponse
+// @Failure      500      {object} httphelper.APIResponse
+// @Router       /api/v1/admin/trash [get]
+func (h *ProductHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseProductFilter(r.URL.Query(), 20)
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.GetTrash(filter)
+	if err != nil {
+		h.logger.Error("failed to get trash", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load trash")
+		return
+	}
+
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, page.Items, pageMeta(filter, page))
+}
+
+// Restore godoc
+// @Summary      Восстановить товар из корзины
+// @Description  Только для админов. Товар возвращается с прежним slug, статусом и изображениями. Набор восстанавливается только после всех входящих в него товаров.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug  path  string  true  "Slug товара"
+// @Success      204   "No Content"
+// @Failure      400   {object}  httphelper.APIResponse
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/trash/{slug}/restore [post]
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	if err := h.service.Restore(r.Context(), slug); err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFound):
+			httphelper.WriteError(w, http.StatusNotFound, "Product not found in trash")
+		case errors.Is(err, services.ErrInvalidSetItems):
+			httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		default:
+			h.logger.Error("failed to restore product", zap.String("slug", slug), zap.Error(err))
+			httphelper.WriteError(w, http.StatusInternalServerError, "Failed to restore product")
+		}
+		return
+	}
+
+	h.logger.Info("product restored", zap.String("slug", slug))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// maxImportFileSize — ограничение на размер загружаемого файла импорта
+const maxImportFileSize = 20 << 20 // 20 MB
+
+// Import godoc
+// @Summary      Импорт товаров из CSV/XLSX
+// @Description  Только для админов. Первая строка файла — заголовок с именами полей товара (slug, type, title, category, categoryId, description, price, oldPrice, inStock, unitCount, tags, includes, setDiscountPercent, status, publishAt, unpublishAt), атрибуты — колонки attr.<ключ>.
+// @Description  Строки сопоставляются с товарами по slug (если он не указан — по slug из title): существующие товары обновляются заполненными ячейками, остальные создаются.
+// @Description  tags и includes перечисляются через запятую или точку с запятой, состав набора — в виде slug:количество.
+// @Description  Все строки сохраняются в одной транзакции, ошибочные строки пропускаются и попадают в отчёт. С dryRun=true изменения только проверяются.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file    formData  file  true   "Файл .csv или .xlsx"
+// @Param        dryRun  query     bool  false  "Проверить без сохранения"
+// @Success      200     {object}  httphelper.APIResponse{data=models.ImportReport}
+// @Failure      400     {object}  httphelper.APIResponse
+// @Failure      500     {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/import [post]
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			httphelper.WriteError(w, http.StatusBadRequest, "Invalid dryRun")
+			return
+		}
+		dryRun = b
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Import file is required")
+		return
+	}
+	defer file.Close()
+
+	rows, err := services.ReadImportFile(file, header.Filename)
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.service.Import(r.Context(), rows, dryRun)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidImportColumns) {
+			httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.logger.Error("product import failed", zap.String("file", header.Filename), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to import products")
+		return
+	}
+
+	h.logger.Info("products imported",
+		zap.String("file", header.Filename),
+		zap.Bool("dryRun", dryRun),
+		zap.Int("created", report.Created),
+		zap.Int("updated", report.Updated),
+		zap.Int("failed", report.Failed))
+	httphelper.WriteSuccess(w, http.StatusOK, report)
+}
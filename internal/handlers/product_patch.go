@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"dozenChairs/internal/metrics"
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// maxPatchSize — ограничение размера тела PATCH-запроса
+const maxPatchSize = 1 << 20
+
+// Patch godoc
+// @Summary      Частично обновить товар
+// @Description  Только для админов. Принимает JSON Merge Patch (`application/merge-patch+json`, RFC 7396) или JSON Patch (`application/json-patch+json`, RFC 6902). Патч применяется к текущему представлению товара, результат проверяется целиком, а сохраняются только изменившиеся поля. Поля id, version, createdAt, updatedAt, images и variants менять нельзя. Заголовок `If-Match` необязателен: если он передан и товар успел измениться, возвращается 412 с актуальным состоянием.
+// @Tags         Products
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        slug      path      string  true   "Slug товара"
+// @Param        If-Match  header    string  false  "ETag товара, например \"3\""
+// @Param        patch     body      object  true   "Merge Patch (объект) или JSON Patch (массив операций)"
+// @Success      200       {object}  models.Product
+// @Header       200       {string}  ETag  "Версия товара"
+// @Failure      400       {object}  httphelper.APIResponse
+// @Failure      404       {object}  httphelper.APIResponse
+// @Failure      409       {object}  httphelper.APIResponse
+// @Failure      412       {object}  models.Product
+// @Failure      415       {object}  httphelper.APIResponse
+// @Failure      500       {object}  httphelper.APIResponse
+// @Router       /api/v1/products/{slug} [patch]
+func (h *ProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != services.MergePatchType && mediaType != services.JSONPatchType) {
+		httphelper.WriteError(w, http.StatusUnsupportedMediaType,
+			"Content-Type must be "+services.MergePatchType+" or "+services.JSONPatchType)
+		return
+	}
+
+	version, _, err := parseIfMatch(r)
+	if err != nil {
+		h.writeVersionConflict(w, slug)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchSize))
+	if err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	p, err := h.service.Patch(r.Context(), slug, version, services.Patch{Type: mediaType, Body: body})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPatch) {
+			httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.writeUpdateError(w, slug, err)
+		return
+	}
+
+	h.logger.Info("product patched", zap.String("slug", slug))
+	metrics.ProductsUpdated.Inc()
+	setProductETag(w, p)
+	httphelper.WriteSuccess(w, http.StatusOK, p)
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/middlewares"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type PromoCodeHandler struct {
+	service services.PromoCodeService
+	logger  logger.Logger
+}
+
+func NewPromoCodeHandler(s services.PromoCodeService, l logger.Logger) *PromoCodeHandler {
+	return &PromoCodeHandler{
+		service: s,
+		logger:  l,
+	}
+}
+
+// Validate godoc
+// @Summary      Проверить промокод
+// @Description  Проверяет промокод для позиций заказа и возвращает суммы со скидкой. Цены берутся с витрины с учётом акций.
+// @Description  Авторизация необязательна: для вошедшего покупателя дополнительно проверяется лимит применений на одного покупателя.
+// @Description  Промокод при этом не расходуется.
+// @Tags         PromoCodes
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.PromoCodeValidateRequest  true  "Промокод и позиции заказа"
+// @Success      200      {object}  httphelper.APIResponse{data=models.PromoCodeQuote}
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/promo-codes/validate [post]
+func (h *PromoCodeHandler) Validate(w http.ResponseWriter, r *http.Request) {
+	var req dto.PromoCodeValidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if err := validation.ValidateStruct(req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	quote, err := h.service.Quote(r.Context(), req.Code, middlewares.CurrentUserID(r.Context()), req.Items)
+	if err != nil {
+		h.writeServiceError(w, "promo code validation failed", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, quote)
+}
+
+// GetAll godoc
+// @Summary      Список промокодов
+// @Description  Только для админов. Все промокоды со счётчиком применений, новые первыми.
+// @Tags         PromoCodes
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  httphelper.APIResponse{data=[]models.PromoCode}
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promo-codes [get]
+func (h *PromoCodeHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	codes, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get promo codes", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load promo codes")
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, codes)
+}
+
+// GetByID godoc
+// @Summary      Получить промокод
+// @Tags         PromoCodes
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID промокода"
+// @Success      200  {object}  httphelper.APIResponse{data=models.PromoCode}
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promo-codes/{id} [get]
+func (h *PromoCodeHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	c, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get promo code", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, c)
+}
+
+// Create godoc
+// @Summary      Создать промокод
+// @Description  Только для админов. Код сохраняется в верхнем регистре и вводится покупателями без учёта регистра.
+// @Description  Без categoryIds и productIds промокод действует на все товары заказа.
+// @Tags         PromoCodes
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        promoCode  body      models.PromoCode  true  "Данные промокода"
+// @Success      201        {object}  httphelper.APIResponse{data=models.PromoCode}
+// @Failure      400        {object}  httphelper.APIResponse
+// @Failure      409        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promo-codes [post]
+func (h *PromoCodeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	c, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Create(r.Context(), c); err != nil {
+		h.writeServiceError(w, "promo code creation failed", err)
+		return
+	}
+
+	h.logger.Info("promo code created", zap.String("id", c.ID), zap.String("code", c.Code))
+	httphelper.WriteSuccess(w, http.StatusCreated, c)
+}
+
+// Update godoc
+// @Summary      Обновить промокод
+// @Description  Только для админов. Заменяет настройки промокода целиком; счётчик применений сохраняется.
+// @Tags         PromoCodes
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string            true  "ID промокода"
+// @Param        promoCode  body      models.PromoCode  true  "Данные промокода"
+// @Success      200        {object}  httphelper.APIResponse{data=models.PromoCode}
+// @Failure      400        {object}  httphelper.APIResponse
+// @Failure      404        {object}  httphelper.APIResponse
+// @Failure      409        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promo-codes/{id} [put]
+func (h *PromoCodeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	c, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, c); err != nil {
+		h.writeServiceError(w, "promo code update failed", err)
+		return
+	}
+
+	h.logger.Info("promo code updated", zap.String("id", id))
+	httphelper.WriteSuccess(w, http.StatusOK, c)
+}
+
+// Delete godoc
+// @Summary      Удалить промокод
+// @Description  Только для админов. Вместе с промокодом удаляется история его применений; чтобы её сохранить, выключите промокод.
+// @Tags         PromoCodes
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "ID промокода"
+// @Success      204  "No Content"
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promo-codes/{id} [delete]
+func (h *PromoCodeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.writeServiceError(w, "promo code deletion failed", err)
+		return
+	}
+
+	h.logger.Info("promo code deleted", zap.String("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PromoCodeHandler) decode(w http.ResponseWriter, r *http.Request) (*models.PromoCode, bool) {
+	var c models.PromoCode
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return nil, false
+	}
+	if err := validation.ValidateStruct(c); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return &c, true
+}
+
+func (h *PromoCodeHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrPromoCodeNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Promo code not found")
+	case errors.Is(err, services.ErrPromoCodeExhausted), errors.Is(err, services.ErrPromoCodeUserLimit),
+		errors.Is(err, services.ErrPromoCodeExists):
+		httphelper.WriteError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrPromoCodeNotStarted), errors.Is(err, services.ErrPromoCodeExpired),
+		errors.Is(err, services.ErrPromoCodeMinOrder), errors.Is(err, services.ErrPromoCodeNotApplicable),
+		errors.Is(err, services.ErrInvalidPromoCode), errors.Is(err, services.ErrInvalidOrderItems):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process promo code")
+	}
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type PromotionHandler struct {
+	service services.PromotionService
+	logger  logger.Logger
+}
+
+func NewPromotionHandler(s services.PromotionService, l logger.Logger) *PromotionHandler {
+	return &PromotionHandler{
+		service: s,
+		logger:  l,
+	}
+}
+
+// GetAll godoc
+// @Summary      Список акций
+// @Description  Только для админов. Все акции, включая выключенные и завершившиеся, от большего приоритета к меньшему.
+// @Tags         Promotions
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  httphelper.APIResponse{data=[]models.Promotion}
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promotions [get]
+func (h *PromotionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error("failed to get promotions", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load promotions")
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, promotions)
+}
+
+// GetByID godoc
+// @Summary      Получить акцию
+// @Tags         Promotions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID акции"
+// @Success      200  {object}  httphelper.APIResponse{data=models.Promotion}
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promotions/{id} [get]
+func (h *PromotionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	p, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get promotion", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, p)
+}
+
+// Create godoc
+// @Summary      Создать акцию
+// @Description  Только для админов. Условия categoryIds (с подкатегориями), tags и productIds объединяются через «или»,
+// @Description  productType дополнительно ограничивает тип товара; без условий акция действует на весь каталог.
+// @Description  Скидка percent — процент от цены, fixed — сумма в рублях.
+// @Tags         Promotions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        promotion  body      models.Promotion  true  "Данные акции"
+// @Success      201        {object}  httphelper.APIResponse{data=models.Promotion}
+// @Failure      400        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promotions [post]
+func (h *PromotionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Create(r.Context(), p); err != nil {
+		h.writeServiceError(w, "promotion creation failed", err)
+		return
+	}
+
+	h.logger.Info("promotion created", zap.String("id", p.ID), zap.String("name", p.Name))
+	httphelper.WriteSuccess(w, http.StatusCreated, p)
+}
+
+// Update godoc
+// @Summary      Обновить акцию
+// @Description  Только для админов. Заменяет акцию целиком.
+// @Tags         Promotions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string            true  "ID акции"
+// @Param        promotion  body      models.Promotion  true  "Данные акции"
+// @Success      200        {object}  httphelper.APIResponse{data=models.Promotion}
+// @Failure      400        {object}  httphelper.APIResponse
+// @Failure      404        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promotions/{id} [put]
+func (h *PromotionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	p, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, p); err != nil {
+		h.writeServiceError(w, "promotion update failed", err)
+		return
+	}
+
+	h.logger.Info("promotion updated", zap.String("id", id))
+	httphelper.WriteSuccess(w, http.StatusOK, p)
+}
+
+// Delete godoc
+// @Summary      Удалить акцию
+// @Tags         Promotions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "ID акции"
+// @Success      204  "No Content"
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promotions/{id} [delete]
+func (h *PromotionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.writeServiceError(w, "promotion deletion failed", err)
+		return
+	}
+
+	h.logger.Info("promotion deleted", zap.String("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Preview godoc
+// @Summary      Предпросмотр акции
+// @Description  Только для админов. Возвращает витринные товары, попадающие под условия акции из тела запроса,
+// @Description  с ценой до и после скидки. Акция не сохраняется; другие акции и период действия не учитываются.
+// @Tags         Promotions
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        promotion  body      models.Promotion  true  "Данные акции"
+// @Success      200        {object}  httphelper.APIResponse{data=[]models.PromotionPreviewItem}
+// @Failure      400        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promotions/preview [post]
+func (h *PromotionHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	h.preview(w, r, p)
+}
+
+// PreviewSaved godoc
+// @Summary      Предпросмотр сохранённой акции
+// @Description  Только для админов. Товары, на которые действует акция, с ценой до и после скидки.
+// @Tags         Promotions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID акции"
+// @Success      200  {object}  httphelper.APIResponse{data=[]models.PromotionPreviewItem}
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/promotions/{id}/preview [get]
+func (h *PromotionHandler) PreviewSaved(w http.ResponseWriter, r *http.Request) {
+	p, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get promotion", err)
+		return
+	}
+	h.preview(w, r, p)
+}
+
+func (h *PromotionHandler) preview(w http.ResponseWriter, r *http.Request, p *models.Promotion) {
+	items, err := h.service.Preview(r.Context(), p)
+	if err != nil {
+		h.writeServiceError(w, "promotion preview failed", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, items)
+}
+
+func (h *PromotionHandler) decode(w http.ResponseWriter, r *http.Request) (*models.Promotion, bool) {
+	var p models.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return nil, false
+	}
+	if err := validation.ValidateStruct(p); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return &p, true
+}
+
+func (h *PromotionHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrPromotionNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Promotion not found")
+	case errors.Is(err, services.ErrInvalidPromotion):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process promotion")
+	}
+}
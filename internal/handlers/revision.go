@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"dozenChairs/internal/dto"
+	_ "dozenChairs/internal/models" // типы для аннотаций swag
+	"dozenChairs/internal/repository"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GetRevisions godoc
+// @Summary      История правок товара
+// @Description  Только для админов. Журнал изменений товара и его изображений от новых к старым: кто, когда и какие поля изменил. Снимки состояния в списке не возвращаются.
+// @Tags         Revisions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug    path      string  true   "Slug товара"
+// @Param        limit   query     int     false  "Лимит (по умолчанию 20)"
+// @Param        offset  query     int     false  "Смещение (по умолчанию 0)"
+// @Success      200     {object}  httphelper.APIResponse{data=[]models.ProductRevision,meta=dto.ListMeta}
+// @Failure      404     {object}  httphelper.APIResponse
+// @Failure      500     {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/{slug}/revisions [get]
+func (h *ProductHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	limit := httphelper.ParseInt(r.URL.Query().Get("limit"), 20)
+	offset := httphelper.ParseInt(r.URL.Query().Get("offset"), 0)
+
+	revisions, total, err := h.service.GetRevisions(r.Context(), slug, limit, offset)
+	if err != nil {
+		h.writeRevisionError(w, "failed to get revisions", err)
+		return
+	}
+
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, revisions, dto.ListMeta{Total: total, Limit: limit, Offset: offset})
+}
+
+// GetRevision godoc
+// @Summary      Получить ревизию товара
+// @Description  Только для админов. Возвращает ревизию вместе со снимком состояния товара после изменения.
+// @Tags         Revisions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug  path      string  true  "Slug товара"
+// @Param        id    path      string  true  "ID ревизии"
+// @Success      200   {object}  models.ProductRevision
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/{slug}/revisions/{id} [get]
+func (h *ProductHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	id := chi.URLParam(r, "id")
+
+	rev, err := h.service.GetRevision(r.Context(), slug, id)
+	if err != nil {
+		h.writeRevisionError(w, "failed to get revision", err)
+		return
+	}
+
+	httphelper.WriteSuccess(w, http.StatusOK, rev)
+}
+
+// Rollback godoc
+// @Summary      Откатить товар к ревизии
+// @Description  Только для админов. Возвращает поля товара к состоянию из снимка ревизии. Изображения и варианты не меняются. Откат записывается в историю новой ревизией.
+// @Tags         Revisions
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug  path      string  true  "Slug товара"
+// @Param        id    path      string  true  "ID ревизии"
+// @Success      200   {object}  models.Product
+// @Failure      400   {object}  httphelper.APIResponse
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      409   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/{slug}/revisions/{id}/rollback [post]
+func (h *ProductHandler) Rollback(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	id := chi.URLParam(r, "id")
+
+	p, err := h.service.Rollback(r.Context(), slug, id)
+	if err != nil {
+		h.writeRevisionError(w, "product rollback failed", err)
+		return
+	}
+
+	h.logger.Info("product rolled back", zap.String("slug", slug), zap.String("revision", id))
+	setProductETag(w, p)
+	httphelper.WriteSuccess(w, http.StatusOK, p)
+}
+
+func (h *ProductHandler) writeRevisionError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, services.ErrRevisionNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Revision not found")
+	case errors.Is(err, services.ErrRevisionNoSnapshot),
+		errors.Is(err, services.ErrInvalidSetItems),
+		errors.Is(err, services.ErrCategoryNotFound):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrAlreadyExists):
+		httphelper.WriteError(w, http.StatusConflict, "Slug already exists")
+	case errors.Is(err, repository.ErrVersionConflict):
+		httphelper.WriteError(w, http.StatusConflict, "Product was modified concurrently, retry")
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process revision")
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type SitemapHandler struct {
+	service services.SitemapService
+	logger  logger.Logger
+}
+
+func NewSitemapHandler(s services.SitemapService, l logger.Logger) *SitemapHandler {
+	return &SitemapHandler{
+		service: s,
+		logger:  l,
+	}
+}
+
+// GetSitemap godoc
+// @Summary      sitemap.xml витрины
+// @Description  Страницы категорий и опубликованных товаров с lastmod и изображениями (image sitemap).
+// @Description  Если адресов больше 50 000, возвращается индекс со ссылками на /sitemap-N.xml.
+// @Description  Перегенерируется при изменении товаров, изображений или категорий.
+// @Tags         SEO
+// @Produce      xml
+// @Success      200
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /sitemap.xml [get]
+func (h *SitemapHandler) GetSitemap(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, services.SitemapName)
+}
+
+// GetSitemapPart godoc
+// @Summary      Часть sitemap
+// @Description  Файл из индекса sitemap.xml, когда адресов больше 50 000.
+// @Tags         SEO
+// @Produce      xml
+// @Param        n    path      int  true  "Номер части, начиная с 1"
+// @Success      200
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /sitemap-{n}.xml [get]
+func (h *SitemapHandler) GetSitemapPart(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "sitemap-"+chi.URLParam(r, "n")+".xml")
+}
+
+func (h *SitemapHandler) serve(w http.ResponseWriter, r *http.Request, name string) {
+	f, err := h.service.Open(r.Context(), name)
+	if err != nil {
+		if errors.Is(err, services.ErrSitemapNotFound) {
+			httphelper.WriteError(w, http.StatusNotFound, "Sitemap not found")
+			return
+		}
+		h.logger.Error("sitemap generation failed", zap.String("file", name), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to generate sitemap")
+		return
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		h.logger.Error("failed to stat sitemap", zap.String("file", name), zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to generate sitemap")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	http.ServeContent(w, r, name, stat.ModTime(), f)
+}
+
+// GetRobotsTxt godoc
+// @Summary      robots.txt
+// @Description  Содержимое файла ROBOTS_TXT_PATH или правила по умолчанию (закрыты пути из ROBOTS_DISALLOW) со ссылкой на sitemap.xml
+// @Tags         SEO
+// @Produce      plain
+// @Success      200  {string}  string
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /robots.txt [get]
+func (h *SitemapHandler) GetRobotsTxt(w http.ResponseWriter, r *http.Request) {
+	content, err := h.service.RobotsTxt()
+	if err != nil {
+		h.logger.Error("failed to read robots.txt", zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to load robots.txt")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(content)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type StockHandler struct {
+	service services.StockService
+	logger  logger.Logger
+}
+
+func NewStockHandler(s services.StockService, l logger.Logger) *StockHandler {
+	return &StockHandler{
+		service: s,
+		logger:  l,
+	}
+}
+
+// GetLevels godoc
+// @Summary      Остатки товара
+// @Description  Только для админов. Остатки товара по складам: без варианта и по каждому варианту.
+// @Description  reserved — зарезервировано под неоплаченные заказы, available — доступно для заказа.
+// @Description  Пустой список — остаток товара не учитывается.
+// @Tags         Stock
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug  path      string  true  "Slug товара"
+// @Success      200   {object}  httphelper.APIResponse{data=[]models.StockLevel}
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/{slug}/stock [get]
+func (h *StockHandler) GetLevels(w http.ResponseWriter, r *http.Request) {
+	levels, err := h.service.GetLevels(r.Context(), chi.URLParam(r, "slug"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get stock levels", err)
+		return
+	}
+
+	httphelper.WriteSuccess(w, http.StatusOK, levels)
+}
+
+// GetMovements godoc
+// @Summary      Журнал движений по складу
+// @Description  Только для админов. Движения остатков товара от новых к старым: поступления, резервы и их снятие,
+// @Description  продажи, возвраты и корректировки — с остатком после движения, причиной, заказом и автором.
+// @Tags         Stock
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug         path      string  true   "Slug товара"
+// @Param        variantId    query     string  false  "ID варианта"
+// @Param        warehouseId  query     string  false  "ID склада"
+// @Param        type         query     string  false  "Вид движения"  Enums(receipt, reservation, release, sale, return, adjustment)
+// @Param        limit        query     int     false  "Лимит (по умолчанию 20)"
+// @Param        offset       query     int     false  "Смещение (по умолчанию 0)"
+// @Success      200          {object}  httphelper.APIResponse{data=[]models.StockMovement,meta=dto.ListMeta}
+// @Failure      404          {object}  httphelper.APIResponse
+// @Failure      500          {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/{slug}/stock/movements [get]
+func (h *StockHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := repository.StockMovementFilter{
+		VariantID:   q.Get("variantId"),
+		WarehouseID: q.Get("warehouseId"),
+		Type:        models.StockMovementType(q.Get("type")),
+		Limit:       httphelper.ParseInt(q.Get("limit"), 20),
+		Offset:      httphelper.ParseInt(q.Get("offset"), 0),
+	}
+
+	movements, total, err := h.service.GetMovements(r.Context(), chi.URLParam(r, "slug"), filter)
+	if err != nil {
+		h.writeServiceError(w, "failed to get stock movements", err)
+		return
+	}
+
+	httphelper.WriteSuccessWithMeta(w, http.StatusOK, movements, dto.ListMeta{Total: total, Limit: filter.Limit, Offset: filter.Offset})
+}
+
+// RecordMovement godoc
+// @Summary      Провести движение по складу
+// @Description  Только для админов. Поступление (receipt), возврат (return) или корректировка (adjustment, quantity со знаком)
+// @Description  на складе warehouseId. Перемещение между складами проводится двумя корректировками.
+// @Description  Для товара с вариантами нужен variantId; у наборов остаток складывается из компонентов и не ведётся.
+// @Description  Если остаток стал бы меньше нуля или зарезервированного, возвращается 409.
+// @Tags         Stock
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                    true  "Slug товара"
+// @Param        request  body      dto.StockMovementRequest  true  "Движение"
+// @Success      201      {object}  httphelper.APIResponse{data=models.StockLevel}
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/products/{slug}/stock/movements [post]
+func (h *StockHandler) RecordMovement(w http.ResponseWriter, r *http.Request) {
+	var req dto.StockMovementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if err := validation.ValidateStruct(req); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slug := chi.URLParam(r, "slug")
+	m := &models.StockMovement{
+		VariantID:   req.VariantID,
+		WarehouseID: req.WarehouseID,
+		Type:        req.Type,
+		Quantity:    req.Quantity,
+		Reason:      req.Reason,
+	}
+	level, err := h.service.Record(r.Context(), slug, m)
+	if err != nil {
+		h.writeServiceError(w, "failed to record stock movement", err)
+		return
+	}
+
+	h.logger.Info("stock movement recorded",
+		zap.String("slug", slug), zap.String("warehouse", m.WarehouseID), zap.String("type", string(m.Type)),
+		zap.Int("quantity", m.Quantity), zap.Int("onHand", level.OnHand))
+	httphelper.WriteSuccess(w, http.StatusCreated, level)
+}
+
+// GetAvailability godoc
+// @Summary      Наличие товара по складам
+// @Description  Где товар есть, где его можно забрать самовывозом и за сколько дней его доставят в город city.
+// @Description  Для товара с вариантами без variantId остатки вариантов складываются; набор доступен на складе
+// @Description  в количестве комплектов, которые собираются из его остатков. Если остаток товара не учитывается
+// @Description  по складам (tracked = false), наличие определяется только флагом inStock.
+// @Tags         Stock
+// @Produce      json
+// @Param        slug       path      string  true   "Slug товара"
+// @Param        variantId  query     string  false  "ID варианта"
+// @Param        city       query     string  false  "Город покупателя"
+// @Success      200        {object}  httphelper.APIResponse{data=models.ProductAvailability}
+// @Failure      404        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/products/{slug}/availability [get]
+func (h *StockHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	availability, err := h.service.GetAvailability(r.Context(), chi.URLParam(r, "slug"), q.Get("variantId"), q.Get("city"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get product availability", err)
+		return
+	}
+
+	httphelper.WriteSuccess(w, http.StatusOK, availability)
+}
+
+func (h *StockHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, services.ErrVariantNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Variant not found")
+	case errors.Is(err, services.ErrWarehouseNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Warehouse not found")
+	case errors.Is(err, services.ErrStockNotTracked), errors.Is(err, services.ErrStockVariantRequired),
+		errors.Is(err, services.ErrInvalidStockMovement):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrInsufficientStock):
+		httphelper.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process stock")
+	}
+}
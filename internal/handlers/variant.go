@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// GetVariants godoc
+// @Summary      Получить варианты товара
+// @Description  Возвращает варианты (SKU) товара с опциями, ценой, остатком и изображениями
+// @Tags         Variants
+// @Produce      json
+// @Param        slug  path      string  true  "Slug товара"
+// @Success      200   {array}   models.ProductVariant
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/products/{slug}/variants [get]
+func (h *ProductHandler) GetVariants(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	variants, err := h.service.GetVariants(r.Context(), slug)
+	if err != nil {
+		h.writeVariantError(w, "failed to get variants", err)
+		return
+	}
+
+	httphelper.WriteSuccess(w, http.StatusOK, variants)
+}
+
+// CreateVariant godoc
+// @Summary      Создать вариант товара
+// @Description  Только для админов. Добавляет вариант с собственным SKU, опциями (например, цвет и обивка), ценой и остатком. Если цена не указана, используется цена товара. Товару, входящему в набор, вариант добавить нельзя (409).
+// @Tags         Variants
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                 true  "Slug товара"
+// @Param        variant  body      models.ProductVariant  true  "Данные варианта"
+// @Success      201      {object}  models.ProductVariant
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/products/{slug}/variants [post]
+func (h *ProductHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+
+	var v models.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	if err := validation.ValidateStruct(v); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.CreateVariant(r.Context(), slug, &v); err != nil {
+		h.writeVariantError(w, "variant creation failed", err)
+		return
+	}
+
+	h.logger.Info("variant created", zap.String("slug", slug), zap.String("sku", v.SKU))
+	httphelper.WriteSuccess(w, http.StatusCreated, v)
+}
+
+// UpdateVariant godoc
+// @Summary      Обновить вариант товара
+// @Description  Только для админов. Остаток и наличие товара пересчитываются по всем его вариантам.
+// @Tags         Variants
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                 true  "Slug товара"
+// @Param        id       path      string                 true  "ID варианта"
+// @Param        variant  body      models.ProductVariant  true  "Обновлённые данные варианта"
+// @Success      200      {object}  models.ProductVariant
+// @Failure      400      {object}  httphelper.APIResponse
+// @Failure      404      {object}  httphelper.APIResponse
+// @Failure      409      {object}  httphelper.APIResponse
+// @Failure      500      {object}  httphelper.APIResponse
+// @Router       /api/v1/products/{slug}/variants/{id} [put]
+func (h *ProductHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	id := chi.URLParam(r, "id")
+
+	var v models.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validation.ValidateStruct(v); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.UpdateVariant(r.Context(), slug, id, &v); err != nil {
+		h.writeVariantError(w, "variant update failed", err)
+		return
+	}
+
+	h.logger.Info("variant updated", zap.String("slug", slug), zap.String("id", id))
+	httphelper.WriteSuccess(w, http.StatusOK, v)
+}
+
+// DeleteVariant godoc
+// @Summary      Удалить вариант товара
+// @Description  Только для админов. Изображения варианта остаются привязанными к товару.
+// @Tags         Variants
+// @Security     BearerAuth
+// @Produce      json
+// @Param        slug  path  string  true  "Slug товара"
+// @Param        id    path  string  true  "ID варианта"
+// @Success      204   "No Content"
+// @Failure      404   {object}  httphelper.APIResponse
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/products/{slug}/variants/{id} [delete]
+func (h *ProductHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.DeleteVariant(r.Context(), slug, id); err != nil {
+		h.writeVariantError(w, "variant deletion failed", err)
+		return
+	}
+
+	h.logger.Info("variant deleted", zap.String("slug", slug), zap.String("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ProductHandler) writeVariantError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrProductNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Product not found")
+	case errors.Is(err, services.ErrVariantNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Variant not found")
+	case errors.Is(err, services.ErrVariantsNotSupported):
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrAlreadyExists):
+		httphelper.WriteError(w, http.StatusConflict, "SKU already exists")
+	case errors.Is(err, repository.ErrReferenced):
+		httphelper.WriteError(w, http.StatusConflict, "Product is included in a set and cannot have variants")
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process variant")
+	}
+}
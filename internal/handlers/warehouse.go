@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/httphelper"
+	"dozenChairs/pkg/logger"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type WarehouseHandler struct {
+	service services.WarehouseService
+	logger  logger.Logger
+}
+
+func NewWarehouseHandler(s services.WarehouseService, l logger.Logger) *WarehouseHandler {
+	return &WarehouseHandler{
+		service: s,
+		logger:  l,
+	}
+}
+
+// GetPickupPoints godoc
+// @Summary      Пункты самовывоза
+// @Description  Работающие склады и шоурумы, где можно забрать заказ, со сроком готовности заказа к выдаче.
+// @Tags         Warehouses
+// @Produce      json
+// @Param        city  query     string  false  "Город"
+// @Success      200   {object}  httphelper.APIResponse{data=[]models.Warehouse}
+// @Failure      500   {object}  httphelper.APIResponse
+// @Router       /api/v1/pickup-points [get]
+func (h *WarehouseHandler) GetPickupPoints(w http.ResponseWriter, r *http.Request) {
+	points, err := h.service.GetPickupPoints(r.Context(), r.URL.Query().Get("city"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get pickup points", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, points)
+}
+
+// GetAll godoc
+// @Summary      Список складов
+// @Description  Только для админов. Все склады и шоурумы, включая выключенные, в порядке резервирования товара под заказ.
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  httphelper.APIResponse{data=[]models.Warehouse}
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/warehouses [get]
+func (h *WarehouseHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	warehouses, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.writeServiceError(w, "failed to get warehouses", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, warehouses)
+}
+
+// GetByID godoc
+// @Summary      Получить склад
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      string  true  "ID склада"
+// @Success      200  {object}  httphelper.APIResponse{data=models.Warehouse}
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/warehouses/{id} [get]
+func (h *WarehouseHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	wh, err := h.service.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.writeServiceError(w, "failed to get warehouse", err)
+		return
+	}
+	httphelper.WriteSuccess(w, http.StatusOK, wh)
+}
+
+// Create godoc
+// @Summary      Создать склад
+// @Description  Только для админов. Склады с меньшим priority резервируют товар под заказ первыми (после складов города покупателя).
+// @Description  deliveryDays — срок курьерской доставки по городу склада, intercityDeliveryDays — в другие города;
+// @Description  без них склад под доставку не резервирует. pickup — склад является пунктом самовывоза.
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        warehouse  body      models.Warehouse  true  "Данные склада"
+// @Success      201        {object}  httphelper.APIResponse{data=models.Warehouse}
+// @Failure      400        {object}  httphelper.APIResponse
+// @Failure      409        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/warehouses [post]
+func (h *WarehouseHandler) Create(w http.ResponseWriter, r *http.Request) {
+	wh, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Create(r.Context(), wh); err != nil {
+		h.writeServiceError(w, "warehouse creation failed", err)
+		return
+	}
+
+	h.logger.Info("warehouse created", zap.String("id", wh.ID), zap.String("code", wh.Code))
+	httphelper.WriteSuccess(w, http.StatusCreated, wh)
+}
+
+// Update godoc
+// @Summary      Обновить склад
+// @Description  Только для админов. Заменяет склад целиком. Остатки выключенного склада не продаются
+// @Description  и не входят в наличие товаров; уже созданные резервы сохраняются.
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string            true  "ID склада"
+// @Param        warehouse  body      models.Warehouse  true  "Данные склада"
+// @Success      200        {object}  httphelper.APIResponse{data=models.Warehouse}
+// @Failure      400        {object}  httphelper.APIResponse
+// @Failure      404        {object}  httphelper.APIResponse
+// @Failure      409        {object}  httphelper.APIResponse
+// @Failure      500        {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/warehouses/{id} [put]
+func (h *WarehouseHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	wh, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Update(r.Context(), id, wh); err != nil {
+		h.writeServiceError(w, "warehouse update failed", err)
+		return
+	}
+
+	h.logger.Info("warehouse updated", zap.String("id", id))
+	httphelper.WriteSuccess(w, http.StatusOK, wh)
+}
+
+// Delete godoc
+// @Summary      Удалить склад
+// @Description  Только для админов. Склад, по которому были движения остатков или оформлены заказы, удалить нельзя — его можно выключить.
+// @Tags         Warehouses
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  string  true  "ID склада"
+// @Success      204  "No Content"
+// @Failure      404  {object}  httphelper.APIResponse
+// @Failure      409  {object}  httphelper.APIResponse
+// @Failure      500  {object}  httphelper.APIResponse
+// @Router       /api/v1/admin/warehouses/{id} [delete]
+func (h *WarehouseHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.writeServiceError(w, "warehouse deletion failed", err)
+		return
+	}
+
+	h.logger.Info("warehouse deleted", zap.String("id", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WarehouseHandler) decode(w http.ResponseWriter, r *http.Request) (*models.Warehouse, bool) {
+	var wh models.Warehouse
+	if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, "Invalid JSON body")
+		return nil, false
+	}
+	if err := validation.ValidateStruct(wh); err != nil {
+		httphelper.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return &wh, true
+}
+
+func (h *WarehouseHandler) writeServiceError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, services.ErrWarehouseNotFound):
+		httphelper.WriteError(w, http.StatusNotFound, "Warehouse not found")
+	case errors.Is(err, services.ErrWarehouseCodeExists), errors.Is(err, services.ErrWarehouseInUse):
+		httphelper.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		httphelper.WriteError(w, http.StatusInternalServerError, "Failed to process warehouse")
+	}
+}
@@ -33,6 +33,11 @@ var (
 		Help: "Количество запросов на получение списка товаров",
 	})
 
+	ProductsSearched = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "products_searched_total",
+		Help: "Количество поисковых запросов по товарам",
+	})
+
 	ProductFetched = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "product_fetched_total",
 		Help: "Количество запросов на получение товара по slug",
@@ -47,10 +52,40 @@ var (
 		Help: "Количество удалённых товаров",
 	})
 
+	ProductsPurged = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "products_purged_total",
+		Help: "Количество товаров, окончательно удалённых из корзины",
+	})
+
 	OAuthLoginTotal = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "oauth_login_total",
 		Help: "Количество логинов через OAuth-провайдеров",
 	})
+
+	ProductStatusTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "product_status_transitions_total",
+		Help: "Количество смен статуса товаров планировщиком публикаций",
+	}, []string{"from", "to"})
+
+	FeedsGenerated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "feeds_generated_total",
+		Help: "Количество генераций фидов для маркетплейсов",
+	}, []string{"feed"})
+
+	GuestCartsPurged = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "guest_carts_purged_total",
+		Help: "Количество удалённых устаревших гостевых корзин покупателей",
+	})
+
+	StockReservationsExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "stock_reservations_expired_total",
+		Help: "Количество заказов, резерв товаров которых снят по истечении срока",
+	})
+
+	PaymentWebhooks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "payment_webhooks_total",
+		Help: "Количество принятых уведомлений платёжных провайдеров",
+	}, []string{"provider", "event"})
 )
 
 func Init() {
@@ -61,9 +96,16 @@ func Init() {
 		ImagesUploaded,
 		ProductsCreated,
 		ProductsFetched,
+		ProductsSearched,
 		ProductFetched,
 		ProductsUpdated,
 		ProductsDeleted,
+		ProductsPurged,
 		OAuthLoginTotal,
+		ProductStatusTransitions,
+		FeedsGenerated,
+		GuestCartsPurged,
+		StockReservationsExpired,
+		PaymentWebhooks,
 	)
 }
@@ -67,6 +67,28 @@ func RequireRole(requiredRole string) func(http.Handler) http.Handler {
 	}
 }
 
+// OptionalAuth — как RequireAuth, но запрос без заголовка Authorization пропускается как гостевой
+// (без пользователя в контексте). Неверный или истёкший токен по-прежнему отклоняется.
+func OptionalAuth(jwt *auth.JWTManager) func(http.Handler) http.Handler {
+	requireAuth := RequireAuth(jwt)
+	return func(next http.Handler) http.Handler {
+		withAuth := requireAuth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" && config.LoadConfig().AuthEnabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withAuth.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CurrentUserID возвращает ID пользователя из контекста запроса или пустую строку для гостя
+func CurrentUserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
+
 func Role() contextKey {
 	return roleKey
 }
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// MaxCartItemQuantity — наибольшее количество одного товара в позиции корзины
+const MaxCartItemQuantity = 999
+
+// Cart — корзина покупателя с ценами, пересчитанными по текущим ценам витрины
+type Cart struct {
+	// ID пуст, пока в корзину ничего не добавляли
+	ID    string     `json:"id,omitempty"`
+	Items []CartItem `json:"items"`
+	// Quantity — общее количество единиц товара в корзине
+	Quantity int `json:"quantity"`
+	// Subtotal — стоимость доступных к заказу позиций по ценам без скидок, Total — с учётом акций
+	Subtotal int `json:"subtotal"`
+	Discount int `json:"discount"`
+	Total    int `json:"total"`
+	// Warnings — позиции, которые нельзя заказать в текущем виде; они не входят в суммы
+	Warnings  []CartWarning `json:"warnings,omitempty"`
+	UpdatedAt *time.Time    `json:"updatedAt,omitempty"`
+}
+
+// CartItem — позиция корзины: товар, набор или вариант товара
+type CartItem struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"productId"`
+	VariantID *string           `json:"variantId,omitempty"`
+	Quantity  int               `json:"quantity"`
+	Type      ProductType       `json:"type,omitempty"`
+	Slug      string            `json:"slug,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	SKU       string            `json:"sku,omitempty"`
+	Options   map[string]string `json:"options,omitempty"`
+	Image     string            `json:"image,omitempty"`
+	Price     int               `json:"price"`
+	OldPrice  *int              `json:"oldPrice,omitempty"`
+	LineTotal int               `json:"lineTotal"`
+	// Available — позицию можно заказать: товар опубликован и есть в наличии в нужном количестве
+	Available bool      `json:"available"`
+	AddedAt   time.Time `json:"addedAt"`
+}
+
+// CartWarningCode — причина, по которой позицию корзины нельзя заказать
+type CartWarningCode string
+
+const (
+	CartItemUnavailable  CartWarningCode = "unavailable"        // товар снят с продажи или удалён
+	CartItemOutOfStock   CartWarningCode = "out_of_stock"       // товара нет в наличии
+	CartItemInsufficient CartWarningCode = "insufficient_stock" // в наличии меньше, чем в корзине
+)
+
+type CartWarning struct {
+	ItemID    string          `json:"itemId"`
+	ProductID string          `json:"productId"`
+	Code      CartWarningCode `json:"code"`
+	// Available — сколько единиц есть в наличии (для insufficient_stock)
+	Available *int   `json:"available,omitempty"`
+	Message   string `json:"message"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Category struct {
+	ID          string      `json:"id"`
+	ParentID    *string     `json:"parentId,omitempty" validate:"omitempty,uuid"`
+	Name        string      `json:"name" validate:"required"`
+	Slug        string      `json:"slug" validate:"required"`
+	Description string      `json:"description,omitempty"`
+	ImageURL    string      `json:"imageUrl,omitempty"`
+	SortOrder   int         `json:"sortOrder"`
+	Children    []*Category `json:"children,omitempty"` // заполняется только в дереве категорий
+	CreatedAt   time.Time   `json:"createdAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
+}
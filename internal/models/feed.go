@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// FeedInfo — сведения о сгенерированном фиде для маркетплейса
+type FeedInfo struct {
+	Name        string    `json:"name"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Offers      int       `json:"offers"` // количество выгруженных товаров
+	Size        int64     `json:"size"`   // размер файла в байтах
+	// Skipped — товары, не попавшие в фид из-за незаполненных обязательных полей
+	Skipped []FeedSkippedItem `json:"skipped,omitempty"`
+}
+
+// FeedSkippedItem — товар, пропущенный при генерации фида, и причины пропуска
+type FeedSkippedItem struct {
+	ID      string   `json:"id"`
+	Slug    string   `json:"slug"`
+	Missing []string `json:"missing"` // незаполненные обязательные поля фида
+}
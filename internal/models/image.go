@@ -5,6 +5,7 @@ import "time"
 type Image struct {
 	ID        string    `json:"id"`
 	ProductID string    `json:"product_id"`
+	VariantID *string   `json:"variant_id,omitempty"`
 	URL       string    `json:"url"`
 	Filename  string    `json:"filename"`
 	CreatedAt time.Time `json:"created_at"`
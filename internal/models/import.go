@@ -0,0 +1,28 @@
+package models
+
+// ImportRowStatus — результат обработки строки файла импорта
+type ImportRowStatus string
+
+const (
+	ImportCreated ImportRowStatus = "created"
+	ImportUpdated ImportRowStatus = "updated"
+	ImportFailed  ImportRowStatus = "failed"
+)
+
+// ImportReport — отчёт об импорте товаров из CSV/XLSX
+type ImportReport struct {
+	DryRun  bool              `json:"dryRun"` // изменения проверены, но не сохранены
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult — результат по одной строке файла
+type ImportRowResult struct {
+	Row    int             `json:"row"` // номер строки в файле, считая заголовок
+	Slug   string          `json:"slug,omitempty"`
+	Status ImportRowStatus `json:"status"`
+	Errors []string        `json:"errors,omitempty"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import "time"
+
+// OrderStatus — статус заказа
+type OrderStatus string
+
+const (
+	OrderNew             OrderStatus = "new"              // оформлен, ждёт подтверждения
+	OrderAwaitingPayment OrderStatus = "awaiting_payment" // ждёт оплаты
+	OrderPaid            OrderStatus = "paid"             // оплачен
+	OrderAssembling      OrderStatus = "assembling"       // собирается на складе
+	OrderShipped         OrderStatus = "shipped"          // передан в доставку
+	OrderDelivered       OrderStatus = "delivered"        // получен покупателем
+	OrderCancelled       OrderStatus = "cancelled"        // отменён до оплаты
+	OrderRefunded        OrderStatus = "refunded"         // деньги за оплаченный заказ возвращены
+)
+
+// orderTransitions — допустимые переходы между статусами; cancelled и refunded — конечные
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderNew:             {OrderAwaitingPayment, OrderCancelled},
+	OrderAwaitingPayment: {OrderPaid, OrderCancelled},
+	OrderPaid:            {OrderAssembling, OrderRefunded},
+	OrderAssembling:      {OrderShipped, OrderRefunded},
+	OrderShipped:         {OrderDelivered, OrderRefunded},
+	OrderDelivered:       {OrderRefunded},
+}
+
+// Valid сообщает, известен ли статус
+func (s OrderStatus) Valid() bool {
+	_, ok := orderTransitions[s]
+	return ok || s == OrderCancelled || s == OrderRefunded
+}
+
+// CanTransitionTo сообщает, можно ли перевести заказ из статуса s в статус to
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	for _, next := range orderTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStatuses возвращает статусы, в которые можно перевести заказ из статуса s
+func (s OrderStatus) NextStatuses() []OrderStatus {
+	return append([]OrderStatus{}, orderTransitions[s]...)
+}
+
+// DeliveryMethod — способ получения заказа
+type DeliveryMethod string
+
+const (
+	DeliveryCourier DeliveryMethod = "courier"
+	DeliveryPickup  DeliveryMethod = "pickup"
+)
+
+// Order — заказ покупателя. Состав и цены — снимок на момент оформления.
+type Order struct {
+	ID string `json:"id"`
+	// Number — короткий номер заказа для покупателя и поддержки
+	Number int64       `json:"number"`
+	UserID string      `json:"userId"`
+	Status OrderStatus `json:"status"`
+	// NextStatuses — статусы, в которые заказ можно перевести сейчас
+	NextStatuses   []OrderStatus  `json:"nextStatuses"`
+	CustomerName   string         `json:"customerName"`
+	Email          string         `json:"email"`
+	Phone          string         `json:"phone"`
+	DeliveryMethod DeliveryMethod `json:"deliveryMethod"`
+	City           string         `json:"city"`
+	Address        string         `json:"address,omitempty"`
+	PickupPointID  *string        `json:"pickupPointId,omitempty"` // склад или шоурум, где покупатель заберёт заказ (DeliveryPickup)
+	Comment        string         `json:"comment,omitempty"`
+	Items          []OrderItem    `json:"items"`
+	// Subtotal — стоимость по ценам без скидок, Discount — скидка по акциям,
+	// PromoDiscount — скидка по промокоду PromoCode; Total — к оплате
+	Subtotal      int                 `json:"subtotal"`
+	Discount      int                 `json:"discount"`
+	PromoCode     *string             `json:"promoCode,omitempty"`
+	PromoDiscount int                 `json:"promoDiscount"`
+	Total         int                 `json:"total"`
+	ReservedUntil *time.Time          `json:"reservedUntil,omitempty"` // до этого времени товары зарезервированы; неоплаченный заказ затем отменяется
+	History       []OrderStatusChange `json:"history,omitempty"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+}
+
+// OrderItem — позиция заказа: снимок товара или варианта на момент оформления
+type OrderItem struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"productId"`
+	VariantID *string           `json:"variantId,omitempty"`
+	Type      ProductType       `json:"type"`
+	Slug      string            `json:"slug"`
+	Title     string            `json:"title"`
+	SKU       string            `json:"sku,omitempty"`
+	Options   map[string]string `json:"options,omitempty"`
+	// Price — цена витрины с учётом акций, OldPrice — зачёркнутая цена
+	Price    int  `json:"price"`
+	OldPrice *int `json:"oldPrice,omitempty"`
+	Quantity int  `json:"quantity"`
+	// PromoDiscount — доля скидки по промокоду, LineTotal — стоимость позиции с её учётом
+	PromoDiscount int `json:"promoDiscount"`
+	LineTotal     int `json:"lineTotal"`
+}
+
+// OrderStatusChange — запись истории статусов заказа
+type OrderStatusChange struct {
+	ID string `json:"id"`
+	// From пуст у записи о создании заказа
+	From *OrderStatus `json:"from,omitempty"`
+	To   OrderStatus  `json:"to"`
+	// ChangedBy — ID пользователя; nil — переход выполнила система
+	ChangedBy *string   `json:"changedBy,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	ChangedAt time.Time `json:"changedAt"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// PaymentStatus — статус платежа (совпадает со статусами YooKassa)
+type PaymentStatus string
+
+const (
+	PaymentPending           PaymentStatus = "pending"             // создан, покупатель ещё не оплатил
+	PaymentWaitingForCapture PaymentStatus = "waiting_for_capture" // деньги заблокированы, ждут списания
+	PaymentSucceeded         PaymentStatus = "succeeded"           // оплачен
+	PaymentCanceled          PaymentStatus = "canceled"            // отменён или не прошёл
+)
+
+// Final сообщает, что статус платежа больше не изменится
+func (s PaymentStatus) Final() bool {
+	return s == PaymentSucceeded || s == PaymentCanceled
+}
+
+// Payment — оплата заказа через платёжного провайдера
+type Payment struct {
+	ID      string `json:"id"`
+	OrderID string `json:"orderId"`
+	// Provider — имя провайдера, ExternalID — ID платежа у провайдера
+	Provider   string        `json:"provider"`
+	ExternalID string        `json:"externalId"`
+	Status     PaymentStatus `json:"status"`
+	Amount     int           `json:"amount"`
+	Currency   string        `json:"currency"`
+	// ConfirmationURL — страница оплаты, на которую нужно отправить покупателя
+	ConfirmationURL string `json:"confirmationUrl,omitempty"`
+	// RefundedAmount — сумма проведённых возвратов
+	RefundedAmount int        `json:"refundedAmount"`
+	PaidAt         *time.Time `json:"paidAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
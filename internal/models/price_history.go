@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// PricePoint — цена товара, установленная в момент ChangedAt
+type PricePoint struct {
+	Price     int       `json:"price"`
+	OldPrice  *int      `json:"oldPrice,omitempty"`
+	ChangedAt time.Time `json:"changedAt"`
+}
@@ -11,26 +11,100 @@ const (
 	TypeSet     ProductType = "set"
 )
 
+// ProductStatus — этап жизненного цикла товара; на витрине видны только опубликованные
+type ProductStatus string
+
+const (
+	StatusDraft     ProductStatus = "draft"
+	StatusPublished ProductStatus = "published"
+	StatusArchived  ProductStatus = "archived"
+)
+
 type Product struct {
-	ID          string                 `json:"id" validate:"required"` // можно добавить `uuid4` при необходимости
-	Type        ProductType            `json:"type" validate:"required,oneof=product set"`
-	Category    string                 `json:"category" validate:"required"`
-	Title       string                 `json:"title" validate:"required"`
-	Slug        string                 `json:"slug" validate:"required"` // можно добавить custom slug-валидацию
-	Description string                 `json:"description,omitempty"`
-	Price       int                    `json:"price" validate:"gte=0"`
-	OldPrice    *int                   `json:"oldPrice,omitempty" validate:"omitempty,gte=0"`
-	InStock     bool                   `json:"inStock"`
-	UnitCount   *int                   `json:"unitCount,omitempty" validate:"omitempty,gte=0"`
-	Images      []Image                `json:"images"`
-	Attributes  map[string]interface{} `json:"attributes,omitempty"`
-	Includes    []IncludeItem          `json:"includes,omitempty" validate:"omitempty,dive"` // только для sets
-	Tags        []string               `json:"tags,omitempty"`
-	CreatedAt   time.Time              `json:"createdAt"`
-	UpdatedAt   time.Time              `json:"updatedAt"`
+	ID          string      `json:"id" validate:"required"` // можно добавить `uuid4` при необходимости
+	Type        ProductType `json:"type" validate:"required,oneof=product set"`
+	CategoryID  string      `json:"categoryId" validate:"omitempty,uuid"`
+	Category    string      `json:"category" validate:"required_without=CategoryID"` // название категории; при создании можно передать slug вместо categoryId
+	Title       string      `json:"title" validate:"required"`
+	Slug        string      `json:"slug" validate:"omitempty,slug"` // если не указан, генерируется из title
+	Description string      `json:"description,omitempty"`
+	Price       int         `json:"price" validate:"gte=0"`
+	OldPrice    *int        `json:"oldPrice,omitempty" validate:"omitempty,gte=0"`
+	// LowestPrice30Days — минимальная цена за последние 30 дней по истории цен (только для чтения)
+	LowestPrice30Days *int                   `json:"lowestPrice30Days,omitempty"`
+	InStock           bool                   `json:"inStock"`
+	UnitCount         *int                   `json:"unitCount,omitempty" validate:"omitempty,gte=0"` // доступный остаток по журналу склада; nil — остаток не учитывается
+	Images            []Image                `json:"images"`
+	Attributes        map[string]interface{} `json:"attributes,omitempty"`
+	Includes          []IncludeItem          `json:"includes,omitempty" validate:"omitempty,dive"` // только для sets
+	// SetDiscountPercent — скидка набора в процентах; если задана, цена набора считается как сумма компонентов минус скидка
+	SetDiscountPercent *int             `json:"setDiscountPercent,omitempty" validate:"omitempty,gte=0,lte=100"`
+	Tags               []string         `json:"tags,omitempty"`
+	Variants           []ProductVariant `json:"variants,omitempty"`
+	PriceRange         *PriceRange      `json:"priceRange,omitempty"` // диапазон цен по вариантам
+	// Promotions — акции, учтённые в Price (только для чтения, на витрине)
+	Promotions  []AppliedPromotion `json:"promotions,omitempty"`
+	Status      ProductStatus      `json:"status" validate:"omitempty,oneof=draft published archived"`
+	PublishAt   *time.Time         `json:"publishAt,omitempty"`   // черновик будет опубликован в это время
+	UnpublishAt *time.Time         `json:"unpublishAt,omitempty"` // опубликованный товар уйдёт в архив в это время
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+	DeletedAt   *time.Time         `json:"deletedAt,omitempty"` // время перемещения в корзину
+	Version     int                `json:"version"`             // растёт при каждом изменении, отдаётся в ETag
+}
+
+// Visible сообщает, виден ли товар на витрине в момент now
+func (p *Product) Visible(now time.Time) bool {
+	return p.Status == StatusPublished &&
+		(p.PublishAt == nil || !p.PublishAt.After(now)) &&
+		(p.UnpublishAt == nil || p.UnpublishAt.After(now))
+}
+
+// StatusTransition — смена статуса товара планировщиком публикаций
+type StatusTransition struct {
+	ProductID string
+	Slug      string
+	From      ProductStatus
+	To        ProductStatus
 }
 
 type IncludeItem struct {
-	ProductID string `json:"productId" validate:"required"`
-	Quantity  int    `json:"quantity"  validate:"required,gt=0"`
+	ProductID string   `json:"productId" validate:"required"`
+	Quantity  int      `json:"quantity"  validate:"required,gt=0"`
+	Product   *Product `json:"product,omitempty" validate:"-"` // раскрытые данные компонента (GET набора)
+}
+
+// ProductSearchResult — товар, найденный полнотекстовым поиском, с рангом и подсветкой совпадений
+type ProductSearchResult struct {
+	*Product
+	Rank      float32         `json:"rank"`
+	Highlight SearchHighlight `json:"highlight"`
+}
+
+type SearchHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// ProductFacets — агрегаты по отфильтрованному списку товаров для построения боковой панели каталога
+type ProductFacets struct {
+	Attributes map[string][]FacetValue `json:"attributes"`
+	Price      PriceRange              `json:"price"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type PriceRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// ProductPage — страница списка товаров с общим количеством и курсором следующей страницы
+type ProductPage struct {
+	Items      []*Product `json:"items"`
+	Total      int        `json:"total"`
+	NextCursor string     `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// PromoCode — промокод, который покупатель вводит при оформлении заказа.
+// Скидка считается от суммы подходящих товаров: из категорий CategoryIDs (с подкатегориями)
+// или из списка ProductIDs; без этих условий подходят все товары заказа.
+type PromoCode struct {
+	ID            string       `json:"id"`
+	Code          string       `json:"code" validate:"required,max=64"`
+	Description   string       `json:"description,omitempty"`
+	DiscountType  DiscountType `json:"discountType" validate:"required,oneof=percent fixed"`
+	DiscountValue int          `json:"discountValue" validate:"required,gt=0"`
+	// MinOrderAmount — минимальная сумма заказа (с учётом акций, без промокода)
+	MinOrderAmount int      `json:"minOrderAmount" validate:"gte=0"`
+	CategoryIDs    []string `json:"categoryIds,omitempty" validate:"omitempty,dive,uuid"`
+	ProductIDs     []string `json:"productIds,omitempty"`
+	// UsageLimit — сколько раз код можно применить всего, PerUserLimit — одному покупателю; nil — без ограничения
+	UsageLimit   *int       `json:"usageLimit,omitempty" validate:"omitempty,gt=0"`
+	PerUserLimit *int       `json:"perUserLimit,omitempty" validate:"omitempty,gt=0"`
+	UsedCount    int        `json:"usedCount"`
+	StartsAt     *time.Time `json:"startsAt,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	Enabled      bool       `json:"enabled"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// PromoCodeRedemption — одно применение промокода
+type PromoCodeRedemption struct {
+	ID          string    `json:"id"`
+	PromoCodeID string    `json:"promoCodeId"`
+	UserID      string    `json:"userId"`
+	OrderID     *string   `json:"orderId,omitempty"`
+	Discount    int       `json:"discount"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// OrderItemInput — позиция заказа, для которой считается цена
+type OrderItemInput struct {
+	ProductID string  `json:"productId" validate:"required"`
+	VariantID *string `json:"variantId,omitempty" validate:"omitempty,uuid"`
+	Quantity  int     `json:"quantity" validate:"required,gt=0"`
+}
+
+// PromoCodeQuote — расчёт заказа с промокодом
+type PromoCodeQuote struct {
+	PromoCodeID string               `json:"-"`
+	Code        string               `json:"code"`
+	Items       []PromoCodeQuoteItem `json:"items"`
+	Subtotal    int                  `json:"subtotal"`
+	// EligibleSubtotal — сумма позиций, на которые действует промокод
+	EligibleSubtotal int `json:"eligibleSubtotal"`
+	Discount         int `json:"discount"`
+	Total            int `json:"total"`
+}
+
+// PromoCodeQuoteItem — позиция расчёта; Price — цена витрины с учётом акций
+type PromoCodeQuoteItem struct {
+	ProductID string  `json:"productId"`
+	VariantID *string `json:"variantId,omitempty"`
+	Title     string  `json:"title"`
+	Quantity  int     `json:"quantity"`
+	Price     int     `json:"price"`
+	Total     int     `json:"total"`
+	Discount  int     `json:"discount"`
+	Eligible  bool    `json:"eligible"`
+}
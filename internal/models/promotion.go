@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// DiscountType — способ расчёта скидки
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent" // процент от цены
+	DiscountFixed   DiscountType = "fixed"   // фиксированная сумма в рублях
+)
+
+// Promotion — акция: скидка на товары, попавшие под условия, в период действия.
+// Условия CategoryIDs, Tags и ProductIDs объединяются через «или» (товар подходит, если выполнено любое),
+// ProductType дополнительно сужает выборку. Без условий акция действует на весь каталог.
+type Promotion struct {
+	ID            string       `json:"id"`
+	Name          string       `json:"name" validate:"required"`
+	Description   string       `json:"description,omitempty"`
+	DiscountType  DiscountType `json:"discountType" validate:"required,oneof=percent fixed"`
+	DiscountValue int          `json:"discountValue" validate:"required,gt=0"`
+	CategoryIDs   []string     `json:"categoryIds,omitempty" validate:"omitempty,dive,uuid"` // включая подкатегории
+	Tags          []string     `json:"tags,omitempty"`
+	ProductIDs    []string     `json:"productIds,omitempty"` // товары и наборы
+	ProductType   *ProductType `json:"productType,omitempty" validate:"omitempty,oneof=product set"`
+	StartsAt      *time.Time   `json:"startsAt,omitempty"`
+	EndsAt        *time.Time   `json:"endsAt,omitempty"`
+	// Priority — акции с большим приоритетом применяются первыми
+	Priority int `json:"priority"`
+	// Stackable — акция суммируется с другими суммируемыми акциями;
+	// несуммируемая акция с наибольшим приоритетом действует одна
+	Stackable bool      `json:"stackable"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ActiveAt сообщает, действует ли акция в момент now
+func (p *Promotion) ActiveAt(now time.Time) bool {
+	return p.Enabled &&
+		(p.StartsAt == nil || !p.StartsAt.After(now)) &&
+		(p.EndsAt == nil || p.EndsAt.After(now))
+}
+
+// AppliedPromotion — акция, применённая к цене товара в ответе API
+type AppliedPromotion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PromotionPreviewItem — товар, на который подействует акция, с ценой до и после скидки
+type PromotionPreviewItem struct {
+	ProductID     string      `json:"productId"`
+	Slug          string      `json:"slug"`
+	Title         string      `json:"title"`
+	Type          ProductType `json:"type"`
+	Price         int         `json:"price"`
+	DiscountPrice int         `json:"discountPrice"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// RevisionAction — вид изменения товара в журнале правок
+type RevisionAction string
+
+const (
+	RevisionCreate       RevisionAction = "create"
+	RevisionUpdate       RevisionAction = "update"
+	RevisionDelete       RevisionAction = "delete"
+	RevisionRestore      RevisionAction = "restore"
+	RevisionRollback     RevisionAction = "rollback"
+	RevisionImageAdded   RevisionAction = "image_added"
+	RevisionImageDeleted RevisionAction = "image_deleted"
+)
+
+// ProductRevision — запись журнала правок товара: кто, когда и какие поля изменил
+type ProductRevision struct {
+	ID        string                 `json:"id"`
+	ProductID string                 `json:"productId"`
+	Action    RevisionAction         `json:"action"`
+	UserID    *string                `json:"userId,omitempty"`
+	Changes   map[string]FieldChange `json:"changes"`
+	// Snapshot — состояние товара после изменения; по нему выполняется откат
+	Snapshot *Product `json:"snapshot,omitempty"`
+	// SourceRevisionID — ревизия, к которой выполнен откат (для action = rollback)
+	SourceRevisionID *string   `json:"sourceRevisionId,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// FieldChange — значение поля до и после изменения
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// StockMovementType — вид движения по складу
+type StockMovementType string
+
+const (
+	StockReceipt     StockMovementType = "receipt"     // поступление на склад
+	StockReservation StockMovementType = "reservation" // резерв под оформленный заказ
+	StockRelease     StockMovementType = "release"     // снятие резерва: заказ отменён или резерв истёк
+	StockSale        StockMovementType = "sale"        // продажа: заказ оплачен, резерв списан со склада
+	StockReturn      StockMovementType = "return"      // возврат товара на склад
+	StockAdjustment  StockMovementType = "adjustment"  // ручная корректировка (инвентаризация, брак)
+)
+
+// StockLevel — остаток товара без вариантов (VariantID nil) или варианта на складе
+type StockLevel struct {
+	ProductID   string  `json:"productId"`
+	VariantID   *string `json:"variantId,omitempty"`
+	WarehouseID string  `json:"warehouseId"`
+	// OnHand — единиц на складе, Reserved — из них зарезервировано под неоплаченные заказы,
+	// Available — доступно для заказа
+	OnHand    int       `json:"onHand"`
+	Reserved  int       `json:"reserved"`
+	Available int       `json:"available"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// StockMovement — запись журнала движений по складу.
+// Quantity — количество единиц; у корректировки — изменение остатка со знаком.
+type StockMovement struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"productId"`
+	VariantID *string           `json:"variantId,omitempty"`
+	Type      StockMovementType `json:"type"`
+	Quantity  int               `json:"quantity"`
+	// WarehouseID — склад, остаток которого изменился
+	WarehouseID string `json:"warehouseId"`
+	// OnHandAfter и ReservedAfter — остаток после движения
+	OnHandAfter   int     `json:"onHandAfter"`
+	ReservedAfter int     `json:"reservedAfter"`
+	Reason        string  `json:"reason,omitempty"`
+	OrderID       *string `json:"orderId,omitempty"`
+	// ActorID — ID пользователя; nil — движение выполнила система
+	ActorID   *string   `json:"actorId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
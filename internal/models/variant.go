@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ProductVariant — вариант товара (SKU) с собственными опциями, ценой и остатком
+type ProductVariant struct {
+	ID        string            `json:"id"`
+	ProductID string            `json:"productId"`
+	SKU       string            `json:"sku" validate:"required"`
+	Options   map[string]string `json:"options" validate:"required,min=1"`             // например, {"color": "белый", "fabric": "велюр"}
+	Price     *int              `json:"price,omitempty" validate:"omitempty,gte=0"`    // если не задана — цена товара
+	OldPrice  *int              `json:"oldPrice,omitempty" validate:"omitempty,gte=0"` // если не задана — старая цена товара
+	UnitCount int               `json:"unitCount" validate:"gte=0"`                    // доступный остаток по журналу склада; при создании — начальный
+	InStock   bool              `json:"inStock"`
+	SortOrder int               `json:"sortOrder"`
+	Images    []Image           `json:"images,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
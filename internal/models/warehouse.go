@@ -0,0 +1,78 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WarehouseKind — вид точки хранения товара
+type WarehouseKind string
+
+const (
+	WarehouseStock    WarehouseKind = "warehouse" // склад
+	WarehouseShowroom WarehouseKind = "showroom"  // шоурум: выставочные образцы, обычно с самовывозом
+)
+
+// Warehouse — склад или шоурум, на котором хранится товар
+type Warehouse struct {
+	ID      string        `json:"id"`
+	Code    string        `json:"code" validate:"required,max=50"`
+	Name    string        `json:"name" validate:"required"`
+	Kind    WarehouseKind `json:"kind" validate:"required,oneof=warehouse showroom"`
+	City    string        `json:"city" validate:"required"`
+	Address string        `json:"address,omitempty"`
+	// Pickup — здесь можно забрать заказ самовывозом; PickupDays — через сколько дней заказ готов к выдаче
+	Pickup     bool `json:"pickup"`
+	PickupDays int  `json:"pickupDays" validate:"gte=0"`
+	// DeliveryDays — срок курьерской доставки по городу склада, IntercityDeliveryDays — в другие города;
+	// nil — склад туда не отгружает
+	DeliveryDays          *int `json:"deliveryDays,omitempty" validate:"omitempty,gte=0"`
+	IntercityDeliveryDays *int `json:"intercityDeliveryDays,omitempty" validate:"omitempty,gte=0"`
+	// Priority — склады с меньшим приоритетом резервируют товар под заказ первыми
+	Priority int `json:"priority"`
+	// Active — склад работает; остатки неактивного склада не продаются
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DeliveryDaysTo возвращает срок курьерской доставки со склада в город city; false — склад туда не отгружает
+func (w *Warehouse) DeliveryDaysTo(city string) (int, bool) {
+	days := w.IntercityDeliveryDays
+	if SameCity(w.City, city) {
+		days = w.DeliveryDays
+	}
+	if days == nil {
+		return 0, false
+	}
+	return *days, true
+}
+
+// SameCity сравнивает названия городов без учёта регистра и пробелов по краям
+func SameCity(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// WarehouseAvailability — наличие товара на складе
+type WarehouseAvailability struct {
+	Warehouse Warehouse `json:"warehouse"`
+	Available int       `json:"available"`
+	// DeliveryDays — срок доставки с этого склада в запрошенный город; nil — доставки нет или город не задан
+	DeliveryDays *int `json:"deliveryDays,omitempty"`
+}
+
+// ProductAvailability — где товар можно забрать и за сколько дней его доставят
+type ProductAvailability struct {
+	ProductID string  `json:"productId"`
+	VariantID *string `json:"variantId,omitempty"`
+	City      string  `json:"city,omitempty"`
+	// Tracked — остаток товара учитывается по складам; иначе наличие задано только флагом InStock
+	Tracked bool `json:"tracked"`
+	InStock bool `json:"inStock"`
+	// Warehouses — склады, на которых товар есть, в порядке резервирования
+	Warehouses []WarehouseAvailability `json:"warehouses"`
+	// PickupPoints — пункты самовывоза, где товар есть (в городе City, если он задан)
+	PickupPoints []WarehouseAvailability `json:"pickupPoints"`
+	// DeliveryDays — минимальный срок курьерской доставки в город City; nil — доставка невозможна
+	DeliveryDays *int `json:"deliveryDays,omitempty"`
+}
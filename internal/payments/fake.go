@@ -0,0 +1,139 @@
+package payments
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Fake — встроенный провайдер для разработки и тестов: платежи хранятся в памяти процесса,
+// деньги никуда не уходят. С autoSucceed платёж сразу считается оплаченным,
+// иначе его статус меняют через SetStatus.
+type Fake struct {
+	mu          sync.Mutex
+	payments    map[string]*Payment
+	byKey       map[string]string // ключ идемпотентности -> ID платежа или возврата
+	refunds     map[string]*Refund
+	autoSucceed bool
+}
+
+func NewFake(autoSucceed bool) *Fake {
+	return &Fake{
+		payments:    map[string]*Payment{},
+		byKey:       map[string]string{},
+		refunds:     map[string]*Refund{},
+		autoSucceed: autoSucceed,
+	}
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) CreatePayment(_ context.Context, req CreatePaymentRequest) (*Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id, ok := f.byKey[req.IdempotenceKey]; ok && f.payments[id] != nil {
+		return f.copyPayment(id), nil
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("fake: amount must be positive")
+	}
+
+	p := &Payment{
+		ID:              "fake-" + uuid.NewString(),
+		Status:          models.PaymentPending,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		ConfirmationURL: req.ReturnURL, // страницы оплаты нет: покупатель сразу возвращается в магазин
+		Metadata:        req.Metadata,
+	}
+	if f.autoSucceed {
+		p.Status = models.PaymentSucceeded
+	}
+	f.payments[p.ID] = p
+	if req.IdempotenceKey != "" {
+		f.byKey[req.IdempotenceKey] = p.ID
+	}
+	return f.copyPayment(p.ID), nil
+}
+
+func (f *Fake) GetPayment(_ context.Context, id string) (*Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.payments[id] == nil {
+		return nil, ErrPaymentNotFound
+	}
+	return f.copyPayment(id), nil
+}
+
+func (f *Fake) Refund(_ context.Context, req RefundRequest) (*Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id, ok := f.byKey[req.IdempotenceKey]; ok && f.refunds[id] != nil {
+		r := *f.refunds[id]
+		return &r, nil
+	}
+	p := f.payments[req.PaymentID]
+	if p == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if p.Status != models.PaymentSucceeded {
+		return nil, fmt.Errorf("fake: payment %s is %s, only succeeded payments can be refunded", p.ID, p.Status)
+	}
+	if req.Amount <= 0 || p.RefundedAmount+req.Amount > p.Amount {
+		return nil, fmt.Errorf("fake: refund amount %d exceeds the refundable %d", req.Amount, p.Amount-p.RefundedAmount)
+	}
+
+	p.RefundedAmount += req.Amount
+	r := &Refund{ID: "fake-refund-" + uuid.NewString(), PaymentID: p.ID, Status: "succeeded", Amount: req.Amount}
+	f.refunds[r.ID] = r
+	if req.IdempotenceKey != "" {
+		f.byKey[req.IdempotenceKey] = r.ID
+	}
+	out := *r
+	return &out, nil
+}
+
+// VerifyWebhook принимает уведомления с любого адреса: встроенный провайдер недоступен в production,
+// а статус платежа всё равно берётся из GetPayment
+func (f *Fake) VerifyWebhook(_ netip.Addr, body []byte) (*WebhookEvent, error) {
+	return parseNotification(body)
+}
+
+// SetStatus меняет статус платежа, как если бы покупатель оплатил его или отказался от оплаты
+func (f *Fake) SetStatus(id string, status models.PaymentStatus) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := f.payments[id]
+	if p == nil {
+		return ErrPaymentNotFound
+	}
+	p.Status = status
+	return nil
+}
+
+// Notification возвращает тело уведомления event о платеже id в формате YooKassa
+func (f *Fake) Notification(event, id string) []byte {
+	n := map[string]any{
+		"type":   "notification",
+		"event":  event,
+		"object": map[string]string{"id": id},
+	}
+	body, _ := json.Marshal(n)
+	return body
+}
+
+// copyPayment возвращает копию платежа, чтобы вызывающий код не менял состояние провайдера
+func (f *Fake) copyPayment(id string) *Payment {
+	p := *f.payments[id]
+	return &p
+}
@@ -0,0 +1,157 @@
+// Package payments — платёжные провайдеры: YooKassa и встроенный провайдер для разработки.
+package payments
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/pkg/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// PaymentProvider — платёжный провайдер. Суммы — в рублях, как и цены в заказах.
+type PaymentProvider interface {
+	// Name — имя провайдера, под которым сохраняются его платежи и принимаются уведомления
+	Name() string
+	// CreatePayment создаёт платёж; повторный запрос с тем же IdempotenceKey возвращает уже созданный платёж
+	CreatePayment(ctx context.Context, req CreatePaymentRequest) (*Payment, error)
+	// GetPayment возвращает текущее состояние платежа; ErrPaymentNotFound — провайдер платёж не знает
+	GetPayment(ctx context.Context, id string) (*Payment, error)
+	// Refund возвращает деньги по оплаченному платежу; повторный запрос с тем же IdempotenceKey не возвращает их дважды
+	Refund(ctx context.Context, req RefundRequest) (*Refund, error)
+	// VerifyWebhook проверяет, что уведомление о платеже пришло с адреса провайдера, и разбирает его;
+	// ErrUntrustedSource — чужой адрес, ErrInvalidWebhook — тело не разобрать
+	VerifyWebhook(source netip.Addr, body []byte) (*WebhookEvent, error)
+}
+
+var (
+	ErrPaymentNotFound = errors.New("payment not found at provider")
+	ErrUntrustedSource = errors.New("webhook from untrusted address")
+	ErrInvalidWebhook  = errors.New("invalid webhook notification")
+)
+
+type CreatePaymentRequest struct {
+	IdempotenceKey string
+	Amount         int
+	Currency       string
+	Description    string
+	// ReturnURL — куда провайдер вернёт покупателя после оплаты
+	ReturnURL string
+	Metadata  map[string]string
+}
+
+// Payment — платёж на стороне провайдера
+type Payment struct {
+	ID       string
+	Status   models.PaymentStatus
+	Amount   int
+	Currency string
+	// ConfirmationURL — страница оплаты для покупателя, пока платёж не оплачен
+	ConfirmationURL string
+	RefundedAmount  int
+	Metadata        map[string]string
+}
+
+type RefundRequest struct {
+	IdempotenceKey string
+	PaymentID      string
+	Amount         int
+	Currency       string
+	Description    string
+}
+
+// Refund — возврат по платежу на стороне провайдера
+type Refund struct {
+	ID        string
+	PaymentID string
+	Status    string
+	Amount    int
+}
+
+// WebhookEvent — уведомление провайдера. Статус платежа из уведомления не используется:
+// он перепроверяется запросом GetPayment.
+type WebhookEvent struct {
+	// Event — тип события, например payment.succeeded или refund.succeeded
+	Event     string
+	PaymentID string
+}
+
+// New создаёт провайдера, выбранного в настройках. Встроенный провайдер проводит платежи без денег,
+// поэтому в production (production = true) он недоступен, а с ним и приём его уведомлений.
+func New(cfg config.PaymentConfig, production bool) (PaymentProvider, error) {
+	switch cfg.Provider {
+	case "yookassa":
+		if cfg.YooKassa.ShopID == "" || cfg.YooKassa.SecretKey == "" {
+			return nil, errors.New("yookassa: shop id and secret key are required")
+		}
+		return NewYooKassa(cfg.YooKassa, nil)
+	case "fake":
+		if production {
+			return nil, errors.New("fake payment provider is not available in production")
+		}
+		return NewFake(cfg.FakeAutoSucceed), nil
+	}
+	return nil, fmt.Errorf("unknown payment provider %q", cfg.Provider)
+}
+
+// notification — уведомление в формате YooKassa; в object — платёж или, для событий refund.*, возврат
+type notification struct {
+	Type   string `json:"type"`
+	Event  string `json:"event"`
+	Object struct {
+		ID        string `json:"id"`
+		PaymentID string `json:"payment_id"`
+	} `json:"object"`
+}
+
+// parseNotification разбирает уведомление
+func parseNotification(body []byte) (*WebhookEvent, error) {
+	var n notification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	event := &WebhookEvent{Event: n.Event, PaymentID: n.Object.ID}
+	if strings.HasPrefix(n.Event, "refund.") {
+		event.PaymentID = n.Object.PaymentID
+	}
+	if n.Event == "" || event.PaymentID == "" {
+		return nil, fmt.Errorf("%w: event and payment id are required", ErrInvalidWebhook)
+	}
+	return event, nil
+}
+
+// formatAmount записывает сумму в рублях в формате API: "1990.00"
+func formatAmount(amount int) string {
+	return strconv.Itoa(amount) + ".00"
+}
+
+// parseAmount читает сумму в формате API; копейки в заказах не используются, поэтому дробная часть должна быть нулевой
+func parseAmount(value string) (int, error) {
+	whole, frac, _ := strings.Cut(value, ".")
+	amount, err := strconv.Atoi(whole)
+	if err != nil || strings.Trim(frac, "0") != "" {
+		return 0, fmt.Errorf("unsupported amount %q", value)
+	}
+	return amount, nil
+}
+
+// parsePrefixes разбирает адреса и подсети; одиночный адрес становится подсетью из одного адреса
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		if addr, err := netip.ParseAddr(v); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address or subnet %q", v)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,16 @@
+package payments
+
+import (
+	"dozenChairs/pkg/config"
+	"testing"
+)
+
+func TestNewRefusesFakeInProduction(t *testing.T) {
+	cfg := config.PaymentConfig{Provider: "fake"}
+	if _, err := New(cfg, true); err == nil {
+		t.Error("New(fake, production) succeeded, want error")
+	}
+	if _, err := New(cfg, false); err != nil {
+		t.Errorf("New(fake, development): %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package payments
+
+import (
+	"bytes"
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/pkg/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// YooKassa — провайдер YooKassa (API v3). Адрес API задаётся в настройках,
+// поэтому клиент можно проверить на локальном сервере, отвечающем в формате YooKassa.
+type YooKassa struct {
+	baseURL    string
+	shopID     string
+	secretKey  string
+	webhookIPs []netip.Prefix
+	client     *http.Client
+}
+
+// NewYooKassa создаёт клиента YooKassa; client nil — клиент с таймаутом 15 секунд
+func NewYooKassa(cfg config.YooKassaConfig, client *http.Client) (*YooKassa, error) {
+	webhookIPs, err := parsePrefixes(cfg.WebhookIPs)
+	if err != nil {
+		return nil, fmt.Errorf("yookassa: webhook ips: %w", err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &YooKassa{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		shopID:     cfg.ShopID,
+		secretKey:  cfg.SecretKey,
+		webhookIPs: webhookIPs,
+		client:     client,
+	}, nil
+}
+
+// APIError — ошибка, которую вернул API YooKassa
+type APIError struct {
+	StatusCode  int
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("yookassa: %d %s: %s", e.StatusCode, e.Code, e.Description)
+}
+
+type ykAmount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+type ykPayment struct {
+	ID           string    `json:"id"`
+	Status       string    `json:"status"`
+	Amount       ykAmount  `json:"amount"`
+	Refunded     *ykAmount `json:"refunded_amount,omitempty"`
+	Confirmation *struct {
+		ConfirmationURL string `json:"confirmation_url"`
+	} `json:"confirmation,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type ykRefund struct {
+	ID        string   `json:"id"`
+	PaymentID string   `json:"payment_id"`
+	Status    string   `json:"status"`
+	Amount    ykAmount `json:"amount"`
+}
+
+func (y *YooKassa) Name() string { return "yookassa" }
+
+func (y *YooKassa) CreatePayment(ctx context.Context, req CreatePaymentRequest) (*Payment, error) {
+	body := map[string]any{
+		"amount":  ykAmount{Value: formatAmount(req.Amount), Currency: req.Currency},
+		"capture": true,
+		"confirmation": map[string]string{
+			"type":       "redirect",
+			"return_url": req.ReturnURL,
+		},
+		"description": req.Description,
+		"metadata":    req.Metadata,
+	}
+	var p ykPayment
+	if err := y.do(ctx, http.MethodPost, "/payments", req.IdempotenceKey, body, &p); err != nil {
+		return nil, err
+	}
+	return p.toPayment()
+}
+
+func (y *YooKassa) GetPayment(ctx context.Context, id string) (*Payment, error) {
+	var p ykPayment
+	if err := y.do(ctx, http.MethodGet, "/payments/"+url.PathEscape(id), "", nil, &p); err != nil {
+		return nil, err
+	}
+	return p.toPayment()
+}
+
+func (y *YooKassa) Refund(ctx context.Context, req RefundRequest) (*Refund, error) {
+	body := map[string]any{
+		"payment_id":  req.PaymentID,
+		"amount":      ykAmount{Value: formatAmount(req.Amount), Currency: req.Currency},
+		"description": req.Description,
+	}
+	var r ykRefund
+	if err := y.do(ctx, http.MethodPost, "/refunds", req.IdempotenceKey, body, &r); err != nil {
+		return nil, err
+	}
+	amount, err := parseAmount(r.Amount.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &Refund{ID: r.ID, PaymentID: r.PaymentID, Status: r.Status, Amount: amount}, nil
+}
+
+// VerifyWebhook разбирает уведомление YooKassa. YooKassa уведомления не подписывает,
+// поэтому принимаются только уведомления с её адресов (webhookIPs), а статус платежа
+// в любом случае перепроверяется запросом GetPayment.
+func (y *YooKassa) VerifyWebhook(source netip.Addr, body []byte) (*WebhookEvent, error) {
+	source = source.Unmap()
+	for _, prefix := range y.webhookIPs {
+		if prefix.Contains(source) {
+			return parseNotification(body)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUntrustedSource, source)
+}
+
+// do выполняет запрос к API; idempotenceKey передаётся в заголовке Idempotence-Key
+func (y *YooKassa) do(ctx context.Context, method, path, idempotenceKey string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, y.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(y.shopID, y.secretKey)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotenceKey != "" {
+		req.Header.Set("Idempotence-Key", idempotenceKey)
+	}
+
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("yookassa: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(apiErr)
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %v", ErrPaymentNotFound, apiErr)
+		}
+		return apiErr
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("yookassa: decode response: %w", err)
+	}
+	return nil
+}
+
+func (p *ykPayment) toPayment() (*Payment, error) {
+	if p.ID == "" {
+		return nil, errors.New("yookassa: payment id is missing in response")
+	}
+	amount, err := parseAmount(p.Amount.Value)
+	if err != nil {
+		return nil, err
+	}
+	payment := &Payment{
+		ID:       p.ID,
+		Status:   models.PaymentStatus(p.Status),
+		Amount:   amount,
+		Currency: p.Amount.Currency,
+		Metadata: p.Metadata,
+	}
+	if p.Refunded != nil {
+		if payment.RefundedAmount, err = parseAmount(p.Refunded.Value); err != nil {
+			return nil, err
+		}
+	}
+	if p.Confirmation != nil {
+		payment.ConfirmationURL = p.Confirmation.ConfirmationURL
+	}
+	return payment, nil
+}
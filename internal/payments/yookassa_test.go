@@ -0,0 +1,277 @@
+package payments
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/pkg/config"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+// newTestYooKassa поднимает локальный сервер с обработчиком handler и клиента YooKassa, смотрящего на него
+func newTestYooKassa(t *testing.T, handler http.HandlerFunc) *YooKassa {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	yk, err := NewYooKassa(config.YooKassaConfig{
+		BaseURL:   srv.URL + "/v3/",
+		ShopID:    "shop",
+		SecretKey: "secret",
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("NewYooKassa: %v", err)
+	}
+	return yk
+}
+
+// checkAuth проверяет, что запрос подписан учётными данными магазина
+func checkAuth(t *testing.T, r *http.Request) {
+	t.Helper()
+	if user, pass, ok := r.BasicAuth(); !ok || user != "shop" || pass != "secret" {
+		t.Errorf("basic auth = %q/%q, want shop/secret", user, pass)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestYooKassaCreatePayment(t *testing.T) {
+	yk := newTestYooKassa(t, func(w http.ResponseWriter, r *http.Request) {
+		checkAuth(t, r)
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/payments" {
+			t.Errorf("request = %s %s, want POST /v3/payments", r.Method, r.URL.Path)
+		}
+		if key := r.Header.Get("Idempotence-Key"); key != "key-1" {
+			t.Errorf("Idempotence-Key = %q, want key-1", key)
+		}
+
+		var body struct {
+			Amount       ykAmount          `json:"amount"`
+			Capture      bool              `json:"capture"`
+			Confirmation map[string]string `json:"confirmation"`
+			Metadata     map[string]string `json:"metadata"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body.Amount != (ykAmount{Value: "1990.00", Currency: "RUB"}) || !body.Capture {
+			t.Errorf("amount = %+v, capture = %v", body.Amount, body.Capture)
+		}
+		if body.Confirmation["return_url"] != "https://shop.test/orders" || body.Metadata["order_id"] != "o-1" {
+			t.Errorf("confirmation = %v, metadata = %v", body.Confirmation, body.Metadata)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"id":           "yk-1",
+			"status":       "pending",
+			"amount":       body.Amount,
+			"confirmation": map[string]string{"type": "redirect", "confirmation_url": "https://yoomoney.test/pay/yk-1"},
+			"metadata":     body.Metadata,
+		})
+	})
+
+	p, err := yk.CreatePayment(context.Background(), CreatePaymentRequest{
+		IdempotenceKey: "key-1",
+		Amount:         1990,
+		Currency:       "RUB",
+		Description:    "Заказ №1000",
+		ReturnURL:      "https://shop.test/orders",
+		Metadata:       map[string]string{"order_id": "o-1"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+	if p.ID != "yk-1" || p.Status != models.PaymentPending || p.Amount != 1990 || p.Currency != "RUB" {
+		t.Errorf("payment = %+v", p)
+	}
+	if p.ConfirmationURL != "https://yoomoney.test/pay/yk-1" {
+		t.Errorf("ConfirmationURL = %q", p.ConfirmationURL)
+	}
+}
+
+func TestYooKassaGetPayment(t *testing.T) {
+	yk := newTestYooKassa(t, func(w http.ResponseWriter, r *http.Request) {
+		checkAuth(t, r)
+		switch r.URL.Path {
+		case "/v3/payments/yk-1":
+			writeJSON(w, http.StatusOK, map[string]any{
+				"id":              "yk-1",
+				"status":          "succeeded",
+				"amount":          ykAmount{Value: "1990.00", Currency: "RUB"},
+				"refunded_amount": ykAmount{Value: "500.00", Currency: "RUB"},
+			})
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]string{"type": "error", "code": "not_found", "description": "payment not found"})
+		}
+	})
+
+	p, err := yk.GetPayment(context.Background(), "yk-1")
+	if err != nil {
+		t.Fatalf("GetPayment: %v", err)
+	}
+	if p.Status != models.PaymentSucceeded || p.Amount != 1990 || p.RefundedAmount != 500 {
+		t.Errorf("payment = %+v", p)
+	}
+
+	_, err = yk.GetPayment(context.Background(), "missing")
+	if !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("GetPayment(missing) error = %v, want ErrPaymentNotFound", err)
+	}
+}
+
+func TestYooKassaRefund(t *testing.T) {
+	yk := newTestYooKassa(t, func(w http.ResponseWriter, r *http.Request) {
+		checkAuth(t, r)
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/refunds" {
+			t.Errorf("request = %s %s, want POST /v3/refunds", r.Method, r.URL.Path)
+		}
+		if key := r.Header.Get("Idempotence-Key"); key != "refund-1" {
+			t.Errorf("Idempotence-Key = %q, want refund-1", key)
+		}
+
+		var body struct {
+			PaymentID string   `json:"payment_id"`
+			Amount    ykAmount `json:"amount"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body.PaymentID != "yk-1" || body.Amount.Value != "1990.00" {
+			t.Errorf("body = %+v", body)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"id":         "rf-1",
+			"payment_id": body.PaymentID,
+			"status":     "succeeded",
+			"amount":     body.Amount,
+		})
+	})
+
+	rf, err := yk.Refund(context.Background(), RefundRequest{
+		IdempotenceKey: "refund-1",
+		PaymentID:      "yk-1",
+		Amount:         1990,
+		Currency:       "RUB",
+	})
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if rf.ID != "rf-1" || rf.PaymentID != "yk-1" || rf.Status != "succeeded" || rf.Amount != 1990 {
+		t.Errorf("refund = %+v", rf)
+	}
+}
+
+func TestYooKassaAPIError(t *testing.T) {
+	yk := newTestYooKassa(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"type": "error", "code": "invalid_request", "description": "bad amount"})
+	})
+
+	_, err := yk.CreatePayment(context.Background(), CreatePaymentRequest{Amount: 1, Currency: "RUB"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != "invalid_request" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestYooKassaVerifyWebhook(t *testing.T) {
+	yk, err := NewYooKassa(config.YooKassaConfig{WebhookIPs: []string{"185.71.76.0/27", "77.75.156.11", "2a02:5180::/32"}}, nil)
+	if err != nil {
+		t.Fatalf("NewYooKassa: %v", err)
+	}
+
+	const paymentBody = `{"type":"notification","event":"payment.succeeded","object":{"id":"yk-1","status":"succeeded"}}`
+	tests := []struct {
+		name    string
+		source  string
+		body    string
+		want    *WebhookEvent
+		wantErr error
+	}{
+		{
+			name:   "payment event from subnet",
+			source: "185.71.76.17",
+			body:   paymentBody,
+			want:   &WebhookEvent{Event: "payment.succeeded", PaymentID: "yk-1"},
+		},
+		{
+			name:   "refund event refers to its payment",
+			source: "77.75.156.11",
+			body:   `{"type":"notification","event":"refund.succeeded","object":{"id":"rf-1","payment_id":"yk-1"}}`,
+			want:   &WebhookEvent{Event: "refund.succeeded", PaymentID: "yk-1"},
+		},
+		{
+			name:   "ipv6 source",
+			source: "2a02:5180::1",
+			body:   paymentBody,
+			want:   &WebhookEvent{Event: "payment.succeeded", PaymentID: "yk-1"},
+		},
+		{
+			name:   "ipv4-mapped ipv6 source",
+			source: "::ffff:185.71.76.1",
+			body:   paymentBody,
+			want:   &WebhookEvent{Event: "payment.succeeded", PaymentID: "yk-1"},
+		},
+		{
+			name:    "address outside the list",
+			source:  "185.71.76.32",
+			body:    paymentBody,
+			wantErr: ErrUntrustedSource,
+		},
+		{
+			name:    "unknown source",
+			body:    paymentBody,
+			wantErr: ErrUntrustedSource,
+		},
+		{
+			name:    "not json",
+			source:  "185.71.76.1",
+			body:    `payment.succeeded`,
+			wantErr: ErrInvalidWebhook,
+		},
+		{
+			name:    "no payment id",
+			source:  "185.71.76.1",
+			body:    `{"type":"notification","event":"payment.succeeded","object":{}}`,
+			wantErr: ErrInvalidWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var source netip.Addr
+			if tt.source != "" {
+				source = netip.MustParseAddr(tt.source)
+			}
+			got, err := yk.VerifyWebhook(source, []byte(tt.body))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyWebhook: %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("event = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewYooKassaRejectsInvalidWebhookIPs(t *testing.T) {
+	if _, err := NewYooKassa(config.YooKassaConfig{WebhookIPs: []string{"185.71.76.0/33"}}, nil); err == nil {
+		t.Error("NewYooKassa with invalid subnet succeeded, want error")
+	}
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CartRepository хранит корзины покупателей. Позиции возвращаются без данных товара:
+// цены и наличие подставляет сервис.
+type CartRepository interface {
+	// GetGuest возвращает гостевую корзину; корзины пользователей по ID не выдаются
+	GetGuest(ctx context.Context, id string) (*models.Cart, error)
+	GetByUser(ctx context.Context, userID string) (*models.Cart, error)
+	CreateGuest(ctx context.Context) (string, error)
+	// EnsureUserCart возвращает ID корзины пользователя, создавая её при необходимости
+	EnsureUserCart(ctx context.Context, userID string) (string, error)
+	// AddItem добавляет позицию или увеличивает количество уже лежащей в корзине (не больше MaxCartItemQuantity)
+	AddItem(ctx context.Context, cartID, productID string, variantID *string, quantity int) error
+	SetQuantity(ctx context.Context, cartID, itemID string, quantity int) error
+	RemoveItem(ctx context.Context, cartID, itemID string) error
+	Clear(ctx context.Context, cartID string) error
+	// Merge переносит позиции гостевой корзины в корзину пользователя (количества складываются)
+	// и удаляет гостевую корзину
+	Merge(ctx context.Context, guestCartID, userID string) error
+	// DeleteStaleGuests удаляет гостевые корзины, не менявшиеся с before
+	DeleteStaleGuests(ctx context.Context, before time.Time) (int64, error)
+}
+
+type cartRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCartRepo(db *pgxpool.Pool) CartRepository {
+	return &cartRepo{db: db}
+}
+
+// cartItemKey — выражение уникального индекса позиций: позиция без варианта сравнивается с нулевым UUID
+const cartItemKey = `(cart_id, product_id, (coalesce(variant_id, '00000000-0000-0000-0000-000000000000'::uuid)))`
+
+func (r *cartRepo) GetGuest(ctx context.Context, id string) (*models.Cart, error) {
+	return r.get(ctx, `SELECT id, updated_at FROM carts WHERE id = $1 AND user_id IS NULL`, id)
+}
+
+func (r *cartRepo) GetByUser(ctx context.Context, userID string) (*models.Cart, error) {
+	return r.get(ctx, `SELECT id, updated_at FROM carts WHERE user_id = $1`, userID)
+}
+
+func (r *cartRepo) get(ctx context.Context, sql string, arg string) (*models.Cart, error) {
+	var c models.Cart
+	var updatedAt time.Time
+	if err := r.db.QueryRow(ctx, sql, arg).Scan(&c.ID, &updatedAt); err != nil {
+		return nil, err
+	}
+	c.UpdatedAt = &updatedAt
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, product_id, variant_id::text, quantity, created_at
+		FROM cart_items
+		WHERE cart_id = $1
+		ORDER BY created_at, id`, c.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	c.Items = []models.CartItem{}
+	for rows.Next() {
+		var item models.CartItem
+		if err := rows.Scan(&item.ID, &item.ProductID, &item.VariantID, &item.Quantity, &item.AddedAt); err != nil {
+			return nil, err
+		}
+		c.Items = append(c.Items, item)
+	}
+	return &c, rows.Err()
+}
+
+func (r *cartRepo) CreateGuest(ctx context.Context) (string, error) {
+	id := uuid.NewString()
+	_, err := r.db.Exec(ctx, `INSERT INTO carts (id) VALUES ($1)`, id)
+	return id, err
+}
+
+func (r *cartRepo) EnsureUserCart(ctx context.Context, userID string) (string, error) {
+	return ensureUserCart(ctx, r.db, userID)
+}
+
+func ensureUserCart(ctx context.Context, db dbtx, userID string) (string, error) {
+	var id string
+	err := db.QueryRow(ctx, `
+		INSERT INTO carts (id, user_id) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET updated_at = carts.updated_at
+		RETURNING id`,
+		uuid.NewString(), userID,
+	).Scan(&id)
+	return id, err
+}
+
+func (r *cartRepo) AddItem(ctx context.Context, cartID, productID string, variantID *string, quantity int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO cart_items (id, cart_id, product_id, variant_id, quantity)
+		VALUES ($1, $2, $3, $4, LEAST($5::int, $6::int))
+		ON CONFLICT `+cartItemKey+` DO UPDATE
+		SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $6::int), updated_at = now()`,
+		uuid.NewString(), cartID, productID, variantID, quantity, models.MaxCartItemQuantity,
+	)
+	if err != nil {
+		return mapForeignKeyViolation(err)
+	}
+	if err := touchCart(ctx, tx, cartID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *cartRepo) SetQuantity(ctx context.Context, cartID, itemID string, quantity int) error {
+	return r.changeItems(ctx, cartID,
+		`UPDATE cart_items SET quantity = $3, updated_at = now() WHERE cart_id = $1 AND id = $2`,
+		cartID, itemID, quantity,
+	)
+}
+
+func (r *cartRepo) RemoveItem(ctx context.Context, cartID, itemID string) error {
+	return r.changeItems(ctx, cartID, `DELETE FROM cart_items WHERE cart_id = $1 AND id = $2`, cartID, itemID)
+}
+
+func (r *cartRepo) Clear(ctx context.Context, cartID string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM cart_items WHERE cart_id = $1`, cartID)
+	if err != nil {
+		return err
+	}
+	return touchCart(ctx, r.db, cartID)
+}
+
+// changeItems выполняет изменение одной позиции и обновляет время изменения корзины;
+// если позиция не найдена, возвращает pgx.ErrNoRows
+func (r *cartRepo) changeItems(ctx context.Context, cartID, sql string, args ...any) error {
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return touchCart(ctx, r.db, cartID)
+}
+
+func (r *cartRepo) Merge(ctx context.Context, guestCartID, userID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// блокируем гостевую корзину, чтобы параллельный вход с той же cookie не перенёс позиции дважды
+	var locked string
+	err = tx.QueryRow(ctx, `SELECT id FROM carts WHERE id = $1 AND user_id IS NULL FOR UPDATE`, guestCartID).Scan(&locked)
+	if err != nil {
+		return err
+	}
+
+	userCartID, err := ensureUserCart(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO cart_items (id, cart_id, product_id, variant_id, quantity, created_at)
+		SELECT gen_random_uuid(), $2, product_id, variant_id, quantity, created_at
+		FROM cart_items WHERE cart_id = $1
+		ON CONFLICT `+cartItemKey+` DO UPDATE
+		SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, $3::int), updated_at = now()`,
+		guestCartID, userCartID, models.MaxCartItemQuantity,
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM carts WHERE id = $1`, guestCartID); err != nil {
+		return err
+	}
+	if err := touchCart(ctx, tx, userCartID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *cartRepo) DeleteStaleGuests(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM carts WHERE user_id IS NULL AND updated_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func touchCart(ctx context.Context, db dbtx, cartID string) error {
+	_, err := db.Exec(ctx, `UPDATE carts SET updated_at = now() WHERE id = $1`, cartID)
+	return err
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, c *models.Category) error
+	GetByID(ctx context.Context, id string) (*models.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Category, error)
+	FindBySlugOrName(ctx context.Context, v string) (*models.Category, error)
+	GetAll(ctx context.Context) ([]*models.Category, error)
+	Update(ctx context.Context, c *models.Category) error
+	Delete(ctx context.Context, id string) error
+	CountProducts(ctx context.Context, id string) (int, error)
+}
+
+type categoryRepo struct {
+	db *pgxpool.Pool
+}
+
+const categoryColumns = `id, parent_id, name, slug, coalesce(description, ''), coalesce(image_url, ''),
+	sort_order, created_at, updated_at`
+
+func NewCategoryRepo(db *pgxpool.Pool) CategoryRepository {
+	return &categoryRepo{db: db}
+}
+
+func (r *categoryRepo) Create(ctx context.Context, c *models.Category) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO categories (id, parent_id, name, slug, description, image_url, sort_order, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		c.ID, c.ParentID, c.Name, c.Slug, c.Description, c.ImageURL, c.SortOrder, c.CreatedAt, c.UpdatedAt,
+	)
+	return mapUniqueViolation(err)
+}
+
+func (r *categoryRepo) GetByID(ctx context.Context, id string) (*models.Category, error) {
+	return scanCategory(r.db.QueryRow(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = $1`, id))
+}
+
+func (r *categoryRepo) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	return scanCategory(r.db.QueryRow(ctx, `SELECT `+categoryColumns+` FROM categories WHERE slug = $1`, slug))
+}
+
+// FindBySlugOrName ищет категорию по slug или названию без учёта регистра
+func (r *categoryRepo) FindBySlugOrName(ctx context.Context, v string) (*models.Category, error) {
+	return scanCategory(r.db.QueryRow(ctx, `
+		SELECT `+categoryColumns+` FROM categories
+		WHERE slug = $1 OR lower(name) = lower($1)
+		ORDER BY slug = $1 DESC
+		LIMIT 1`, v))
+}
+
+func (r *categoryRepo) GetAll(ctx context.Context) ([]*models.Category, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+categoryColumns+` FROM categories ORDER BY sort_order, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*models.Category
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
+
+// Update сохраняет категорию и синхронизирует денормализованное название у её товаров
+func (r *categoryRepo) Update(ctx context.Context, c *models.Category) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE categories SET
+			parent_id = $1,
+			name = $2,
+			slug = $3,
+			description = $4,
+			image_url = $5,
+			sort_order = $6,
+			updated_at = $7
+		WHERE id = $8`,
+		c.ParentID, c.Name, c.Slug, c.Description, c.ImageURL, c.SortOrder, c.UpdatedAt, c.ID,
+	)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE products SET category = $1 WHERE category_id = $2`, c.Name, c.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *categoryRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	return err
+}
+
+func (r *categoryRepo) CountProducts(ctx context.Context, id string) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM products WHERE category_id = $1`, id).Scan(&n)
+	return n, err
+}
+
+func scanCategory(row pgx.Row) (*models.Category, error) {
+	var c models.Category
+	if err := row.Scan(
+		&c.ID, &c.ParentID, &c.Name, &c.Slug, &c.Description, &c.ImageURL,
+		&c.SortOrder, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrAlreadyExists — нарушение уникальности (например, занятый slug)
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrReferenced — запись нельзя удалить, пока на неё ссылаются (например, товар входит в набор)
+	ErrReferenced = errors.New("record is referenced")
+	// ErrVersionConflict — запись изменилась с момента чтения (версия не совпала)
+	ErrVersionConflict = errors.New("version conflict")
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// mapUniqueViolation заменяет ошибку уникального индекса Postgres на ErrAlreadyExists
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+// mapForeignKeyViolation заменяет ошибку внешнего ключа Postgres на ErrReferenced
+func mapForeignKeyViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+		return ErrReferenced
+	}
+	return err
+}
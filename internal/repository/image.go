@@ -9,12 +9,16 @@ import (
 
 type ImageRepository interface {
 	Save(ctx context.Context, img *models.Image) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) (*models.Image, error)
 	GetByProductID(ctx context.Context, productID string) ([]models.Image, error)
+	// WithTx выполняет fn в транзакции: изменения изображений и записанные через
+	// Revisions ревизии фиксируются вместе
+	WithTx(ctx context.Context, fn func(repo ImageRepository) error) error
+	Revisions() RevisionRepository
 }
 
 type imageRepo struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
 func NewImageRepo(db *pgxpool.Pool) ImageRepository {
@@ -23,11 +27,12 @@ func NewImageRepo(db *pgxpool.Pool) ImageRepository {
 
 func (r *imageRepo) Save(ctx context.Context, img *models.Image) error {
 	query := `
-		INSERT INTO images (id, product_id, url, filename, created_at)
-		VALUES ($1, $2, $3, $4, $5)`
+		INSERT INTO images (id, product_id, variant_id, url, filename, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
 	_, err := r.db.Exec(ctx, query,
 		img.ID,
 		img.ProductID,
+		img.VariantID,
 		img.URL,
 		img.Filename,
 		time.Now(),
@@ -35,16 +40,25 @@ func (r *imageRepo) Save(ctx context.Context, img *models.Image) error {
 	return err
 }
 
-func (r *imageRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.db.Exec(ctx, `DELETE FROM images WHERE id = $1`, id)
-	return err
+// Delete удаляет запись изображения и возвращает её (pgx.ErrNoRows, если записи нет)
+func (r *imageRepo) Delete(ctx context.Context, id string) (*models.Image, error) {
+	var img models.Image
+	err := r.db.QueryRow(ctx, `
+		DELETE FROM images WHERE id = $1
+		RETURNING id, product_id, variant_id, url, filename, created_at`, id,
+	).Scan(&img.ID, &img.ProductID, &img.VariantID, &img.URL, &img.Filename, &img.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &img, nil
 }
 
 func (r *imageRepo) GetByProductID(ctx context.Context, productID string) ([]models.Image, error) {
 	query := `
-		SELECT id, product_id, url, filename, created_at
+		SELECT id, product_id, variant_id, url, filename, created_at
 		FROM images
-		WHERE product_id = $1`
+		WHERE product_id = $1
+		ORDER BY created_at, id`
 
 	rows, err := r.db.Query(ctx, query, productID)
 	if err != nil {
@@ -55,10 +69,27 @@ func (r *imageRepo) GetByProductID(ctx context.Context, productID string) ([]mod
 	var images []models.Image
 	for rows.Next() {
 		var img models.Image
-		if err := rows.Scan(&img.ID, &img.ProductID, &img.URL, &img.Filename, &img.CreatedAt); err != nil {
+		if err := rows.Scan(&img.ID, &img.ProductID, &img.VariantID, &img.URL, &img.Filename, &img.CreatedAt); err != nil {
 			return nil, err
 		}
 		images = append(images, img)
 	}
 	return images, nil
 }
+
+func (r *imageRepo) WithTx(ctx context.Context, fn func(repo ImageRepository) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&imageRepo{db: tx}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *imageRepo) Revisions() RevisionRepository {
+	return &revisionRepo{db: r.db}
+}
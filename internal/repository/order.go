@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrCartChanged — корзина изменилась во время оформления (или заказ по ней уже оформлен)
+var ErrCartChanged = errors.New("cart has changed")
+
+// OrderFilter — условия выборки заказов; пустые поля не ограничивают выборку
+type OrderFilter struct {
+	Status models.OrderStatus
+	UserID string
+	// From и To ограничивают дату оформления: [From, To)
+	From *time.Time
+	To   *time.Time
+	// Query — номер заказа либо часть имени, email или телефона покупателя
+	Query  string
+	Limit  int
+	Offset int
+}
+
+type OrderRepository interface {
+	// Create в одной транзакции применяет промокод (redemption может быть nil), сохраняет заказ
+	// с позициями и первой записью истории, резервирует товары до o.ReservedUntil
+	// и убирает оформленные позиции из корзины cartID.
+	// Если позиции корзины изменились с момента чтения, возвращает ErrCartChanged;
+	// если товара не хватает на складе — ErrInsufficientStock.
+	Create(ctx context.Context, o *models.Order, cartID string, cartItems []models.CartItem, redemption *models.PromoCodeRedemption) error
+	GetByID(ctx context.Context, id string) (*models.Order, error)
+	// List возвращает страницу заказов (новые первыми) с позициями и общее количество по фильтру
+	List(ctx context.Context, filter OrderFilter) ([]*models.Order, int, error)
+	// Transition переводит заказ из статуса change.From в change.To, пишет запись истории
+	// и в той же транзакции проводит по складу движения effect. При отмене заказа
+	// применённый к нему промокод освобождается: использование снова доступно.
+	// Если статус заказа уже другой, возвращает ErrVersionConflict.
+	Transition(ctx context.Context, orderID string, change *models.OrderStatusChange, effect StockEffect) error
+}
+
+type orderRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOrderRepo(db *pgxpool.Pool) OrderRepository {
+	return &orderRepo{db: db}
+}
+
+const orderColumns = `id, number, user_id, status, customer_name, email, phone, delivery_method, city, address,
+	pickup_point_id::text, comment,
+	subtotal, discount, promo_code, promo_discount, total, created_at, updated_at,
+	(SELECT min(expires_at) FROM stock_reservations r WHERE r.order_id = orders.id AND r.status = 'active')`
+
+func (r *orderRepo) Create(ctx context.Context, o *models.Order, cartID string, cartItems []models.CartItem, redemption *models.PromoCodeRedemption) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// позиции удаляются только при совпадении количества: параллельное оформление той же корзины
+	// или её изменение после расчёта заказа не пройдёт проверку
+	ids := make([]string, 0, len(cartItems))
+	quantities := make([]int, 0, len(cartItems))
+	for _, item := range cartItems {
+		ids = append(ids, item.ID)
+		quantities = append(quantities, item.Quantity)
+	}
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM cart_items c
+		USING unnest($2::uuid[], $3::int[]) AS x(id, quantity)
+		WHERE c.cart_id = $1 AND c.id = x.id AND c.quantity = x.quantity`,
+		cartID, ids, quantities,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() != int64(len(cartItems)) {
+		return ErrCartChanged
+	}
+	if err := touchCart(ctx, tx, cartID); err != nil {
+		return err
+	}
+
+	if redemption != nil {
+		if err := redeemPromoCode(ctx, tx, redemption); err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO orders (id, user_id, status, customer_name, email, phone, delivery_method, city, address,
+		                    pickup_point_id, comment, subtotal, discount, promo_code, promo_discount, total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING number`,
+		o.ID, o.UserID, o.Status, o.CustomerName, o.Email, o.Phone, o.DeliveryMethod, o.City, o.Address,
+		o.PickupPointID, o.Comment,
+		o.Subtotal, o.Discount, o.PromoCode, o.PromoDiscount, o.Total, o.CreatedAt, o.UpdatedAt,
+	).Scan(&o.Number)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range o.Items {
+		options, _ := json.Marshal(item.Options)
+		_, err := tx.Exec(ctx, `
+			INSERT INTO order_items (id, order_id, position, product_id, variant_id, type, slug, title, sku, options,
+			                         price, old_price, quantity, promo_discount, line_total)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+			item.ID, o.ID, i, item.ProductID, item.VariantID, item.Type, item.Slug, item.Title, item.SKU, options,
+			item.Price, item.OldPrice, item.Quantity, item.PromoDiscount, item.LineTotal,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range o.History {
+		if err := insertStatusChange(ctx, tx, o.ID, &o.History[i]); err != nil {
+			return err
+		}
+	}
+
+	if o.ReservedUntil != nil {
+		reserved, err := reserveOrderStock(ctx, tx, o, *o.ReservedUntil)
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			o.ReservedUntil = nil
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *orderRepo) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	o, err := scanOrder(r.db.QueryRow(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(ctx, []*models.Order{o}); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, from_status, to_status, changed_by, comment, changed_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY changed_at, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	o.History = []models.OrderStatusChange{}
+	for rows.Next() {
+		var c models.OrderStatusChange
+		if err := rows.Scan(&c.ID, &c.From, &c.To, &c.ChangedBy, &c.Comment, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		o.History = append(o.History, c)
+	}
+	return o, rows.Err()
+}
+
+func (r *orderRepo) List(ctx context.Context, filter OrderFilter) ([]*models.Order, int, error) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.UserID != "" {
+		add("user_id = $%d", filter.UserID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		if number, err := strconv.ParseInt(strings.TrimPrefix(q, "#"), 10, 64); err == nil {
+			add("number = $%d", number)
+		} else {
+			args = append(args, "%"+q+"%")
+			n := len(args)
+			conds = append(conds, fmt.Sprintf("(customer_name ILIKE $%d OR email ILIKE $%d OR phone ILIKE $%d)", n, n, n))
+		}
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM orders`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`SELECT %s FROM orders%s ORDER BY created_at DESC, number DESC LIMIT $%d OFFSET $%d`,
+		orderColumns, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	orders := []*models.Order{}
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+func (r *orderRepo) Transition(ctx context.Context, orderID string, change *models.OrderStatusChange, effect StockEffect) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// условие на текущий статус защищает от одновременных переходов из одного и того же статуса
+	tag, err := tx.Exec(ctx,
+		`UPDATE orders SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2`,
+		orderID, change.From, change.To, change.ChangedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, orderID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return pgx.ErrNoRows
+		}
+		return ErrVersionConflict
+	}
+
+	if err := insertStatusChange(ctx, tx, orderID, change); err != nil {
+		return err
+	}
+	reason := "order " + string(change.To)
+	if change.Comment != "" {
+		reason += ": " + change.Comment
+	}
+	if err := closeReservations(ctx, tx, orderID, effect, change.ChangedBy, reason); err != nil {
+		return err
+	}
+	if change.To == models.OrderCancelled {
+		if err := releasePromoCode(ctx, tx, orderID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// loadItems подставляет позиции в заказы одним запросом
+func (r *orderRepo) loadItems(ctx context.Context, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, o := range orders {
+		o.Items = []models.OrderItem{}
+		byID[o.ID] = o
+		ids = append(ids, o.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT order_id, id, product_id, variant_id::text, type, slug, title, sku, options,
+		       price, old_price, quantity, promo_discount, line_total
+		FROM order_items
+		WHERE order_id = ANY($1::uuid[])
+		ORDER BY order_id, position`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var item models.OrderItem
+		var options []byte
+		if err := rows.Scan(
+			&orderID, &item.ID, &item.ProductID, &item.VariantID, &item.Type, &item.Slug, &item.Title, &item.SKU, &options,
+			&item.Price, &item.OldPrice, &item.Quantity, &item.PromoDiscount, &item.LineTotal,
+		); err != nil {
+			return err
+		}
+		_ = json.Unmarshal(options, &item.Options)
+		o := byID[orderID]
+		o.Items = append(o.Items, item)
+	}
+	return rows.Err()
+}
+
+func insertStatusChange(ctx context.Context, db dbtx, orderID string, c *models.OrderStatusChange) error {
+	if c.ID == "" {
+		c.ID = uuid.NewString()
+	}
+	_, err := db.Exec(ctx, `
+		INSERT INTO order_status_history (id, order_id, from_status, to_status, changed_by, comment, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		c.ID, orderID, c.From, c.To, c.ChangedBy, c.Comment, c.ChangedAt,
+	)
+	return err
+}
+
+func scanOrder(row pgx.Row) (*models.Order, error) {
+	var o models.Order
+	if err := row.Scan(
+		&o.ID, &o.Number, &o.UserID, &o.Status, &o.CustomerName, &o.Email, &o.Phone, &o.DeliveryMethod, &o.City,
+		&o.Address, &o.PickupPointID, &o.Comment, &o.Subtotal, &o.Discount, &o.PromoCode, &o.PromoDiscount, &o.Total,
+		&o.CreatedAt, &o.UpdatedAt, &o.ReservedUntil,
+	); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PaymentRepository interface {
+	// Create сохраняет платёж; платёж с тем же ID или тем же платежом провайдера — ErrAlreadyExists
+	Create(ctx context.Context, p *models.Payment) error
+	GetByID(ctx context.Context, id string) (*models.Payment, error)
+	// GetByExternalID ищет платёж по ID у провайдера
+	GetByExternalID(ctx context.Context, provider, externalID string) (*models.Payment, error)
+	// ListByOrder возвращает платежи заказа, последний первым
+	ListByOrder(ctx context.Context, orderID string) ([]*models.Payment, error)
+	// UpdateStatus записывает состояние платежа у провайдера. Сумма возвратов не уменьшается,
+	// а время оплаты записывается один раз, поэтому повторные уведомления ничего не портят.
+	UpdateStatus(ctx context.Context, id string, status models.PaymentStatus, refundedAmount int, paidAt *time.Time) error
+}
+
+type paymentRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentRepo(db *pgxpool.Pool) PaymentRepository {
+	return &paymentRepo{db: db}
+}
+
+const paymentColumns = `id, order_id, provider, external_id, status, amount, currency, confirmation_url,
+	refunded_amount, paid_at, created_at, updated_at`
+
+func (r *paymentRepo) Create(ctx context.Context, p *models.Payment) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO payments (`+paymentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		p.ID, p.OrderID, p.Provider, p.ExternalID, p.Status, p.Amount, p.Currency, p.ConfirmationURL,
+		p.RefundedAmount, p.PaidAt, p.CreatedAt, p.UpdatedAt,
+	)
+	return mapUniqueViolation(err)
+}
+
+func (r *paymentRepo) GetByID(ctx context.Context, id string) (*models.Payment, error) {
+	return scanPayment(r.db.QueryRow(ctx, `SELECT `+paymentColumns+` FROM payments WHERE id = $1`, id))
+}
+
+func (r *paymentRepo) GetByExternalID(ctx context.Context, provider, externalID string) (*models.Payment, error) {
+	return scanPayment(r.db.QueryRow(ctx, `
+		SELECT `+paymentColumns+` FROM payments
+		WHERE provider = $1 AND external_id = $2`, provider, externalID))
+}
+
+func (r *paymentRepo) ListByOrder(ctx context.Context, orderID string) ([]*models.Payment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+paymentColumns+` FROM payments
+		WHERE order_id = $1
+		ORDER BY created_at DESC, id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []*models.Payment{}
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func (r *paymentRepo) UpdateStatus(ctx context.Context, id string, status models.PaymentStatus, refundedAmount int, paidAt *time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE payments SET
+			status = $2,
+			refunded_amount = GREATEST(refunded_amount, $3),
+			paid_at = COALESCE(paid_at, $4),
+			updated_at = now()
+		WHERE id = $1`, id, status, refundedAmount, paidAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func scanPayment(row pgx.Row) (*models.Payment, error) {
+	var p models.Payment
+	err := row.Scan(
+		&p.ID, &p.OrderID, &p.Provider, &p.ExternalID, &p.Status, &p.Amount, &p.Currency, &p.ConfirmationURL,
+		&p.RefundedAmount, &p.PaidAt, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
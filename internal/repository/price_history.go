@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LowestPriceWindow — период, за который считается минимальная цена товара (Product.LowestPrice30Days)
+const LowestPriceWindow = 30 * 24 * time.Hour
+
+type PriceHistoryRepository interface {
+	// GetByProductID возвращает изменения цены товара начиная с since от новых к старым,
+	// а также цену, действовавшую на момент since
+	GetByProductID(ctx context.Context, productID string, since time.Time) ([]models.PricePoint, error)
+}
+
+type priceHistoryRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPriceHistoryRepo(db *pgxpool.Pool) PriceHistoryRepository {
+	return &priceHistoryRepo{db: db}
+}
+
+func (r *priceHistoryRepo) GetByProductID(ctx context.Context, productID string, since time.Time) ([]models.PricePoint, error) {
+	rows, err := r.db.Query(ctx, `
+		(SELECT price, old_price, changed_at, id
+		 FROM product_price_history
+		 WHERE product_id = $1 AND changed_at >= $2)
+		UNION ALL
+		(SELECT price, old_price, changed_at, id
+		 FROM product_price_history
+		 WHERE product_id = $1 AND changed_at < $2
+		 ORDER BY changed_at DESC, id DESC
+		 LIMIT 1)
+		ORDER BY changed_at DESC, id DESC`, productID, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.PricePoint, error) {
+		var p models.PricePoint
+		var id int64
+		err := row.Scan(&p.Price, &p.OldPrice, &p.ChangedAt, &id)
+		return p, err
+	})
+}
+
+// recordPriceChanges добавляет в историю текущую цену товара productID и наборов, в которые он входит,
+// если она отличается от последней записанной. Вызывается в транзакции, изменившей цены.
+// changed_at — TIMESTAMP в UTC, поэтому и моменты для сравнения с ним передаются в UTC.
+func recordPriceChanges(ctx context.Context, db execer, productID string) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO product_price_history (product_id, price, old_price, changed_at)
+		SELECT p.id, p.price, p.old_price, now() AT TIME ZONE 'UTC'
+		FROM products p
+		WHERE (p.id = $1 OR p.id IN (SELECT set_id FROM set_items WHERE product_id = $1))
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM (SELECT price, old_price
+			      FROM product_price_history h
+			      WHERE h.product_id = p.id
+			      ORDER BY changed_at DESC, id DESC
+			      LIMIT 1) latest
+			WHERE latest.price = p.price AND latest.old_price IS NOT DISTINCT FROM p.old_price
+		  )`, productID)
+	return err
+}
+
+// fetchLowestPrices возвращает минимальную цену товаров с момента since,
+// учитывая и цену, которая действовала на этот момент
+func fetchLowestPrices(ctx context.Context, db dbtx, productIDs []string, since time.Time) (map[string]int, error) {
+	rows, err := db.Query(ctx, `
+		SELECT product_id, min(price)
+		FROM (
+			SELECT product_id, price
+			FROM product_price_history
+			WHERE product_id = ANY($1) AND changed_at >= $2
+			UNION ALL
+			SELECT DISTINCT ON (product_id) product_id, price
+			FROM product_price_history
+			WHERE product_id = ANY($1) AND changed_at < $2
+			ORDER BY product_id, changed_at DESC, id DESC
+		) h
+		GROUP BY product_id`, productIDs, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lowest := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var price int
+		if err := rows.Scan(&id, &price); err != nil {
+			return nil, err
+		}
+		lowest[id] = price
+	}
+	return lowest, rows.Err()
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"dozenChairs/internal/models"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"strings"
 	"time"
@@ -13,24 +16,57 @@ import (
 type ProductRepository interface {
 	Create(p *models.Product) error
 	GetBySlug(slug string) (*models.Product, error)
+	GetByIDs(ids []string) ([]*models.Product, error)
+	ResolveSlug(oldSlug string) (string, error)
+	SlugTaken(slug, exceptID string) (bool, error)
 	GetAll(filter ProductFilter) ([]*models.Product, error)
-	GetCategories() ([]string, error)
+	Count(filter ProductFilter) (int, error)
+	Search(filter ProductFilter) ([]*models.ProductSearchResult, error)
+	GetFacets(filter ProductFilter) (*models.ProductFacets, error)
 	Update(slug string, p *models.Product) error
+	Patch(slug string, p *models.Product, fields []string) error
 	Delete(slug string) error
+	GetDeletedBySlug(slug string) (*models.Product, error)
+	Restore(slug string) error
+	Purge(ctx context.Context, before time.Time) (*PurgeResult, error)
+	ApplySchedule(ctx context.Context, now time.Time) ([]models.StatusTransition, error)
+	// LastModified возвращает время последнего изменения витрины: товаров, их изображений и категорий
+	LastModified(ctx context.Context) (time.Time, error)
+	// WithTx выполняет fn в транзакции: все операции репозитория, переданного в fn,
+	// фиксируются вместе или откатываются, если fn вернула ошибку.
+	// Вложенный вызов WithTx открывает точку сохранения
+	WithTx(ctx context.Context, fn func(repo ProductRepository) error) error
+	// Revisions возвращает журнал правок на том же соединении: внутри WithTx
+	// ревизия фиксируется или откатывается вместе с изменением товара
+	Revisions() RevisionRepository
+}
+
+// dbtx — общее подмножество методов pgxpool.Pool и pgx.Tx, которым пользуется репозиторий
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
 type productRepo struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
-type ProductFilter struct {
-	Type     string
-	Category string
-	InStock  *bool
-	Sort     string
-	Limit    int
-	Offset   int
-	FromDate time.Time
+const productColumns = `id, type, category_id, category, title, slug, description, price, old_price, in_stock, unit_count,
+	attributes, tags, set_discount_percent, status, publish_at, unpublish_at, created_at, updated_at, deleted_at, version`
+
+// searchConfig — конфигурация полнотекстового поиска Postgres (русская морфология)
+const searchConfig = "russian"
+
+// searchVectorExpr строит выражение tsvector по названию, тегам, описанию и значениям атрибутов.
+// Аргументы — SQL-выражения (колонки или плейсхолдеры), из которых берутся значения.
+func searchVectorExpr(title, tags, description, attributes string) string {
+	return fmt.Sprintf(`setweight(to_tsvector('%[1]s', coalesce(%[2]s, '')), 'A') ||
+		setweight(jsonb_to_tsvector('%[1]s', coalesce(%[3]s::jsonb, '[]'::jsonb), '["string"]'), 'B') ||
+		setweight(to_tsvector('%[1]s', coalesce(%[4]s, '')), 'C') ||
+		setweight(jsonb_to_tsvector('%[1]s', coalesce(%[5]s::jsonb, '{}'::jsonb), '["string", "numeric"]'), 'D')`,
+		searchConfig, title, tags, description, attributes)
 }
 
 func NewProductRepo(db *pgxpool.Pool) ProductRepository {
@@ -39,129 +75,164 @@ func NewProductRepo(db *pgxpool.Pool) ProductRepository {
 
 func (r *productRepo) Create(p *models.Product) error {
 	attrJson, _ := json.Marshal(p.Attributes)
-	includesJson, _ := json.Marshal(p.Includes)
 	tagsJson, _ := json.Marshal(p.Tags)
 
 	query := `
 	INSERT INTO products (
 		id, type, category, title, slug, description,
 		price, old_price, in_stock, unit_count,
-		attributes, includes, tags, created_at, updated_at
+		attributes, tags, created_at, updated_at,
+		category_id, set_discount_percent, status, publish_at, unpublish_at, search_vector
 	) VALUES (
 		$1, $2, $3, $4, $5, $6,
 		$7, $8, $9, $10,
-		$11, $12, $13, $14, $15
+		$11, $12, $13, $14,
+		$15, $16, $17, $18, $19, ` + searchVectorExpr("$4::text", "$12", "$6::text", "$11") + `
 	)`
 
-	_, err := r.db.Exec(
-		context.Background(),
-		query,
-		p.ID, p.Type, p.Category, p.Title, p.Slug, p.Description,
-		p.Price, p.OldPrice, p.InStock, p.UnitCount,
-		string(attrJson), string(includesJson), string(tagsJson),
-		p.CreatedAt, p.UpdatedAt,
-	)
-
-	return err
+	ctx := context.Background()
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(
+			ctx,
+			query,
+			p.ID, p.Type, p.Category, p.Title, p.Slug, p.Description,
+			p.Price, p.OldPrice, p.InStock, p.UnitCount,
+			string(attrJson), string(tagsJson),
+			p.CreatedAt, p.UpdatedAt,
+			p.CategoryID, p.SetDiscountPercent,
+			p.Status, p.PublishAt, p.UnpublishAt,
+		)
+		if err != nil {
+			return mapUniqueViolation(err)
+		}
+		return r.saveComposition(ctx, tx, p)
+	})
 }
 
 func (r *productRepo) GetBySlug(slug string) (*models.Product, error) {
-	query := `SELECT id, type, category, title, slug, description, price, old_price,
-	                 in_stock, unit_count, attributes, includes, tags, created_at, updated_at
-	          FROM products WHERE slug = $1`
+	query := `SELECT ` + productColumns + ` FROM products WHERE slug = $1 AND deleted_at IS NULL`
 
 	var p models.Product
-	var attributes, includes, tags []byte
-
-	err := r.db.QueryRow(context.Background(), query, slug).Scan(
-		&p.ID, &p.Type, &p.Category, &p.Title, &p.Slug, &p.Description,
-		&p.Price, &p.OldPrice, &p.InStock, &p.UnitCount,
-		&attributes, &includes, &tags,
-		&p.CreatedAt, &p.UpdatedAt,
-	)
-	if err != nil {
+	if err := scanProduct(r.db.QueryRow(context.Background(), query, slug), &p); err != nil {
 		return nil, err
 	}
 
-	// Распаковываем JSON-поля
-	_ = json.Unmarshal(attributes, &p.Attributes)
-	_ = json.Unmarshal(includes, &p.Includes)
-	_ = json.Unmarshal(tags, &p.Tags)
+	// Загружаем варианты и изображения
+	if err := r.loadRelations(&p); err != nil {
+		return nil, err
+	}
 
-	// Загружаем изображения
-	imageQuery := `SELECT id, product_id, url, filename FROM images WHERE product_id = $1`
+	return &p, nil
+}
 
-	rows, err := r.db.Query(context.Background(), imageQuery, p.ID)
+// GetByIDs загружает товары по списку ID (порядок не гарантируется)
+func (r *productRepo) GetByIDs(ids []string) ([]*models.Product, error) {
+	rows, err := r.db.Query(context.Background(), `SELECT `+productColumns+` FROM products WHERE id = ANY($1) AND deleted_at IS NULL`, ids)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var images []models.Image
+	var products []*models.Product
 	for rows.Next() {
-		var img models.Image
-		if err := rows.Scan(&img.ID, &img.ProductID, &img.URL, &img.Filename); err != nil {
+		var p models.Product
+		if err := scanProduct(rows, &p); err != nil {
 			return nil, err
 		}
-		images = append(images, img)
+		products = append(products, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	p.Images = images
 
-	return &p, nil
+	if err := r.loadRelations(products...); err != nil {
+		return nil, err
+	}
+	return products, nil
 }
 
 func (r *productRepo) GetAll(f ProductFilter) ([]*models.Product, error) {
-	query := `SELECT id, type, category, title, slug, description, price, old_price, in_stock, unit_count,
-	                 attributes, includes, tags, created_at, updated_at
-	          FROM products`
-	var args []interface{}
-	var where []string
+	query := `SELECT ` + productColumns + ` FROM products`
 
-	addFilter := func(cond string, val interface{}) {
-		where = append(where, fmt.Sprintf("%s = $%d", cond, len(args)+1))
-		args = append(args, val)
+	where, args := f.conditions()
+	where, args = f.keyset(where, args)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
 	}
-	//
-	if f.Type != "" {
-		addFilter("type", f.Type)
+
+	// сортировка
+	query += " ORDER BY " + f.orderBy()
+
+	query, args = f.paginate(query, args)
+
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
 	}
-	if f.Category != "" {
-		addFilter("category", f.Category)
+	defer rows.Close()
+
+	var products []*models.Product
+	for rows.Next() {
+		var p models.Product
+		if err := scanProduct(rows, &p); err != nil {
+			return nil, err
+		}
+		products = append(products, &p)
 	}
-	if f.InStock != nil {
-		addFilter("in_stock", *f.InStock)
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	if !f.FromDate.IsZero() {
-		where = append(where, fmt.Sprintf("created_at >= $%d", len(args)+1))
-		args = append(args, f.FromDate)
+
+	// загрузка вариантов и изображений
+	if err := r.loadRelations(products...); err != nil {
+		return nil, err
 	}
 
+	return products, nil
+}
+
+// Count возвращает количество товаров, подходящих под фильтр (без учёта пагинации)
+func (r *productRepo) Count(f ProductFilter) (int, error) {
+	query := `SELECT count(*) FROM products`
+
+	where, args := f.conditions()
 	if len(where) > 0 {
 		query += " WHERE " + strings.Join(where, " AND ")
 	}
 
-	// сортировка
+	var total int
+	err := r.db.QueryRow(context.Background(), query, args...).Scan(&total)
+	return total, err
+}
+
+// Search выполняет полнотекстовый поиск по f.Query с учётом остальных фильтров.
+// Без явной сортировки результаты упорядочены по релевантности.
+func (r *productRepo) Search(f ProductFilter) ([]*models.ProductSearchResult, error) {
+	where, args := f.conditions()
+
+	// условие совпадения добавляет conditions(), здесь запрос нужен для ранга и подсветки
+	args = append(args, f.Query)
+	tsQuery := fmt.Sprintf("websearch_to_tsquery('%s', $%d)", searchConfig, len(args))
+
+	headline := func(col string) string {
+		return fmt.Sprintf("ts_headline('%s', coalesce(%s, ''), %s, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')",
+			searchConfig, col, tsQuery)
+	}
+
+	query := `SELECT ` + productColumns + `,
+	                 ts_rank(search_vector, ` + tsQuery + `) AS rank,
+	                 ` + headline("title") + `,
+	                 ` + headline("description") + `
+	          FROM products
+	          WHERE ` + strings.Join(where, " AND ")
+
 	if f.Sort != "" {
-		sortMap := map[string]string{
-			"price":     "price",
-			"createdAt": "created_at",
-		}
-		if col, ok := sortMap[f.Sort]; ok {
-			query += " ORDER BY " + col
-		}
+		query += " ORDER BY " + f.orderBy()
 	} else {
-		query += " ORDER BY created_at DESC"
+		query += " ORDER BY rank DESC, created_at DESC"
 	}
 
-	// пагинация
-	if f.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
-		args = append(args, f.Limit)
-	}
-	if f.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", len(args)+1)
-		args = append(args, f.Offset)
-	}
+	query, args = f.paginate(query, args)
 
 	rows, err := r.db.Query(context.Background(), query, args...)
 	if err != nil {
@@ -169,67 +240,108 @@ func (r *productRepo) GetAll(f ProductFilter) ([]*models.Product, error) {
 	}
 	defer rows.Close()
 
-	var products []*models.Product
+	var results []*models.ProductSearchResult
 	for rows.Next() {
-		var p models.Product
-		var attributes, includes, tags []byte
-
-		if err := rows.Scan(
-			&p.ID, &p.Type, &p.Category, &p.Title, &p.Slug, &p.Description,
-			&p.Price, &p.OldPrice, &p.InStock, &p.UnitCount,
-			&attributes, &includes, &tags,
-			&p.CreatedAt, &p.UpdatedAt,
+		res := models.ProductSearchResult{Product: &models.Product{}}
+		if err := scanProduct(rows, res.Product,
+			&res.Rank, &res.Highlight.Title, &res.Highlight.Description,
 		); err != nil {
 			return nil, err
 		}
+		results = append(results, &res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		_ = json.Unmarshal(attributes, &p.Attributes)
-		_ = json.Unmarshal(includes, &p.Includes)
-		_ = json.Unmarshal(tags, &p.Tags)
+	products := make([]*models.Product, 0, len(results))
+	for _, res := range results {
+		products = append(products, res.Product)
+	}
+	if err := r.loadRelations(products...); err != nil {
+		return nil, err
+	}
 
-		// загрузка изображений
-		imgRows, err := r.db.Query(context.Background(),
-			`SELECT id, product_id, url, filename FROM images WHERE product_id = $1`, p.ID)
-		if err != nil {
+	return results, nil
+}
+
+// GetFacets считает товары по каждому значению атрибутов и диапазон цен для отфильтрованного списка.
+// Для атрибутов с активным фильтром счётчики считаются без учёта этого фильтра,
+// чтобы в панели каталога оставались видны альтернативные значения.
+func (r *productRepo) GetFacets(f ProductFilter) (*models.ProductFacets, error) {
+	facets := &models.ProductFacets{Attributes: map[string][]models.FacetValue{}}
+
+	active := f.filteredAttributes()
+	if err := r.countAttributeFacets(f, "", active, facets.Attributes); err != nil {
+		return nil, err
+	}
+	for _, key := range active {
+		if err := r.countAttributeFacets(f.withoutAttribute(key), key, nil, facets.Attributes); err != nil {
 			return nil, err
 		}
+	}
 
-		for imgRows.Next() {
-			var img models.Image
-			if err := imgRows.Scan(&img.ID, &img.ProductID, &img.URL, &img.Filename); err != nil {
-				imgRows.Close()
-				return nil, err
-			}
-			p.Images = append(p.Images, img)
-		}
-		imgRows.Close()
+	// диапазон цен считается без учёта фильтра по цене
+	priceFilter := f
+	priceFilter.PriceMin, priceFilter.PriceMax = nil, nil
 
-		products = append(products, &p)
+	query := `SELECT coalesce(min(price), 0), coalesce(max(price), 0) FROM products`
+	where, args := priceFilter.conditions()
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
 	}
 
-	return products, nil
+	err := r.db.QueryRow(context.Background(), query, args...).Scan(&facets.Price.Min, &facets.Price.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
 }
 
-func (r *productRepo) GetCategories() ([]string, error) {
-	query := `SELECT DISTINCT category FROM products ORDER BY category`
-	rows, err := r.db.Query(context.Background(), query)
+// countAttributeFacets группирует товары по парам ключ/значение атрибутов.
+// onlyKey ограничивает подсчёт одним ключом, excludeKeys — исключает ключи.
+func (r *productRepo) countAttributeFacets(f ProductFilter, onlyKey string, excludeKeys []string, dst map[string][]models.FacetValue) error {
+	where, args := f.conditions()
+	where = append(where, "jsonb_typeof(a.value) IN ('string', 'number', 'boolean')")
+
+	if onlyKey != "" {
+		args = append(args, onlyKey)
+		where = append(where, fmt.Sprintf("a.key = $%d", len(args)))
+	}
+	if len(excludeKeys) > 0 {
+		args = append(args, excludeKeys)
+		where = append(where, fmt.Sprintf("a.key <> ALL($%d)", len(args)))
+	}
+
+	query := `SELECT a.key, a.value #>> '{}', count(*)
+	          FROM products
+	          CROSS JOIN LATERAL jsonb_each(
+	              CASE WHEN jsonb_typeof(attributes) = 'object' THEN attributes ELSE '{}'::jsonb END
+	          ) AS a(key, value)
+	          WHERE ` + strings.Join(where, " AND ") + `
+	          GROUP BY 1, 2
+	          ORDER BY 1, 3 DESC, 2`
+
+	rows, err := r.db.Query(context.Background(), query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var categories []string
 	for rows.Next() {
-		var cat string
-		if err := rows.Scan(&cat); err != nil {
-			return nil, err
+		var key string
+		var fv models.FacetValue
+		if err := rows.Scan(&key, &fv.Value, &fv.Count); err != nil {
+			return err
 		}
-		categories = append(categories, cat)
+		dst[key] = append(dst[key], fv)
 	}
-
-	return categories, nil
+	return rows.Err()
 }
 
+// Update перезаписывает товар. Если p.Version не ноль, запись обновляется только при совпадении
+// версии, иначе возвращается ErrVersionConflict. Новая версия записывается в p.Version.
 func (r *productRepo) Update(slug string, p *models.Product) error {
 	query := `
 	UPDATE products SET
@@ -243,27 +355,583 @@ func (r *productRepo) Update(slug string, p *models.Product) error {
 		in_stock = $8,
 		unit_count = $9,
 		attributes = $10,
-		includes = $11,
-		tags = $12,
-		updated_at = $13
-	WHERE slug = $14
+		tags = $11,
+		updated_at = $12,
+		category_id = $14,
+		set_discount_percent = $15,
+		slug = $16,
+		status = $17,
+		publish_at = $18,
+		unpublish_at = $19,
+		version = version + 1,
+		search_vector = ` + searchVectorExpr("$4::text", "$11", "$5::text", "$10") + `
+	WHERE slug = $13 AND deleted_at IS NULL AND ($20 = 0 OR version = $20)
+	RETURNING version
 	`
 
 	attrs, _ := json.Marshal(p.Attributes)
-	includes, _ := json.Marshal(p.Includes)
 	tags, _ := json.Marshal(p.Tags)
 
-	_, err := r.db.Exec(context.Background(), query,
-		p.ID, p.Type, p.Category, p.Title, p.Description,
-		p.Price, p.OldPrice, p.InStock, p.UnitCount,
-		attrs, includes, tags,
-		p.UpdatedAt, slug,
-	)
+	ctx := context.Background()
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query,
+			p.ID, p.Type, p.Category, p.Title, p.Description,
+			p.Price, p.OldPrice, p.InStock, p.UnitCount,
+			attrs, tags,
+			p.UpdatedAt, slug,
+			p.CategoryID, p.SetDiscountPercent, p.Slug,
+			p.Status, p.PublishAt, p.UnpublishAt,
+			p.Version,
+		).Scan(&p.Version)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return mapUniqueViolation(err)
+		}
+		if p.Slug != slug {
+			if err := renameSlug(ctx, tx, p.ID, slug, p.Slug); err != nil {
+				return err
+			}
+		}
+		return r.saveComposition(ctx, tx, p)
+	})
+}
+
+// patchColumns возвращает колонки и значения, соответствующие JSON-полю товара;
+// поля без собственной колонки (includes) дают nil
+func patchColumns(p *models.Product, field string) map[string]interface{} {
+	switch field {
+	case "type":
+		return map[string]interface{}{"type": p.Type}
+	case "categoryId", "category":
+		return map[string]interface{}{"category_id": p.CategoryID, "category": p.Category}
+	case "title":
+		return map[string]interface{}{"title": p.Title}
+	case "slug":
+		return map[string]interface{}{"slug": p.Slug}
+	case "description":
+		return map[string]interface{}{"description": p.Description}
+	case "price":
+		return map[string]interface{}{"price": p.Price}
+	case "oldPrice":
+		return map[string]interface{}{"old_price": p.OldPrice}
+	case "inStock":
+		return map[string]interface{}{"in_stock": p.InStock}
+	case "unitCount":
+		return map[string]interface{}{"unit_count": p.UnitCount}
+	case "attributes":
+		return map[string]interface{}{"attributes": jsonValue(p.Attributes)}
+	case "tags":
+		return map[string]interface{}{"tags": jsonValue(p.Tags)}
+	case "setDiscountPercent":
+		return map[string]interface{}{"set_discount_percent": p.SetDiscountPercent}
+	case "status":
+		return map[string]interface{}{"status": p.Status}
+	case "publishAt":
+		return map[string]interface{}{"publish_at": p.PublishAt}
+	case "unpublishAt":
+		return map[string]interface{}{"unpublish_at": p.UnpublishAt}
+	}
+	return nil
+}
+
+func jsonValue(v interface{}) string {
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+// Patch обновляет только колонки, соответствующие изменённым полям fields (JSON-имена).
+// Версия проверяется так же, как в Update; состав набора пересохраняется, если изменились includes или type.
+func (r *productRepo) Patch(slug string, p *models.Product, fields []string) error {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	values := map[string]interface{}{}
+	for _, field := range fields {
+		for col, v := range patchColumns(p, field) {
+			values[col] = v
+		}
+	}
+
+	// поисковый вектор пересчитывается, если изменилось хотя бы одно из его полей;
+	// для остальных подставляются текущие значения колонок
+	searchSource := map[string]string{"title": "title", "tags": "tags", "description": "description", "attributes": "attributes"}
+	searchChanged := false
+
+	var set []string
+	for _, col := range sortedKeys(values) {
+		placeholder := arg(values[col])
+		switch col {
+		case "attributes", "tags":
+			placeholder += "::jsonb"
+		case "title", "description":
+			placeholder += "::text"
+		}
+		if _, ok := searchSource[col]; ok {
+			searchSource[col] = placeholder
+			searchChanged = true
+		}
+		set = append(set, col+" = "+placeholder)
+	}
+	set = append(set, "updated_at = "+arg(p.UpdatedAt), "version = version + 1")
+	if searchChanged {
+		set = append(set, "search_vector = "+searchVectorExpr(
+			searchSource["title"], searchSource["tags"], searchSource["description"], searchSource["attributes"]))
+	}
+
+	query := fmt.Sprintf(`UPDATE products SET %s
+		WHERE slug = %s AND deleted_at IS NULL AND (%s = 0 OR version = %[3]s)
+		RETURNING version`,
+		strings.Join(set, ", "), arg(slug), arg(p.Version))
+
+	ctx := context.Background()
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, args...).Scan(&p.Version)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVersionConflict
+		}
+		if err != nil {
+			return mapUniqueViolation(err)
+		}
+		if p.Slug != slug {
+			if err := renameSlug(ctx, tx, p.ID, slug, p.Slug); err != nil {
+				return err
+			}
+		}
+		return r.saveComposition(ctx, tx, p)
+	})
+}
 
+// renameSlug запоминает старый slug товара для постоянного редиректа.
+// Новый slug убирается из истории, если товар возвращается к нему.
+func renameSlug(ctx context.Context, tx pgx.Tx, productID, oldSlug, newSlug string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM product_slug_history WHERE slug = $1`, newSlug); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO product_slug_history (slug, product_id, created_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (slug) DO UPDATE SET product_id = EXCLUDED.product_id, created_at = EXCLUDED.created_at`,
+		oldSlug, productID)
 	return err
 }
 
+// ResolveSlug возвращает текущий slug товара по одному из его прежних slug
+func (r *productRepo) ResolveSlug(oldSlug string) (string, error) {
+	var slug string
+	err := r.db.QueryRow(context.Background(), `
+		SELECT p.slug
+		FROM product_slug_history h
+		JOIN products p ON p.id = h.product_id
+		WHERE h.slug = $1 AND p.deleted_at IS NULL`, oldSlug).Scan(&slug)
+	return slug, err
+}
+
+// SlugTaken проверяет, занят ли slug другим товаром — текущим или прежним slug
+func (r *productRepo) SlugTaken(slug, exceptID string) (bool, error) {
+	var taken bool
+	err := r.db.QueryRow(context.Background(), `
+		SELECT EXISTS (SELECT 1 FROM products WHERE slug = $1 AND id <> $2)
+		    OR EXISTS (SELECT 1 FROM product_slug_history WHERE slug = $1 AND product_id <> $2)`,
+		slug, exceptID).Scan(&taken)
+	return taken, err
+}
+
+// Delete переносит товар в корзину. Товар, входящий в действующий набор, удалить нельзя (ErrReferenced).
 func (r *productRepo) Delete(slug string) error {
-	_, err := r.db.Exec(context.Background(), `DELETE FROM products WHERE slug = $1`, slug)
-	return err
+	ctx := context.Background()
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var referenced bool
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1
+				FROM products p
+				JOIN set_items si ON si.product_id = p.id
+				JOIN products s ON s.id = si.set_id AND s.deleted_at IS NULL
+				WHERE p.slug = $1
+			)`, slug).Scan(&referenced)
+		if err != nil {
+			return err
+		}
+		if referenced {
+			return ErrReferenced
+		}
+
+		tag, err := tx.Exec(ctx, `
+			UPDATE products SET deleted_at = $2, updated_at = $2, version = version + 1
+			WHERE slug = $1 AND deleted_at IS NULL`, slug, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// GetDeletedBySlug возвращает товар из корзины
+func (r *productRepo) GetDeletedBySlug(slug string) (*models.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE slug = $1 AND deleted_at IS NOT NULL`
+
+	var p models.Product
+	if err := scanProduct(r.db.QueryRow(context.Background(), query, slug), &p); err != nil {
+		return nil, err
+	}
+	if err := r.loadRelations(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Restore возвращает товар из корзины вместе с изображениями и пересчитывает связанные наборы
+func (r *productRepo) Restore(slug string) error {
+	ctx := context.Background()
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		var id string
+		var typ models.ProductType
+		err := tx.QueryRow(ctx, `
+			UPDATE products SET deleted_at = NULL, updated_at = $2, version = version + 1
+			WHERE slug = $1 AND deleted_at IS NOT NULL
+			RETURNING id, type`, slug, time.Now().UTC()).Scan(&id, &typ)
+		if err != nil {
+			return err
+		}
+
+		if typ == models.TypeSet {
+			return syncSet(ctx, tx, id)
+		}
+		return syncSetsContaining(ctx, tx, id)
+	})
+}
+
+// PurgeResult — итог очистки корзины: удалённые товары и файлы их изображений
+type PurgeResult struct {
+	ProductIDs []string
+	Filenames  []string
+}
+
+// Purge окончательно удаляет товары, попавшие в корзину раньше before, вместе с записями изображений.
+// Сначала удаляются наборы, затем товары; товар, который всё ещё входит в набор, остаётся в корзине.
+// Сами файлы не трогаются — их имена возвращаются в PurgeResult.
+func (r *productRepo) Purge(ctx context.Context, before time.Time) (*PurgeResult, error) {
+	res := &PurgeResult{}
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		for _, cond := range []string{
+			"type = 'set'",
+			"NOT EXISTS (SELECT 1 FROM set_items si WHERE si.product_id = products.id)",
+		} {
+			ids, err := collectStrings(tx.Query(ctx, `
+				SELECT id FROM products
+				WHERE deleted_at < $1 AND `+cond+`
+				FOR UPDATE`, before))
+			if err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+
+			files, err := collectStrings(tx.Query(ctx, `SELECT filename FROM images WHERE product_id = ANY($1)`, ids))
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM products WHERE id = ANY($1)`, ids); err != nil {
+				return err
+			}
+
+			res.ProductIDs = append(res.ProductIDs, ids...)
+			res.Filenames = append(res.Filenames, files...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// collectStrings читает первый столбец всех строк результата
+func collectStrings(rows pgx.Rows, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// ApplySchedule публикует черновики, у которых наступил publish_at,
+// и отправляет в архив опубликованные товары с наступившим unpublish_at
+func (r *productRepo) ApplySchedule(ctx context.Context, now time.Time) ([]models.StatusTransition, error) {
+	steps := []struct {
+		from, to models.ProductStatus
+		due      string
+	}{
+		{models.StatusDraft, models.StatusPublished, "publish_at <= $3 AND (unpublish_at IS NULL OR unpublish_at > $3)"},
+		{models.StatusPublished, models.StatusArchived, "unpublish_at <= $3"},
+	}
+
+	var transitions []models.StatusTransition
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		for _, step := range steps {
+			rows, err := tx.Query(ctx, `
+				UPDATE products SET status = $2, updated_at = $3, version = version + 1
+				WHERE status = $1 AND deleted_at IS NULL AND `+step.due+`
+				RETURNING id, slug`, step.from, step.to, now)
+			if err != nil {
+				return err
+			}
+
+			for rows.Next() {
+				t := models.StatusTransition{From: step.from, To: step.to}
+				if err := rows.Scan(&t.ProductID, &t.Slug); err != nil {
+					rows.Close()
+					return err
+				}
+				transitions = append(transitions, t)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return transitions, err
+}
+
+func (r *productRepo) LastModified(ctx context.Context) (time.Time, error) {
+	// наступившие publish_at/unpublish_at меняют видимость товара без обновления updated_at;
+	// они хранятся в UTC, как и в publishedCondition
+	var t time.Time
+	err := r.db.QueryRow(ctx, `
+		SELECT coalesce(greatest(
+			(SELECT max(updated_at) FROM products),
+			(SELECT max(publish_at) FROM products WHERE publish_at <= (now() AT TIME ZONE 'UTC')),
+			(SELECT max(unpublish_at) FROM products WHERE unpublish_at <= (now() AT TIME ZONE 'UTC')),
+			(SELECT max(created_at) FROM images),
+			(SELECT max(updated_at) FROM categories)
+		), 'epoch'::timestamp)`).Scan(&t)
+	return t, err
+}
+
+// saveComposition сохраняет состав набора и пересчитывает производные поля:
+// для набора — его наличие и цену, для товара — наборы, в которые он входит
+func (r *productRepo) saveComposition(ctx context.Context, tx pgx.Tx, p *models.Product) error {
+	if p.Type != models.TypeSet {
+		if err := trackProductStock(ctx, tx, p); err != nil {
+			return err
+		}
+		if err := syncSetsContaining(ctx, tx, p.ID); err != nil {
+			return err
+		}
+	} else {
+		if err := replaceSetItems(ctx, tx, p.ID, p.Includes); err != nil {
+			return err
+		}
+		if err := syncSet(ctx, tx, p.ID); err != nil {
+			return err
+		}
+	}
+	return recordPriceChanges(ctx, tx, p.ID)
+}
+
+// trackProductStock сверяет остаток товара без вариантов с журналом склада (см. trackStock);
+// остаток и наличие товара с вариантами пересчитываются по вариантам
+func trackProductStock(ctx context.Context, tx pgx.Tx, p *models.Product) error {
+	var hasVariants bool
+	err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)`, p.ID).Scan(&hasVariants)
+	if err != nil {
+		return err
+	}
+	if hasVariants {
+		return syncProductStock(ctx, tx, p.ID)
+	}
+	return trackStock(ctx, tx, stockKey{productID: p.ID}, p.UnitCount)
+}
+
+func (r *productRepo) WithTx(ctx context.Context, fn func(repo ProductRepository) error) error {
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		return fn(&productRepo{db: tx})
+	})
+}
+
+func (r *productRepo) Revisions() RevisionRepository {
+	return &revisionRepo{db: r.db}
+}
+
+func (r *productRepo) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// scanProduct читает колонки productColumns (и дополнительные колонки extra) в p
+func scanProduct(row pgx.Row, p *models.Product, extra ...interface{}) error {
+	var attributes, tags []byte
+
+	dest := []interface{}{
+		&p.ID, &p.Type, &p.CategoryID, &p.Category, &p.Title, &p.Slug, &p.Description,
+		&p.Price, &p.OldPrice, &p.InStock, &p.UnitCount,
+		&attributes, &tags, &p.SetDiscountPercent,
+		&p.Status, &p.PublishAt, &p.UnpublishAt,
+		&p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &p.Version,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	// Распаковываем JSON-поля
+	_ = json.Unmarshal(attributes, &p.Attributes)
+	_ = json.Unmarshal(tags, &p.Tags)
+
+	return nil
+}
+
+// loadRelations подгружает варианты, состав наборов, минимальные цены и изображения для страницы товаров
+// фиксированным числом запросов, независимо от её размера
+func (r *productRepo) loadRelations(products ...*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	if err := r.loadVariants(products); err != nil {
+		return err
+	}
+	if err := r.loadSetItems(products); err != nil {
+		return err
+	}
+	if err := r.loadLowestPrices(products); err != nil {
+		return err
+	}
+	return r.loadImages(products)
+}
+
+// loadLowestPrices подставляет минимальную цену за последние LowestPriceWindow
+func (r *productRepo) loadLowestPrices(products []*models.Product) error {
+	lowest, err := fetchLowestPrices(context.Background(), r.db, productIDs(products), time.Now().UTC().Add(-LowestPriceWindow))
+	if err != nil {
+		return err
+	}
+	for _, p := range products {
+		if price, ok := lowest[p.ID]; ok {
+			p.LowestPrice30Days = &price
+		}
+	}
+	return nil
+}
+
+// loadSetItems загружает состав наборов
+func (r *productRepo) loadSetItems(products []*models.Product) error {
+	var setIDs []string
+	for _, p := range products {
+		if p.Type == models.TypeSet {
+			setIDs = append(setIDs, p.ID)
+		}
+	}
+	if len(setIDs) == 0 {
+		return nil
+	}
+
+	bySet, err := fetchSetItems(context.Background(), r.db, setIDs)
+	if err != nil {
+		return err
+	}
+	for _, p := range products {
+		if p.Type == models.TypeSet {
+			p.Includes = bySet[p.ID]
+		}
+	}
+	return nil
+}
+
+// loadVariants загружает варианты и считает по ним диапазон цен и наличие
+func (r *productRepo) loadVariants(products []*models.Product) error {
+	byProduct, err := fetchVariants(context.Background(), r.db, productIDs(products))
+	if err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		p.Variants = byProduct[p.ID]
+		if len(p.Variants) == 0 {
+			continue
+		}
+
+		rng := models.PriceRange{Min: -1}
+		inStock := false
+		for _, v := range p.Variants {
+			price := p.Price
+			if v.Price != nil {
+				price = *v.Price
+			}
+			if rng.Min < 0 || price < rng.Min {
+				rng.Min = price
+			}
+			if price > rng.Max {
+				rng.Max = price
+			}
+			inStock = inStock || v.InStock
+		}
+		p.PriceRange = &rng
+		p.InStock = inStock
+	}
+	return nil
+}
+
+// loadImages загружает изображения всех переданных товаров одним запросом.
+// Изображения, привязанные к варианту, попадают в images этого варианта.
+func (r *productRepo) loadImages(products []*models.Product) error {
+	byID := make(map[string]*models.Product, len(products))
+	variants := make(map[string]*models.ProductVariant)
+	for _, p := range products {
+		byID[p.ID] = p
+		for i := range p.Variants {
+			variants[p.Variants[i].ID] = &p.Variants[i]
+		}
+	}
+
+	rows, err := r.db.Query(context.Background(), `
+		SELECT id, product_id, variant_id, url, filename, created_at
+		FROM images
+		WHERE product_id = ANY($1)
+		ORDER BY created_at, id`, productIDs(products))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var img models.Image
+		if err := rows.Scan(&img.ID, &img.ProductID, &img.VariantID, &img.URL, &img.Filename, &img.CreatedAt); err != nil {
+			return err
+		}
+		if img.VariantID != nil {
+			if v, ok := variants[*img.VariantID]; ok {
+				v.Images = append(v.Images, img)
+				continue
+			}
+		}
+		if p, ok := byID[img.ProductID]; ok {
+			p.Images = append(p.Images, img)
+		}
+	}
+	return rows.Err()
+}
+
+func productIDs(products []*models.Product) []string {
+	ids := make([]string, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	return ids
 }
@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"dozenChairs/internal/models"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ProductFilter struct {
+	Type     string
+	Category string // slug или название категории, включая подкатегории
+	InStock  *bool
+	Query    string // строка полнотекстового поиска
+	PriceMin *int
+	PriceMax *int
+	Tags     []string // товар должен содержать все перечисленные теги
+	// StockCity и StockWarehouse (код или ID склада) ограничивают фильтр InStock остатками
+	// работающих складов этого города или этим складом
+	StockCity      string
+	StockWarehouse string
+	// Attributes — фильтр по значениям атрибутов: ключ → допустимые значения (любое из них)
+	Attributes map[string][]string
+	// AttributeRanges — числовые диапазоны по атрибутам (например, seat_height от 45 до 50)
+	AttributeRanges map[string]NumericRange
+	// Sort — ключ сортировки из sortMap, префикс "-" означает обратный порядок
+	Sort   string
+	Limit  int
+	Offset int
+	// Cursor — позиция keyset-пагинации; при наличии Offset игнорируется
+	Cursor   *ProductCursor
+	FromDate time.Time
+	// WithUnpublished — выдача для админки: черновики и архив не скрываются
+	WithUnpublished bool
+	Status          string // только товары в этом статусе (для админки)
+	Trashed         bool   // выдача корзины: только удалённые товары
+}
+
+// publishedCondition — товар виден на витрине: опубликован и срок показа не истёк.
+// Совпадает с models.Product.Visible. publish_at/unpublish_at хранятся как TIMESTAMP в UTC,
+// поэтому now() приводится к UTC, а не к часовому поясу сессии.
+const publishedCondition = `status = 'published'
+	AND (publish_at IS NULL OR publish_at <= (now() AT TIME ZONE 'UTC'))
+	AND (unpublish_at IS NULL OR unpublish_at > (now() AT TIME ZONE 'UTC'))`
+
+// ProductCursor — последняя запись предыдущей страницы для keyset-пагинации
+type ProductCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type sortColumn struct {
+	column string
+	cast   string // SQL-тип, к которому приводится значение курсора
+	value  func(p *models.Product) string
+}
+
+const cursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+// sortMap — допустимые ключи сортировки и соответствующие колонки
+var sortMap = map[string]sortColumn{
+	"price": {
+		column: "price",
+		cast:   "integer",
+		value:  func(p *models.Product) string { return strconv.Itoa(p.Price) },
+	},
+	"createdAt": {
+		column: "created_at",
+		cast:   "timestamp",
+		value:  func(p *models.Product) string { return p.CreatedAt.Format(cursorTimeLayout) },
+	},
+}
+
+const defaultSort = "-createdAt"
+
+// sortOrder нормализует f.Sort: неизвестный ключ заменяется сортировкой по умолчанию
+func (f ProductFilter) sortOrder() (key string, col sortColumn, desc bool) {
+	key = f.Sort
+	if _, ok := sortMap[strings.TrimPrefix(key, "-")]; !ok {
+		key = defaultSort
+	}
+	desc = strings.HasPrefix(key, "-")
+	return key, sortMap[strings.TrimPrefix(key, "-")], desc
+}
+
+// orderBy возвращает выражение ORDER BY; id добавляется для стабильного порядка
+func (f ProductFilter) orderBy() string {
+	_, col, desc := f.sortOrder()
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s, id %s", col.column, dir, dir)
+}
+
+// keyset добавляет условие «после курсора» для текущей сортировки
+func (f ProductFilter) keyset(where []string, args []interface{}) ([]string, []interface{}) {
+	if f.Cursor == nil || f.Offset > 0 {
+		return where, args
+	}
+	_, col, desc := f.sortOrder()
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	args = append(args, f.Cursor.Value, f.Cursor.ID)
+	where = append(where, fmt.Sprintf("(%s, id) %s ($%d::%s, $%d)", col.column, op, len(args)-1, col.cast, len(args)))
+	return where, args
+}
+
+// EncodeCursor формирует непрозрачный курсор, указывающий на товар p при сортировке фильтра f
+func EncodeCursor(f ProductFilter, p *models.Product) string {
+	raw, _ := json.Marshal(CursorAfter(f, p))
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// CursorAfter возвращает курсор на товар p при сортировке фильтра f — для обхода каталога страницами
+func CursorAfter(f ProductFilter, p *models.Product) *ProductCursor {
+	key, col, _ := f.sortOrder()
+	return &ProductCursor{Sort: key, Value: col.value(p), ID: p.ID}
+}
+
+// DecodeCursor разбирает курсор и проверяет, что он выдан для той же сортировки sortKey
+func DecodeCursor(s, sortKey string) (*ProductCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c ProductCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if key, _, _ := (ProductFilter{Sort: sortKey}).sortOrder(); c.Sort != key {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// NumericRange — границы диапазона, nil означает отсутствие ограничения
+type NumericRange struct {
+	Min *float64
+	Max *float64
+}
+
+// filteredAttributes возвращает отсортированные ключи атрибутов, по которым задан фильтр
+func (f ProductFilter) filteredAttributes() []string {
+	keys := sortedKeys(f.Attributes)
+	for _, k := range sortedKeys(f.AttributeRanges) {
+		if _, ok := f.Attributes[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// withoutAttribute возвращает копию фильтра без условий по атрибуту key
+// (нужно для подсчёта фасетов по самому фильтруемому атрибуту)
+func (f ProductFilter) withoutAttribute(key string) ProductFilter {
+	attrs := make(map[string][]string, len(f.Attributes))
+	for k, v := range f.Attributes {
+		if k != key {
+			attrs[k] = v
+		}
+	}
+	ranges := make(map[string]NumericRange, len(f.AttributeRanges))
+	for k, v := range f.AttributeRanges {
+		if k != key {
+			ranges[k] = v
+		}
+	}
+	f.Attributes = attrs
+	f.AttributeRanges = ranges
+	return f
+}
+
+// conditions собирает WHERE-условия фильтра и их аргументы
+func (f ProductFilter) conditions() ([]string, []interface{}) {
+	var args []interface{}
+	var where []string
+
+	arg := func(val interface{}) string {
+		args = append(args, val)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	addFilter := func(cond string, val interface{}) {
+		where = append(where, fmt.Sprintf("%s = %s", cond, arg(val)))
+	}
+
+	if f.Trashed {
+		where = append(where, "deleted_at IS NOT NULL")
+	} else {
+		where = append(where, "deleted_at IS NULL")
+	}
+	if !f.WithUnpublished {
+		where = append(where, "("+publishedCondition+")")
+	}
+	if f.Status != "" {
+		addFilter("status", f.Status)
+	}
+	if f.Type != "" {
+		addFilter("type", f.Type)
+	}
+	if f.Category != "" {
+		// категория задаётся slug'ом или названием и включает все подкатегории
+		where = append(where, fmt.Sprintf(`category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE slug = %[1]s OR name = %[1]s
+				UNION
+				SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+			)
+			SELECT id FROM tree
+		)`, arg(f.Category)))
+	}
+	if f.InStock != nil {
+		if scope := f.stockScope(arg); scope != "" {
+			where = append(where, fmt.Sprintf("(%s) = %s", fmt.Sprintf(availableInScope, scope), arg(*f.InStock)))
+		} else {
+			addFilter("in_stock", *f.InStock)
+		}
+	}
+	if !f.FromDate.IsZero() {
+		where = append(where, "created_at >= "+arg(f.FromDate))
+	}
+	if f.Query != "" {
+		where = append(where, fmt.Sprintf("search_vector @@ websearch_to_tsquery('%s', %s)", searchConfig, arg(f.Query)))
+	}
+	if f.PriceMin != nil {
+		where = append(where, "price >= "+arg(*f.PriceMin))
+	}
+	if f.PriceMax != nil {
+		where = append(where, "price <= "+arg(*f.PriceMax))
+	}
+	if len(f.Tags) > 0 {
+		tagsJson, _ := json.Marshal(f.Tags)
+		where = append(where, "tags @> "+arg(string(tagsJson))+"::jsonb")
+	}
+
+	for _, key := range sortedKeys(f.Attributes) {
+		// Каждое значение проверяется через @> — так работает GIN-индекс по attributes
+		var alts []string
+		for _, v := range f.Attributes[key] {
+			for _, candidate := range attributeCandidates(key, v) {
+				alts = append(alts, "attributes @> "+arg(candidate)+"::jsonb")
+			}
+		}
+		if len(alts) > 0 {
+			where = append(where, "("+strings.Join(alts, " OR ")+")")
+		}
+	}
+
+	for _, key := range sortedKeys(f.AttributeRanges) {
+		rng := f.AttributeRanges[key]
+		k := arg(key)
+		// CASE гарантирует, что приведение к numeric выполняется только для чисел
+		value := fmt.Sprintf("CASE WHEN jsonb_typeof(attributes->%[1]s) = 'number' THEN (attributes->>%[1]s)::numeric END", k)
+		if rng.Min != nil {
+			where = append(where, value+" >= "+arg(*rng.Min))
+		}
+		if rng.Max != nil {
+			where = append(where, value+" <= "+arg(*rng.Max))
+		}
+		if rng.Min == nil && rng.Max == nil {
+			where = append(where, value+" IS NOT NULL")
+		}
+	}
+
+	return where, args
+}
+
+// availableInScope — товар есть в наличии на складах, отобранных условием %[1]s над warehouses w:
+// у товара доступен хоть один экземпляр (любого варианта), у набора — каждый компонент в нужном количестве
+const availableInScope = `CASE WHEN type = 'set' THEN
+		EXISTS (SELECT 1 FROM set_items si WHERE si.set_id = products.id)
+		AND NOT EXISTS (
+			SELECT 1 FROM set_items si
+			WHERE si.set_id = products.id
+			  AND (
+				SELECT coalesce(sum(l.on_hand - l.reserved), 0)
+				FROM stock_levels l JOIN warehouses w ON w.id = l.warehouse_id
+				WHERE l.product_id = si.product_id AND l.variant_id IS NULL AND w.active AND %[1]s
+			  ) < si.quantity
+		)
+	ELSE EXISTS (
+		SELECT 1 FROM stock_levels l JOIN warehouses w ON w.id = l.warehouse_id
+		WHERE l.product_id = products.id AND l.on_hand > l.reserved AND w.active AND %[1]s
+	)
+	END`
+
+// stockScope возвращает условие на склады w для фильтра наличия по городу или складу;
+// пустая строка — наличие проверяется по всем складам
+func (f ProductFilter) stockScope(arg func(interface{}) string) string {
+	var conds []string
+	if city := strings.TrimSpace(f.StockCity); city != "" {
+		conds = append(conds, "lower(w.city) = lower("+arg(city)+")")
+	}
+	if f.StockWarehouse != "" {
+		conds = append(conds, fmt.Sprintf("(w.code = %[1]s OR w.id::text = %[1]s)", arg(f.StockWarehouse)))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// paginate добавляет к запросу LIMIT/OFFSET
+func (f ProductFilter) paginate(query string, args []interface{}) (string, []interface{}) {
+	if f.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+		args = append(args, f.Limit)
+	}
+	if f.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", len(args)+1)
+		args = append(args, f.Offset)
+	}
+	return query, args
+}
+
+// attributeCandidates возвращает JSON-объекты {key: value} для всех типов,
+// которыми может быть представлено строковое значение из запроса (строка, число, bool)
+func attributeCandidates(key, value string) []string {
+	values := []interface{}{value}
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		values = append(values, n)
+	}
+	if value == "true" || value == "false" {
+		values = append(values, value == "true")
+	}
+
+	candidates := make([]string, 0, len(values))
+	for _, v := range values {
+		obj, _ := json.Marshal(map[string]interface{}{key: v})
+		candidates = append(candidates, string(obj))
+	}
+	return candidates
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
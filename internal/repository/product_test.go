@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// queryCounter считает запросы, отправленные через пул
+type queryCounter struct {
+	n atomic.Int64
+}
+
+func (c *queryCounter) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	c.n.Add(1)
+	return ctx
+}
+
+func (c *queryCounter) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+// count возвращает, сколько запросов выполнил fn
+func (c *queryCounter) count(tb testing.TB, fn func() error) int64 {
+	tb.Helper()
+	before := c.n.Load()
+	if err := fn(); err != nil {
+		tb.Fatal(err)
+	}
+	return c.n.Load() - before
+}
+
+// newCountingRepo подключается к базе из TEST_DATABASE_DSN (с применёнными миграциями)
+// и возвращает репозиторий товаров со счётчиком запросов
+func newCountingRepo(tb testing.TB) (*productRepo, *queryCounter) {
+	tb.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		tb.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		tb.Fatalf("parse dsn: %v", err)
+	}
+	counter := &queryCounter{}
+	cfg.ConnConfig.Tracer = counter
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		tb.Fatalf("connect: %v", err)
+	}
+	tb.Cleanup(pool.Close)
+
+	return &productRepo{db: pool}, counter
+}
+
+// seedProducts создаёт n товаров с двумя изображениями у каждого и удаляет их после теста.
+// Все товары помечены тегом и содержат его в названии, чтобы выбрать только их.
+func seedProducts(tb testing.TB, r *productRepo, n int) string {
+	tb.Helper()
+	ctx := context.Background()
+	tag := "seed" + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+
+	categoryID := uuid.NewString()
+	if _, err := r.db.Exec(ctx, `INSERT INTO categories (id, name, slug) VALUES ($1, $2, $3)`,
+		categoryID, "Тест "+tag, tag); err != nil {
+		tb.Fatalf("create category: %v", err)
+	}
+
+	var ids []string
+	tb.Cleanup(func() {
+		_, _ = r.db.Exec(ctx, `DELETE FROM products WHERE id = ANY($1)`, ids)
+		_, _ = r.db.Exec(ctx, `DELETE FROM categories WHERE id = $1`, categoryID)
+	})
+
+	now := time.Now().UTC()
+	for i := 0; i < n; i++ {
+		p := &models.Product{
+			ID:         uuid.NewString(),
+			Type:       models.TypeProduct,
+			CategoryID: categoryID,
+			Category:   "Тест " + tag,
+			Title:      "Стул " + tag,
+			Slug:       fmt.Sprintf("%s-%d", tag, i),
+			Status:     models.StatusPublished,
+			Price:      1000 + i,
+			InStock:    true,
+			Tags:       []string{tag},
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := r.Create(p); err != nil {
+			tb.Fatalf("create product: %v", err)
+		}
+		ids = append(ids, p.ID)
+
+		for j := 0; j < 2; j++ {
+			_, err := r.db.Exec(ctx, `
+				INSERT INTO images (id, product_id, url, filename, created_at)
+				VALUES ($1, $2, $3, $4, $5)`,
+				uuid.NewString(), p.ID, "/uploads/test.jpg", "test.jpg", now)
+			if err != nil {
+				tb.Fatalf("create image: %v", err)
+			}
+		}
+	}
+	return tag
+}
+
+// checkImages проверяет, что страница полная и у каждого товара загружены изображения
+func checkImages(products []*models.Product, limit int) error {
+	if len(products) != limit {
+		return fmt.Errorf("got %d products, want %d", len(products), limit)
+	}
+	for _, p := range products {
+		if len(p.Images) != 2 {
+			return fmt.Errorf("product %s has %d images, want 2", p.ID, len(p.Images))
+		}
+	}
+	return nil
+}
+
+// productLists — публичные методы списков, количество запросов которых не должно зависеть от размера страницы
+func productLists(r *productRepo, tag string) map[string]func(limit int) error {
+	return map[string]func(limit int) error{
+		"GetAll": func(limit int) error {
+			products, err := r.GetAll(ProductFilter{Tags: []string{tag}, Limit: limit})
+			if err != nil {
+				return err
+			}
+			return checkImages(products, limit)
+		},
+		"Search": func(limit int) error {
+			results, err := r.Search(ProductFilter{Query: tag, Tags: []string{tag}, Limit: limit})
+			if err != nil {
+				return err
+			}
+			products := make([]*models.Product, 0, len(results))
+			for _, res := range results {
+				products = append(products, res.Product)
+			}
+			return checkImages(products, limit)
+		},
+	}
+}
+
+func TestProductListQueryCountIndependentOfPageSize(t *testing.T) {
+	repo, counter := newCountingRepo(t)
+	tag := seedProducts(t, repo, 50)
+
+	for name, list := range productLists(repo, tag) {
+		t.Run(name, func(t *testing.T) {
+			single := counter.count(t, func() error { return list(1) })
+			page := counter.count(t, func() error { return list(50) })
+
+			if single != page {
+				t.Fatalf("queries for 1 product = %d, for 50 products = %d; want equal", single, page)
+			}
+		})
+	}
+}
+
+func BenchmarkProductList(b *testing.B) {
+	repo, counter := newCountingRepo(b)
+	tag := seedProducts(b, repo, 50)
+
+	for name, list := range productLists(repo, tag) {
+		for _, size := range []int{1, 50} {
+			b.Run(fmt.Sprintf("%s/N=%d", name, size), func(b *testing.B) {
+				var queries int64
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					queries += counter.count(b, func() error { return list(size) })
+				}
+				b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrUsageLimitReached — промокод уже применён максимальное число раз
+	ErrUsageLimitReached = errors.New("usage limit reached")
+	// ErrUserUsageLimitReached — покупатель уже применил промокод максимальное число раз
+	ErrUserUsageLimitReached = errors.New("per-user usage limit reached")
+)
+
+type PromoCodeRepository interface {
+	Create(ctx context.Context, c *models.PromoCode) error
+	GetByID(ctx context.Context, id string) (*models.PromoCode, error)
+	// GetByCode ищет промокод без учёта регистра
+	GetByCode(ctx context.Context, code string) (*models.PromoCode, error)
+	GetAll(ctx context.Context) ([]*models.PromoCode, error)
+	Update(ctx context.Context, c *models.PromoCode) error
+	Delete(ctx context.Context, id string) error
+	// CountRedemptions возвращает, сколько раз пользователь применил промокод
+	CountRedemptions(ctx context.Context, codeID, userID string) (int, error)
+}
+
+type promoCodeRepo struct {
+	db *pgxpool.Pool
+}
+
+const promoCodeColumns = `id, code, coalesce(description, ''), discount_type, discount_value, min_order_amount,
+	category_ids, product_ids, usage_limit, per_user_limit, used_count,
+	starts_at, expires_at, enabled, created_at, updated_at`
+
+func NewPromoCodeRepo(db *pgxpool.Pool) PromoCodeRepository {
+	return &promoCodeRepo{db: db}
+}
+
+func (r *promoCodeRepo) Create(ctx context.Context, c *models.PromoCode) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO promo_codes (
+			id, code, description, discount_type, discount_value, min_order_amount,
+			category_ids, product_ids, usage_limit, per_user_limit,
+			starts_at, expires_at, enabled, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		c.ID, c.Code, c.Description, c.DiscountType, c.DiscountValue, c.MinOrderAmount,
+		nonNil(c.CategoryIDs), nonNil(c.ProductIDs), c.UsageLimit, c.PerUserLimit,
+		c.StartsAt, c.ExpiresAt, c.Enabled, c.CreatedAt, c.UpdatedAt,
+	)
+	return mapUniqueViolation(err)
+}
+
+func (r *promoCodeRepo) GetByID(ctx context.Context, id string) (*models.PromoCode, error) {
+	return scanPromoCode(r.db.QueryRow(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes WHERE id = $1`, id))
+}
+
+func (r *promoCodeRepo) GetByCode(ctx context.Context, code string) (*models.PromoCode, error) {
+	return scanPromoCode(r.db.QueryRow(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes WHERE upper(code) = upper($1)`, code))
+}
+
+func (r *promoCodeRepo) GetAll(ctx context.Context) ([]*models.PromoCode, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes ORDER BY created_at DESC, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []*models.PromoCode
+	for rows.Next() {
+		c, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+	}
+	return codes, rows.Err()
+}
+
+// Update меняет настройки промокода; счётчик применений не трогается
+func (r *promoCodeRepo) Update(ctx context.Context, c *models.PromoCode) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE promo_codes SET
+			code = $2,
+			description = $3,
+			discount_type = $4,
+			discount_value = $5,
+			min_order_amount = $6,
+			category_ids = $7,
+			product_ids = $8,
+			usage_limit = $9,
+			per_user_limit = $10,
+			starts_at = $11,
+			expires_at = $12,
+			enabled = $13,
+			updated_at = $14
+		WHERE id = $1`,
+		c.ID, c.Code, c.Description, c.DiscountType, c.DiscountValue, c.MinOrderAmount,
+		nonNil(c.CategoryIDs), nonNil(c.ProductIDs), c.UsageLimit, c.PerUserLimit,
+		c.StartsAt, c.ExpiresAt, c.Enabled, c.UpdatedAt,
+	)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *promoCodeRepo) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM promo_codes WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *promoCodeRepo) CountRedemptions(ctx context.Context, codeID, userID string) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx,
+		`SELECT count(*) FROM promo_code_redemptions WHERE promo_code_id = $1 AND user_id = $2`,
+		codeID, userID,
+	).Scan(&n)
+	return n, err
+}
+
+// redeemPromoCode внутри транзакции db увеличивает счётчик применений и записывает применение.
+// Возвращает ErrUsageLimitReached или ErrUserUsageLimitReached, если лимит исчерпан,
+// и pgx.ErrNoRows, если код выключен или не действует в момент rd.CreatedAt.
+//
+// used_count увеличивается условным UPDATE: он берёт блокировку строки промокода,
+// и конкурентные применения того же кода выполняются по очереди, каждое перепроверяя
+// условие на счётчике, записанном предыдущим, поэтому лимит не превышается.
+// Пока блокировка удерживается, подсчёт применений пользователя тоже точен.
+func redeemPromoCode(ctx context.Context, db dbtx, rd *models.PromoCodeRedemption) error {
+	var perUserLimit *int
+	err := db.QueryRow(ctx, `
+		UPDATE promo_codes SET used_count = used_count + 1
+		WHERE id = $1
+		  AND enabled
+		  AND (starts_at IS NULL OR starts_at <= $2)
+		  AND (expires_at IS NULL OR expires_at > $2)
+		  AND (usage_limit IS NULL OR used_count < usage_limit)
+		RETURNING per_user_limit`,
+		rd.PromoCodeID, rd.CreatedAt,
+	).Scan(&perUserLimit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// код не найден, не действует или исчерпан — уточняем причину
+		var exhausted bool
+		err := db.QueryRow(ctx,
+			`SELECT usage_limit IS NOT NULL AND used_count >= usage_limit FROM promo_codes WHERE id = $1`,
+			rd.PromoCodeID,
+		).Scan(&exhausted)
+		if err == nil && exhausted {
+			return ErrUsageLimitReached
+		}
+		return pgx.ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
+
+	if perUserLimit != nil {
+		var n int
+		err := db.QueryRow(ctx,
+			`SELECT count(*) FROM promo_code_redemptions WHERE promo_code_id = $1 AND user_id = $2`,
+			rd.PromoCodeID, rd.UserID,
+		).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n >= *perUserLimit {
+			return ErrUserUsageLimitReached
+		}
+	}
+
+	_, err = db.Exec(ctx, `
+		INSERT INTO promo_code_redemptions (id, promo_code_id, user_id, order_id, discount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		rd.ID, rd.PromoCodeID, rd.UserID, rd.OrderID, rd.Discount, rd.CreatedAt,
+	)
+	return err
+}
+
+// releasePromoCode внутри транзакции db отменяет применения промокодов заказом orderID:
+// удаляет их и возвращает использования в счётчики кодов
+func releasePromoCode(ctx context.Context, db dbtx, orderID string) error {
+	_, err := db.Exec(ctx, `
+		WITH released AS (
+			DELETE FROM promo_code_redemptions WHERE order_id = $1
+			RETURNING promo_code_id
+		)
+		UPDATE promo_codes c SET used_count = greatest(c.used_count - r.n, 0)
+		FROM (SELECT promo_code_id, count(*) AS n FROM released GROUP BY promo_code_id) r
+		WHERE c.id = r.promo_code_id`,
+		orderID,
+	)
+	return err
+}
+
+func scanPromoCode(row pgx.Row) (*models.PromoCode, error) {
+	var c models.PromoCode
+	if err := row.Scan(
+		&c.ID, &c.Code, &c.Description, &c.DiscountType, &c.DiscountValue, &c.MinOrderAmount,
+		&c.CategoryIDs, &c.ProductIDs, &c.UsageLimit, &c.PerUserLimit, &c.UsedCount,
+		&c.StartsAt, &c.ExpiresAt, &c.Enabled, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
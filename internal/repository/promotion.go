@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PromotionRepository interface {
+	Create(ctx context.Context, p *models.Promotion) error
+	GetByID(ctx context.Context, id string) (*models.Promotion, error)
+	GetAll(ctx context.Context) ([]*models.Promotion, error)
+	// GetActive возвращает акции, действующие в момент now, в порядке применения
+	GetActive(ctx context.Context, now time.Time) ([]*models.Promotion, error)
+	Update(ctx context.Context, p *models.Promotion) error
+	Delete(ctx context.Context, id string) error
+}
+
+type promotionRepo struct {
+	db *pgxpool.Pool
+}
+
+const promotionColumns = `id, name, coalesce(description, ''), discount_type, discount_value,
+	category_ids, tags, product_ids, product_type, starts_at, ends_at,
+	priority, stackable, enabled, created_at, updated_at`
+
+// promotionOrder — порядок применения акций: по убыванию приоритета, при равенстве — раньше созданные
+const promotionOrder = `priority DESC, created_at, id`
+
+func NewPromotionRepo(db *pgxpool.Pool) PromotionRepository {
+	return &promotionRepo{db: db}
+}
+
+func (r *promotionRepo) Create(ctx context.Context, p *models.Promotion) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO promotions (
+			id, name, description, discount_type, discount_value,
+			category_ids, tags, product_ids, product_type, starts_at, ends_at,
+			priority, stackable, enabled, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+		p.ID, p.Name, p.Description, p.DiscountType, p.DiscountValue,
+		nonNil(p.CategoryIDs), nonNil(p.Tags), nonNil(p.ProductIDs), p.ProductType, p.StartsAt, p.EndsAt,
+		p.Priority, p.Stackable, p.Enabled, p.CreatedAt, p.UpdatedAt,
+	)
+	return err
+}
+
+func (r *promotionRepo) GetByID(ctx context.Context, id string) (*models.Promotion, error) {
+	return scanPromotion(r.db.QueryRow(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE id = $1`, id))
+}
+
+func (r *promotionRepo) GetAll(ctx context.Context) ([]*models.Promotion, error) {
+	return r.query(ctx, `SELECT `+promotionColumns+` FROM promotions ORDER BY `+promotionOrder)
+}
+
+func (r *promotionRepo) GetActive(ctx context.Context, now time.Time) ([]*models.Promotion, error) {
+	return r.query(ctx, `
+		SELECT `+promotionColumns+` FROM promotions
+		WHERE enabled
+		  AND (starts_at IS NULL OR starts_at <= $1)
+		  AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY `+promotionOrder, now)
+}
+
+func (r *promotionRepo) Update(ctx context.Context, p *models.Promotion) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE promotions SET
+			name = $2,
+			description = $3,
+			discount_type = $4,
+			discount_value = $5,
+			category_ids = $6,
+			tags = $7,
+			product_ids = $8,
+			product_type = $9,
+			starts_at = $10,
+			ends_at = $11,
+			priority = $12,
+			stackable = $13,
+			enabled = $14,
+			updated_at = $15
+		WHERE id = $1`,
+		p.ID, p.Name, p.Description, p.DiscountType, p.DiscountValue,
+		nonNil(p.CategoryIDs), nonNil(p.Tags), nonNil(p.ProductIDs), p.ProductType, p.StartsAt, p.EndsAt,
+		p.Priority, p.Stackable, p.Enabled, p.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *promotionRepo) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM promotions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *promotionRepo) query(ctx context.Context, sql string, args ...interface{}) ([]*models.Promotion, error) {
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []*models.Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+func scanPromotion(row pgx.Row) (*models.Promotion, error) {
+	var p models.Promotion
+	if err := row.Scan(
+		&p.ID, &p.Name, &p.Description, &p.DiscountType, &p.DiscountValue,
+		&p.CategoryIDs, &p.Tags, &p.ProductIDs, &p.ProductType, &p.StartsAt, &p.EndsAt,
+		&p.Priority, &p.Stackable, &p.Enabled, &p.CreatedAt, &p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// nonNil заменяет nil-срез пустым, чтобы в NOT NULL колонку-массив записался '{}'
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
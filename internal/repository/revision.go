@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RevisionRepository interface {
+	Create(ctx context.Context, rev *models.ProductRevision) error
+	GetByID(ctx context.Context, id string) (*models.ProductRevision, error)
+	GetByProductID(ctx context.Context, productID string, limit, offset int) ([]*models.ProductRevision, int, error)
+}
+
+type revisionRepo struct {
+	db dbtx
+}
+
+const revisionColumns = `id, product_id, action, user_id, changes, snapshot, source_revision_id, created_at`
+
+func NewRevisionRepo(db *pgxpool.Pool) RevisionRepository {
+	return &revisionRepo{db: db}
+}
+
+func (r *revisionRepo) Create(ctx context.Context, rev *models.ProductRevision) error {
+	changes, _ := json.Marshal(rev.Changes)
+
+	var snapshot []byte
+	if rev.Snapshot != nil {
+		snapshot, _ = json.Marshal(rev.Snapshot)
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO product_revisions (`+revisionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		rev.ID, rev.ProductID, rev.Action, rev.UserID, changes, snapshot, rev.SourceRevisionID, rev.CreatedAt,
+	)
+	return err
+}
+
+func (r *revisionRepo) GetByID(ctx context.Context, id string) (*models.ProductRevision, error) {
+	return scanRevision(r.db.QueryRow(ctx, `SELECT `+revisionColumns+` FROM product_revisions WHERE id = $1`, id))
+}
+
+// GetByProductID возвращает правки товара от новых к старым (без снимков) и их общее количество
+func (r *revisionRepo) GetByProductID(ctx context.Context, productID string, limit, offset int) ([]*models.ProductRevision, int, error) {
+	var total int
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM product_revisions WHERE product_id = $1`, productID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, product_id, action, user_id, changes, NULL::jsonb, source_revision_id, created_at
+		FROM product_revisions
+		WHERE product_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, productID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var revisions []*models.ProductRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, total, rows.Err()
+}
+
+func scanRevision(row pgx.Row) (*models.ProductRevision, error) {
+	var rev models.ProductRevision
+	var changes, snapshot []byte
+	if err := row.Scan(
+		&rev.ID, &rev.ProductID, &rev.Action, &rev.UserID, &changes, &snapshot, &rev.SourceRevisionID, &rev.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	_ = json.Unmarshal(changes, &rev.Changes)
+	if len(snapshot) > 0 {
+		rev.Snapshot = &models.Product{}
+		_ = json.Unmarshal(snapshot, rev.Snapshot)
+	}
+	return &rev, nil
+}
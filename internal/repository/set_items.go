@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// syncSetsQuery пересчитывает производные поля наборов по их составу:
+//   - unit_count — сколько комплектов можно собрать из остатков компонентов
+//     (NULL, если у всех доступных компонентов остаток не ведётся);
+//   - in_stock — набор в наличии, только если каждого компонента хватает;
+//   - price — сумма цен компонентов минус set_discount_percent, если скидка задана,
+//     иначе цена набора остаётся введённой вручную.
+//
+// %s — условие отбора наборов в подзапросе по set_items (алиас si).
+const syncSetsQuery = `
+	UPDATE products s SET
+		unit_count = agg.units,
+		in_stock = coalesce(agg.units > 0, agg.all_available),
+		price = CASE
+			WHEN s.set_discount_percent IS NULL THEN s.price
+			ELSE agg.total_price * (100 - s.set_discount_percent) / 100
+		END
+	FROM (
+		SELECT si.set_id,
+		       min(CASE
+		               WHEN NOT p.in_stock THEN 0
+		               WHEN p.unit_count IS NULL THEN NULL
+		               ELSE p.unit_count / si.quantity
+		           END) AS units,
+		       bool_and(p.in_stock) AS all_available,
+		       sum(p.price * si.quantity) AS total_price
+		FROM set_items si
+		JOIN products p ON p.id = si.product_id
+		WHERE %s
+		GROUP BY si.set_id
+	) agg
+	WHERE s.id = agg.set_id`
+
+// syncSet пересчитывает производные поля одного набора
+func syncSet(ctx context.Context, db execer, setID string) error {
+	_, err := db.Exec(ctx, fmt.Sprintf(syncSetsQuery, "si.set_id = $1"), setID)
+	return err
+}
+
+// syncSetsContaining пересчитывает все наборы, в которые входит товар productID
+func syncSetsContaining(ctx context.Context, db execer, productID string) error {
+	_, err := db.Exec(ctx,
+		fmt.Sprintf(syncSetsQuery, "si.set_id IN (SELECT set_id FROM set_items WHERE product_id = $1)"),
+		productID)
+	return err
+}
+
+// replaceSetItems перезаписывает состав набора в порядке items
+func replaceSetItems(ctx context.Context, tx pgx.Tx, setID string, items []models.IncludeItem) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM set_items WHERE set_id = $1`, setID); err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO set_items (set_id, product_id, quantity, position)
+			VALUES ($1, $2, $3, $4)`,
+			setID, item.ProductID, item.Quantity, i,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchSetItems загружает состав наборов одним запросом
+func fetchSetItems(ctx context.Context, db dbtx, setIDs []string) (map[string][]models.IncludeItem, error) {
+	rows, err := db.Query(ctx, `
+		SELECT set_id, product_id, quantity
+		FROM set_items
+		WHERE set_id = ANY($1)
+		ORDER BY position, product_id`, setIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bySet := make(map[string][]models.IncludeItem)
+	for rows.Next() {
+		var setID string
+		var item models.IncludeItem
+		if err := rows.Scan(&setID, &item.ProductID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		bySet[setID] = append(bySet[setID], item)
+	}
+	return bySet, rows.Err()
+}
@@ -0,0 +1,666 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrInsufficientStock — движение сделало бы остаток отрицательным или меньше зарезервированного
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// StockEffect — что смена статуса заказа делает с его резервами
+type StockEffect int
+
+const (
+	StockKeep    StockEffect = iota // резервы не меняются
+	StockRelease                    // активные резервы снимаются (заказ отменён)
+	StockSell                       // активные резервы списываются со склада как продажа (заказ оплачен)
+	StockReturn                     // проданные товары возвращаются на склад (возврат денег до отгрузки)
+)
+
+// StockMovementFilter — условия выборки журнала движений
+type StockMovementFilter struct {
+	ProductID   string
+	VariantID   string
+	WarehouseID string
+	Type        models.StockMovementType
+	Limit       int
+	Offset      int
+}
+
+// StockRepository ведёт складские остатки по складам. Каждое изменение остатка — строка журнала
+// stock_movements в той же транзакции; строка остатка при этом блокируется, поэтому одновременные
+// списания выполняются по очереди и остаток не уходит в минус. В колонки товаров и вариантов
+// переносится доступный остаток, суммированный по работающим складам.
+type StockRepository interface {
+	// GetLevels возвращает остатки товара по складам: без варианта и по каждому варианту
+	GetLevels(ctx context.Context, productID string) ([]models.StockLevel, error)
+	GetMovements(ctx context.Context, filter StockMovementFilter) ([]models.StockMovement, int, error)
+	// Record проводит ручное движение (поступление, возврат, корректировку) по складу m.WarehouseID
+	// и возвращает новый остаток. Если остаток стал бы меньше нуля или зарезервированного,
+	// возвращает ErrInsufficientStock.
+	Record(ctx context.Context, m *models.StockMovement) (*models.StockLevel, error)
+	// ExpiredReservationOrders возвращает заказы с активными резервами, срок которых истёк к now
+	ExpiredReservationOrders(ctx context.Context, now time.Time, limit int) ([]string, error)
+	// ReleaseOrder снимает активные резервы заказа
+	ReleaseOrder(ctx context.Context, orderID string, actorID *string, reason string) error
+}
+
+type stockRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStockRepo(db *pgxpool.Pool) StockRepository {
+	return &stockRepo{db: db}
+}
+
+// stockItemCond — условие строки остатка по товару $1 и варианту $2 в форме уникального индекса
+const stockItemCond = `product_id = $1
+	AND coalesce(variant_id, '00000000-0000-0000-0000-000000000000'::uuid) = coalesce($2::uuid, '00000000-0000-0000-0000-000000000000'::uuid)`
+
+// stockKey — позиция склада: товар и вариант ("" — без варианта) на складе warehouseID.
+// Пустой warehouseID означает позицию по всем складам.
+type stockKey struct {
+	productID   string
+	variantID   string
+	warehouseID string
+}
+
+func (k stockKey) variant() *string {
+	if k.variantID == "" {
+		return nil
+	}
+	return &k.variantID
+}
+
+// item возвращает позицию без привязки к складу
+func (k stockKey) item() stockKey {
+	return stockKey{productID: k.productID, variantID: k.variantID}
+}
+
+func (k stockKey) less(o stockKey) bool {
+	if k.productID != o.productID {
+		return k.productID < o.productID
+	}
+	if k.variantID != o.variantID {
+		return k.variantID < o.variantID
+	}
+	return k.warehouseID < o.warehouseID
+}
+
+func newStockKey(productID string, variantID *string, warehouseID string) stockKey {
+	k := stockKey{productID: productID, warehouseID: warehouseID}
+	if variantID != nil {
+		k.variantID = *variantID
+	}
+	return k
+}
+
+func (r *stockRepo) GetLevels(ctx context.Context, productID string) ([]models.StockLevel, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT l.product_id, l.variant_id::text, l.warehouse_id::text, l.on_hand, l.reserved, l.updated_at
+		FROM stock_levels l
+		JOIN warehouses w ON w.id = l.warehouse_id
+		WHERE l.product_id = $1
+		ORDER BY l.variant_id NULLS FIRST, `+warehouseOrder, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []models.StockLevel{}
+	for rows.Next() {
+		var l models.StockLevel
+		if err := rows.Scan(&l.ProductID, &l.VariantID, &l.WarehouseID, &l.OnHand, &l.Reserved, &l.UpdatedAt); err != nil {
+			return nil, err
+		}
+		l.Available = l.OnHand - l.Reserved
+		levels = append(levels, l)
+	}
+	return levels, rows.Err()
+}
+
+func (r *stockRepo) GetMovements(ctx context.Context, filter StockMovementFilter) ([]models.StockMovement, int, error) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.ProductID != "" {
+		add("product_id = $%d", filter.ProductID)
+	}
+	if filter.VariantID != "" {
+		add("variant_id = $%d::uuid", filter.VariantID)
+	}
+	if filter.WarehouseID != "" {
+		add("warehouse_id = $%d::uuid", filter.WarehouseID)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM stock_movements`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT id, product_id, variant_id::text, warehouse_id::text, type, quantity, on_hand_after, reserved_after, reason,
+		       order_id::text, actor_id, created_at
+		FROM stock_movements%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := []models.StockMovement{}
+	for rows.Next() {
+		var m models.StockMovement
+		if err := rows.Scan(
+			&m.ID, &m.ProductID, &m.VariantID, &m.WarehouseID, &m.Type, &m.Quantity, &m.OnHandAfter, &m.ReservedAfter, &m.Reason,
+			&m.OrderID, &m.ActorID, &m.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, m)
+	}
+	return movements, total, rows.Err()
+}
+
+func (r *stockRepo) Record(ctx context.Context, m *models.StockMovement) (*models.StockLevel, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	key := newStockKey(m.ProductID, m.VariantID, m.WarehouseID)
+	if err := ensureStockLevel(ctx, tx, key); err != nil {
+		return nil, err
+	}
+	if err := applyStockMovement(ctx, tx, m); err != nil {
+		return nil, err
+	}
+	if err := syncStockProducts(ctx, tx, []stockKey{key}); err != nil {
+		return nil, err
+	}
+
+	level := &models.StockLevel{
+		ProductID:   m.ProductID,
+		VariantID:   m.VariantID,
+		WarehouseID: m.WarehouseID,
+		OnHand:      m.OnHandAfter,
+		Reserved:    m.ReservedAfter,
+		UpdatedAt:   m.CreatedAt,
+	}
+	level.Available = level.OnHand - level.Reserved
+	return level, tx.Commit(ctx)
+}
+
+func (r *stockRepo) ExpiredReservationOrders(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT order_id::text
+		FROM stock_reservations
+		WHERE status = 'active' AND expires_at <= $1
+		GROUP BY order_id
+		ORDER BY min(expires_at)
+		LIMIT $2`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *stockRepo) ReleaseOrder(ctx context.Context, orderID string, actorID *string, reason string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := closeReservations(ctx, tx, orderID, StockRelease, actorID, reason); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// applyStockMovement изменяет строку остатка по движению m и записывает его в журнал
+// с остатком после движения. Строка остатка блокируется до конца транзакции; условие в UPDATE
+// перепроверяется на последней версии строки, поэтому одновременные движения не выводят остаток
+// за допустимые границы. Если строки остатка нет, возвращает pgx.ErrNoRows.
+func applyStockMovement(ctx context.Context, db dbtx, m *models.StockMovement) error {
+	var onHand, reserved int
+	switch m.Type {
+	case models.StockReceipt, models.StockReturn, models.StockAdjustment:
+		onHand = m.Quantity
+	case models.StockReservation:
+		reserved = m.Quantity
+	case models.StockRelease:
+		reserved = -m.Quantity
+	case models.StockSale:
+		onHand, reserved = -m.Quantity, -m.Quantity
+	default:
+		return fmt.Errorf("unknown stock movement type %q", m.Type)
+	}
+	if m.ID == "" {
+		m.ID = uuid.NewString()
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+
+	err := db.QueryRow(ctx, `
+		UPDATE stock_levels SET
+			on_hand = on_hand + $4,
+			reserved = reserved + $5,
+			updated_at = $6
+		WHERE `+stockItemCond+` AND warehouse_id = $3
+		  AND on_hand + $4 >= 0
+		  AND reserved + $5 >= 0
+		  AND reserved + $5 <= on_hand + $4
+		RETURNING on_hand, reserved`,
+		m.ProductID, m.VariantID, m.WarehouseID, onHand, reserved, m.CreatedAt,
+	).Scan(&m.OnHandAfter, &m.ReservedAfter)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var available int
+		err := db.QueryRow(ctx, `SELECT on_hand - reserved FROM stock_levels WHERE `+stockItemCond+` AND warehouse_id = $3`,
+			m.ProductID, m.VariantID, m.WarehouseID).Scan(&available)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: product %s, available %d", ErrInsufficientStock, m.ProductID, available)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(ctx, `
+		INSERT INTO stock_movements (id, product_id, variant_id, warehouse_id, type, quantity, on_hand_after, reserved_after,
+		                             reason, order_id, actor_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		m.ID, m.ProductID, m.VariantID, m.WarehouseID, m.Type, m.Quantity, m.OnHandAfter, m.ReservedAfter,
+		m.Reason, m.OrderID, m.ActorID, m.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return projectStockLevel(ctx, db, newStockKey(m.ProductID, m.VariantID, ""))
+}
+
+func ensureStockLevel(ctx context.Context, db dbtx, key stockKey) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO stock_levels (product_id, variant_id, warehouse_id) VALUES ($1, $2, $3)
+		ON CONFLICT (product_id, (coalesce(variant_id, '00000000-0000-0000-0000-000000000000'::uuid)), warehouse_id) DO NOTHING`,
+		key.productID, key.variant(), key.warehouseID)
+	return err
+}
+
+// projectStockLevel переносит доступный остаток позиции, суммированный по работающим складам,
+// в колонки товара или варианта. Позиция без строк остатка не учитывается и не меняется.
+func projectStockLevel(ctx context.Context, db dbtx, key stockKey) error {
+	const available = `
+		SELECT coalesce(sum(l.on_hand - l.reserved) FILTER (WHERE w.active), 0)::int AS total
+		FROM stock_levels l
+		JOIN warehouses w ON w.id = l.warehouse_id
+		WHERE ` + stockItemCond + `
+		HAVING count(*) > 0`
+	if key.variantID != "" {
+		_, err := db.Exec(ctx, `
+			UPDATE product_variants v SET unit_count = l.total
+			FROM (`+available+`) l
+			WHERE v.id = $2`, key.productID, key.variant())
+		return err
+	}
+	_, err := db.Exec(ctx, `
+		UPDATE products p SET
+			unit_count = l.total,
+			in_stock = l.total > 0
+		FROM (`+available+`) l
+		WHERE p.id = $1`, key.productID, nil)
+	return err
+}
+
+// syncStockProducts пересчитывает остатки товаров с вариантами и наборы, в которые входят товары keys
+func syncStockProducts(ctx context.Context, db dbtx, keys []stockKey) error {
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if seen[key.productID] {
+			continue
+		}
+		seen[key.productID] = true
+		if err := syncProductStock(ctx, db, key.productID); err != nil {
+			return err
+		}
+		if err := syncSetsContaining(ctx, db, key.productID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectWarehouseStock пересчитывает остатки всех товаров, лежащих на складе warehouseID
+// (после того как склад включили или выключили)
+func projectWarehouseStock(ctx context.Context, db dbtx, warehouseID string) error {
+	rows, err := db.Query(ctx, `
+		SELECT DISTINCT product_id, variant_id::text
+		FROM stock_levels
+		WHERE warehouse_id = $1`, warehouseID)
+	if err != nil {
+		return err
+	}
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (stockKey, error) {
+		var productID string
+		var variantID *string
+		err := row.Scan(&productID, &variantID)
+		return newStockKey(productID, variantID, ""), err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := projectStockLevel(ctx, db, key); err != nil {
+			return err
+		}
+	}
+	return syncStockProducts(ctx, db, keys)
+}
+
+// trackStock приводит остаток товара или варианта, сохранённого вручную, к журналу склада.
+// Если остаток уже учитывается, колонки перезаписываются суммой по складам
+// (вручную остаток не меняется — только движениями). Если ещё не учитывается, а initial задан,
+// начинается учёт: initial записывается корректировкой как начальный остаток первого по приоритету склада.
+func trackStock(ctx context.Context, db dbtx, key stockKey, initial *int) error {
+	var tracked bool
+	err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM stock_levels WHERE `+stockItemCond+`)`,
+		key.productID, key.variant()).Scan(&tracked)
+	if err != nil {
+		return err
+	}
+	if tracked || initial == nil {
+		return projectStockLevel(ctx, db, key)
+	}
+
+	err = db.QueryRow(ctx, `SELECT id::text FROM warehouses w WHERE active ORDER BY `+warehouseOrder+` LIMIT 1`).
+		Scan(&key.warehouseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// ни одного работающего склада — остаток учитывать негде
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := ensureStockLevel(ctx, db, key); err != nil {
+		return err
+	}
+	if *initial == 0 {
+		return projectStockLevel(ctx, db, key)
+	}
+	return applyStockMovement(ctx, db, &models.StockMovement{
+		ProductID:   key.productID,
+		VariantID:   key.variant(),
+		WarehouseID: key.warehouseID,
+		Type:        models.StockAdjustment,
+		Quantity:    *initial,
+		Reason:      "initial stock",
+	})
+}
+
+// stockSource — остаток позиции на складе, из которого можно зарезервировать товар под заказ
+type stockSource struct {
+	warehouse models.Warehouse
+	available int
+}
+
+// reserveOrderStock резервирует позиции заказа до expiresAt. Наборы резервируются по компонентам.
+// Заказ с самовывозом резервируется только на складе самовывоза, с доставкой — на складах,
+// которые отгружают в город заказа: сначала в этом городе, затем по приоритету; позиция может
+// собираться с нескольких складов. Строки остатков блокируются в одном порядке (по товару, варианту
+// и складу), чтобы одновременные заказы не блокировали друг друга. Товары, остаток которых
+// не учитывается, не резервируются. Возвращает true, если зарезервирована хотя бы одна позиция.
+func reserveOrderStock(ctx context.Context, db dbtx, o *models.Order, expiresAt time.Time) (bool, error) {
+	quantities := make(map[stockKey]int)
+	for _, item := range o.Items {
+		if item.Type != models.TypeSet {
+			quantities[newStockKey(item.ProductID, item.VariantID, "")] += item.Quantity
+			continue
+		}
+		components, err := fetchSetItems(ctx, db, []string{item.ProductID})
+		if err != nil {
+			return false, err
+		}
+		for _, c := range components[item.ProductID] {
+			quantities[stockKey{productID: c.ProductID}] += c.Quantity * item.Quantity
+		}
+	}
+
+	keys := make([]stockKey, 0, len(quantities))
+	for key := range quantities {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	var reserved []stockKey
+	for _, key := range keys {
+		sources, tracked, err := lockStockSources(ctx, db, key, o)
+		if err != nil {
+			return false, err
+		}
+		if !tracked {
+			// у варианта без строк остатка остаток нулевой; товар без них — без учёта остатка,
+			// если только остаток не ведётся по его вариантам (компонент набора без указания варианта)
+			if key.variantID != "" {
+				return false, fmt.Errorf("%w: product %s, available 0", ErrInsufficientStock, key.productID)
+			}
+			var hasVariants bool
+			err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)`, key.productID).Scan(&hasVariants)
+			if err != nil {
+				return false, err
+			}
+			if hasVariants {
+				return false, fmt.Errorf("%w: product %s has variants, available 0", ErrInsufficientStock, key.productID)
+			}
+			continue
+		}
+
+		need, available := quantities[key], 0
+		for _, src := range sources {
+			available += src.available
+		}
+		if available < need {
+			return false, fmt.Errorf("%w: product %s, available %d", ErrInsufficientStock, key.productID, available)
+		}
+
+		for _, src := range sources {
+			if need == 0 {
+				break
+			}
+			quantity := min(need, src.available)
+			if quantity <= 0 {
+				continue
+			}
+			need -= quantity
+
+			orderID := o.ID
+			err := applyStockMovement(ctx, db, &models.StockMovement{
+				ProductID:   key.productID,
+				VariantID:   key.variant(),
+				WarehouseID: src.warehouse.ID,
+				Type:        models.StockReservation,
+				Quantity:    quantity,
+				OrderID:     &orderID,
+				ActorID:     &o.UserID,
+				CreatedAt:   o.CreatedAt,
+			})
+			if err != nil {
+				return false, err
+			}
+
+			_, err = db.Exec(ctx, `
+				INSERT INTO stock_reservations (id, order_id, product_id, variant_id, warehouse_id, quantity, status, expires_at, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, 'active', $7, $8)`,
+				uuid.NewString(), o.ID, key.productID, key.variant(), src.warehouse.ID, quantity, expiresAt, o.CreatedAt,
+			)
+			if err != nil {
+				return false, err
+			}
+		}
+		reserved = append(reserved, key)
+	}
+	return len(reserved) > 0, syncStockProducts(ctx, db, reserved)
+}
+
+// lockStockSources блокирует строки остатка позиции key на всех складах и возвращает склады,
+// из которых можно зарезервировать товар для заказа o, в порядке резервирования.
+// tracked = false, если остаток позиции не учитывается ни на одном складе.
+func lockStockSources(ctx context.Context, db dbtx, key stockKey, o *models.Order) (sources []stockSource, tracked bool, err error) {
+	rows, err := db.Query(ctx, `
+		SELECT l.on_hand - l.reserved, `+warehouseColumns+`
+		FROM stock_levels l
+		JOIN warehouses w ON w.id = l.warehouse_id
+		WHERE `+stockItemCond+`
+		ORDER BY l.warehouse_id
+		FOR UPDATE OF l`, key.productID, key.variant())
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var src stockSource
+		if err := scanWarehouse(rows, &src.warehouse, &src.available); err != nil {
+			return nil, false, err
+		}
+		tracked = true
+		if src.warehouse.Active && src.available > 0 && canFulfil(&src.warehouse, o) {
+			sources = append(sources, src)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		a, b := &sources[i].warehouse, &sources[j].warehouse
+		if inCity := models.SameCity(a.City, o.City); inCity != models.SameCity(b.City, o.City) {
+			return inCity
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.Code < b.Code
+	})
+	return sources, tracked, nil
+}
+
+// canFulfil сообщает, может ли склад w собрать заказ o: при самовывозе — это выбранный пункт,
+// при доставке — склад отгружает в город заказа
+func canFulfil(w *models.Warehouse, o *models.Order) bool {
+	if o.DeliveryMethod == models.DeliveryPickup {
+		return o.PickupPointID != nil && *o.PickupPointID == w.ID
+	}
+	_, ok := w.DeliveryDaysTo(o.City)
+	return ok
+}
+
+// closeReservations проводит по резервам заказа движения, соответствующие effect:
+// снятие или продажу активных резервов либо возврат проданного на склад
+func closeReservations(ctx context.Context, db dbtx, orderID string, effect StockEffect, actorID *string, reason string) error {
+	var from, to string
+	var movement models.StockMovementType
+	switch effect {
+	case StockRelease:
+		from, to, movement = "active", "released", models.StockRelease
+	case StockSell:
+		from, to, movement = "active", "sold", models.StockSale
+	case StockReturn:
+		from, to, movement = "sold", "returned", models.StockReturn
+	default:
+		return nil
+	}
+
+	rows, err := db.Query(ctx, `
+		SELECT id, product_id, variant_id::text, warehouse_id::text, quantity
+		FROM stock_reservations
+		WHERE order_id = $1 AND status = $2
+		ORDER BY product_id, variant_id NULLS FIRST, warehouse_id
+		FOR UPDATE`, orderID, from)
+	if err != nil {
+		return err
+	}
+	type reservation struct {
+		id       string
+		key      stockKey
+		quantity int
+	}
+	var reservations []reservation
+	for rows.Next() {
+		var res reservation
+		var productID, warehouseID string
+		var variantID *string
+		if err := rows.Scan(&res.id, &productID, &variantID, &warehouseID, &res.quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		res.key = newStockKey(productID, variantID, warehouseID)
+		reservations = append(reservations, res)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	keys := make([]stockKey, 0, len(reservations))
+	for _, res := range reservations {
+		err := applyStockMovement(ctx, db, &models.StockMovement{
+			ProductID:   res.key.productID,
+			VariantID:   res.key.variant(),
+			WarehouseID: res.key.warehouseID,
+			Type:        movement,
+			Quantity:    res.quantity,
+			Reason:      reason,
+			OrderID:     &orderID,
+			ActorID:     actorID,
+			CreatedAt:   now,
+		})
+		// товар или вариант удалён вместе со строкой остатка — резерв просто закрывается
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		_, err = db.Exec(ctx, `UPDATE stock_reservations SET status = $2, closed_at = $3 WHERE id = $1`, res.id, to, now)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, res.key.item())
+	}
+	return syncStockProducts(ctx, db, keys)
+}
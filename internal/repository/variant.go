@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type VariantRepository interface {
+	// Create добавляет вариант товару. Товару, входящему в набор, вариант не добавляется (ErrReferenced):
+	// состав набора не указывает вариант, и компонент нельзя было бы зарезервировать.
+	Create(ctx context.Context, v *models.ProductVariant) error
+	GetByID(ctx context.Context, id string) (*models.ProductVariant, error)
+	GetByProductID(ctx context.Context, productID string) ([]models.ProductVariant, error)
+	Update(ctx context.Context, v *models.ProductVariant) error
+	Delete(ctx context.Context, id string) error
+}
+
+type variantRepo struct {
+	db *pgxpool.Pool
+}
+
+const variantColumns = `id, product_id, sku, options, price, old_price, unit_count, sort_order, created_at, updated_at`
+
+func NewVariantRepo(db *pgxpool.Pool) VariantRepository {
+	return &variantRepo{db: db}
+}
+
+func (r *variantRepo) Create(ctx context.Context, v *models.ProductVariant) error {
+	options, _ := json.Marshal(v.Options)
+
+	return r.inTx(ctx, v.ProductID, func(tx pgx.Tx) error {
+		var inSet bool
+		err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM set_items WHERE product_id = $1)`, v.ProductID).Scan(&inSet)
+		if err != nil {
+			return err
+		}
+		if inSet {
+			return ErrReferenced
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO product_variants (`+variantColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			v.ID, v.ProductID, v.SKU, options, v.Price, v.OldPrice, v.UnitCount, v.SortOrder, v.CreatedAt, v.UpdatedAt,
+		)
+		if err != nil {
+			return mapUniqueViolation(err)
+		}
+		return r.trackStock(ctx, tx, v)
+	})
+}
+
+func (r *variantRepo) GetByID(ctx context.Context, id string) (*models.ProductVariant, error) {
+	var v models.ProductVariant
+	if err := scanVariant(r.db.QueryRow(ctx, `SELECT `+variantColumns+` FROM product_variants WHERE id = $1`, id), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *variantRepo) GetByProductID(ctx context.Context, productID string) ([]models.ProductVariant, error) {
+	byProduct, err := fetchVariants(ctx, r.db, []string{productID})
+	if err != nil {
+		return nil, err
+	}
+	return byProduct[productID], nil
+}
+
+func (r *variantRepo) Update(ctx context.Context, v *models.ProductVariant) error {
+	options, _ := json.Marshal(v.Options)
+
+	return r.inTx(ctx, v.ProductID, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE product_variants SET
+				sku = $1,
+				options = $2,
+				price = $3,
+				old_price = $4,
+				unit_count = $5,
+				sort_order = $6,
+				updated_at = $7
+			WHERE id = $8`,
+			v.SKU, options, v.Price, v.OldPrice, v.UnitCount, v.SortOrder, v.UpdatedAt, v.ID,
+		)
+		if err != nil {
+			return mapUniqueViolation(err)
+		}
+		return r.trackStock(ctx, tx, v)
+	})
+}
+
+// trackStock сверяет остаток варианта с журналом склада (см. trackStock) и возвращает в v итоговый остаток
+func (r *variantRepo) trackStock(ctx context.Context, tx pgx.Tx, v *models.ProductVariant) error {
+	if err := trackStock(ctx, tx, stockKey{productID: v.ProductID, variantID: v.ID}, &v.UnitCount); err != nil {
+		return err
+	}
+	if err := tx.QueryRow(ctx, `SELECT unit_count FROM product_variants WHERE id = $1`, v.ID).Scan(&v.UnitCount); err != nil {
+		return err
+	}
+	v.InStock = v.UnitCount > 0
+	return nil
+}
+
+func (r *variantRepo) Delete(ctx context.Context, id string) error {
+	v, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return r.inTx(ctx, v.ProductID, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `DELETE FROM product_variants WHERE id = $1`, id)
+		return err
+	})
+}
+
+// inTx выполняет изменение вариантов и пересчёт остатков товара в одной транзакции
+func (r *variantRepo) inTx(ctx context.Context, productID string, fn func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := syncProductStock(ctx, tx, productID); err != nil {
+		return err
+	}
+	if err := syncSetsContaining(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func syncProductStock(ctx context.Context, db execer, productID string) error {
+	_, err := db.Exec(ctx, `
+		UPDATE products p SET
+			unit_count = v.total,
+			in_stock = v.total > 0
+		FROM (
+			SELECT sum(unit_count)::int AS total
+			FROM product_variants
+			WHERE product_id = $1
+			HAVING count(*) > 0
+		) v
+		WHERE p.id = $1`, productID)
+	return err
+}
+
+// fetchVariants загружает варианты для набора товаров одним запросом
+func fetchVariants(ctx context.Context, db dbtx, productIDs []string) (map[string][]models.ProductVariant, error) {
+	rows, err := db.Query(ctx, `
+		SELECT `+variantColumns+`
+		FROM product_variants
+		WHERE product_id = ANY($1)
+		ORDER BY sort_order, created_at, id`, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byProduct := make(map[string][]models.ProductVariant)
+	for rows.Next() {
+		var v models.ProductVariant
+		if err := scanVariant(rows, &v); err != nil {
+			return nil, err
+		}
+		byProduct[v.ProductID] = append(byProduct[v.ProductID], v)
+	}
+	return byProduct, rows.Err()
+}
+
+func scanVariant(row pgx.Row, v *models.ProductVariant) error {
+	var options []byte
+	if err := row.Scan(
+		&v.ID, &v.ProductID, &v.SKU, &options, &v.Price, &v.OldPrice,
+		&v.UnitCount, &v.SortOrder, &v.CreatedAt, &v.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	_ = json.Unmarshal(options, &v.Options)
+	v.InStock = v.UnitCount > 0
+	return nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, w *models.Warehouse) error
+	GetByID(ctx context.Context, id string) (*models.Warehouse, error)
+	// GetAll возвращает склады в порядке резервирования; activeOnly — только работающие
+	GetAll(ctx context.Context, activeOnly bool) ([]*models.Warehouse, error)
+	// Update сохраняет склад; если склад включили или выключили, остатки его товаров пересчитываются
+	Update(ctx context.Context, w *models.Warehouse) error
+	// Delete удаляет склад. Склад, по которому есть движения остатков или заказы, удалить нельзя
+	// (ErrReferenced) — его можно выключить.
+	Delete(ctx context.Context, id string) error
+}
+
+type warehouseRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWarehouseRepo(db *pgxpool.Pool) WarehouseRepository {
+	return &warehouseRepo{db: db}
+}
+
+// warehouseColumns — колонки склада в запросах с псевдонимом w
+const warehouseColumns = `w.id, w.code, w.name, w.kind, w.city, w.address, w.pickup, w.pickup_days,
+	w.delivery_days, w.intercity_delivery_days, w.priority, w.active, w.created_at, w.updated_at`
+
+// warehouseOrder — порядок резервирования: по приоритету, при равенстве — по коду
+const warehouseOrder = `w.priority, w.code`
+
+func (r *warehouseRepo) Create(ctx context.Context, w *models.Warehouse) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO warehouses (
+			id, code, name, kind, city, address, pickup, pickup_days,
+			delivery_days, intercity_delivery_days, priority, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		w.ID, w.Code, w.Name, w.Kind, w.City, w.Address, w.Pickup, w.PickupDays,
+		w.DeliveryDays, w.IntercityDeliveryDays, w.Priority, w.Active, w.CreatedAt, w.UpdatedAt,
+	)
+	return mapUniqueViolation(err)
+}
+
+func (r *warehouseRepo) GetByID(ctx context.Context, id string) (*models.Warehouse, error) {
+	var w models.Warehouse
+	err := scanWarehouse(r.db.QueryRow(ctx, `SELECT `+warehouseColumns+` FROM warehouses w WHERE w.id = $1`, id), &w)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *warehouseRepo) GetAll(ctx context.Context, activeOnly bool) ([]*models.Warehouse, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+warehouseColumns+` FROM warehouses w
+		WHERE w.active OR NOT $1
+		ORDER BY `+warehouseOrder, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	warehouses := []*models.Warehouse{}
+	for rows.Next() {
+		var w models.Warehouse
+		if err := scanWarehouse(rows, &w); err != nil {
+			return nil, err
+		}
+		warehouses = append(warehouses, &w)
+	}
+	return warehouses, rows.Err()
+}
+
+func (r *warehouseRepo) Update(ctx context.Context, w *models.Warehouse) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var wasActive bool
+	if err := tx.QueryRow(ctx, `SELECT active FROM warehouses WHERE id = $1 FOR UPDATE`, w.ID).Scan(&wasActive); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE warehouses SET
+			code = $2,
+			name = $3,
+			kind = $4,
+			city = $5,
+			address = $6,
+			pickup = $7,
+			pickup_days = $8,
+			delivery_days = $9,
+			intercity_delivery_days = $10,
+			priority = $11,
+			active = $12,
+			updated_at = $13
+		WHERE id = $1`,
+		w.ID, w.Code, w.Name, w.Kind, w.City, w.Address, w.Pickup, w.PickupDays,
+		w.DeliveryDays, w.IntercityDeliveryDays, w.Priority, w.Active, w.UpdatedAt,
+	)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+
+	if w.Active != wasActive {
+		if err := projectWarehouseStock(ctx, tx, w.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *warehouseRepo) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// журнал движений ссылается на склад без внешнего ключа, поэтому проверяется отдельно
+	var used bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM stock_movements WHERE warehouse_id = $1)`, id).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrReferenced
+	}
+
+	// строки нулевых остатков, заведённые без движений, удаляются вместе со складом
+	if _, err := tx.Exec(ctx, `DELETE FROM stock_levels WHERE warehouse_id = $1`, id); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM warehouses WHERE id = $1`, id)
+	if err != nil {
+		return mapForeignKeyViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return tx.Commit(ctx)
+}
+
+// scanWarehouse читает колонки warehouseColumns, перед которыми в запросе могут идти колонки prefix
+func scanWarehouse(row pgx.Row, w *models.Warehouse, prefix ...any) error {
+	return row.Scan(append(prefix,
+		&w.ID, &w.Code, &w.Name, &w.Kind, &w.City, &w.Address, &w.Pickup, &w.PickupDays,
+		&w.DeliveryDays, &w.IntercityDeliveryDays, &w.Priority, &w.Active, &w.CreatedAt, &w.UpdatedAt,
+	)...)
+}
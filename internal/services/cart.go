@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// CartOwner определяет корзину покупателя: корзину пользователя по UserID
+// или, для гостя, корзину по CartID из подписанной cookie
+type CartOwner struct {
+	UserID string
+	CartID string
+}
+
+// CartService — корзина покупателя. Корзина хранит только товары и количества;
+// цены, суммы и наличие пересчитываются по витрине (с акциями) при каждом чтении.
+type CartService interface {
+	Get(ctx context.Context, owner CartOwner) (*models.Cart, error)
+	// AddItem кладёт товар в корзину, создавая её при необходимости; у гостя ID новой корзины — в результате
+	AddItem(ctx context.Context, owner CartOwner, input dto.CartItemRequest) (*models.Cart, error)
+	UpdateItem(ctx context.Context, owner CartOwner, itemID string, quantity int) (*models.Cart, error)
+	RemoveItem(ctx context.Context, owner CartOwner, itemID string) (*models.Cart, error)
+	Clear(ctx context.Context, owner CartOwner) error
+	// Merge переносит гостевую корзину в корзину пользователя после входа
+	Merge(ctx context.Context, guestCartID, userID string) error
+}
+
+var (
+	ErrCartItemNotFound       = errors.New("cart item not found")
+	ErrCartProductUnavailable = errors.New("product is not available for purchase")
+	ErrCartOutOfStock         = errors.New("product is out of stock")
+	ErrCartVariantRequired    = errors.New("product has variants: variantId is required")
+)
+
+type cartService struct {
+	repo       repository.CartRepository
+	products   repository.ProductRepository
+	promotions PromotionService
+}
+
+func NewCartService(r repository.CartRepository, products repository.ProductRepository, promo PromotionService) CartService {
+	return &cartService{repo: r, products: products, promotions: promo}
+}
+
+func (s *cartService) Get(ctx context.Context, owner CartOwner) (*models.Cart, error) {
+	cart, err := s.load(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	return cart, s.price(ctx, cart)
+}
+
+func (s *cartService) AddItem(ctx context.Context, owner CartOwner, input dto.CartItemRequest) (*models.Cart, error) {
+	if err := s.checkAddable(input); err != nil {
+		return nil, err
+	}
+
+	cartID, err := s.ensure(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.AddItem(ctx, cartID, input.ProductID, input.VariantID, input.Quantity)
+	if errors.Is(err, repository.ErrReferenced) {
+		// товар или вариант удалили между проверкой и записью
+		return nil, ErrCartProductUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if owner.UserID == "" {
+		owner.CartID = cartID
+	}
+	return s.Get(ctx, owner)
+}
+
+func (s *cartService) UpdateItem(ctx context.Context, owner CartOwner, itemID string, quantity int) (*models.Cart, error) {
+	cart, err := s.loadForItem(ctx, owner, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetQuantity(ctx, cart.ID, itemID, quantity); err != nil {
+		return nil, cartItemError(err)
+	}
+	return s.Get(ctx, owner)
+}
+
+func (s *cartService) RemoveItem(ctx context.Context, owner CartOwner, itemID string) (*models.Cart, error) {
+	cart, err := s.loadForItem(ctx, owner, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveItem(ctx, cart.ID, itemID); err != nil {
+		return nil, cartItemError(err)
+	}
+	return s.Get(ctx, owner)
+}
+
+func (s *cartService) Clear(ctx context.Context, owner CartOwner) error {
+	cart, err := s.load(ctx, owner)
+	if err != nil || cart.ID == "" {
+		return err
+	}
+	return s.repo.Clear(ctx, cart.ID)
+}
+
+func (s *cartService) Merge(ctx context.Context, guestCartID, userID string) error {
+	err := s.repo.Merge(ctx, guestCartID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// гостевая корзина уже перенесена или удалена как устаревшая
+		return nil
+	}
+	return err
+}
+
+// load читает корзину владельца; если её ещё нет, возвращает пустую корзину без ID
+func (s *cartService) load(ctx context.Context, owner CartOwner) (*models.Cart, error) {
+	var cart *models.Cart
+	var err error
+	switch {
+	case owner.UserID != "":
+		cart, err = s.repo.GetByUser(ctx, owner.UserID)
+	case owner.CartID != "":
+		cart, err = s.repo.GetGuest(ctx, owner.CartID)
+	default:
+		err = pgx.ErrNoRows
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.Cart{Items: []models.CartItem{}}, nil
+	}
+	return cart, err
+}
+
+// loadForItem читает корзину для изменения позиции itemID; если корзины нет
+// или ID позиции не UUID, возвращает ErrCartItemNotFound
+func (s *cartService) loadForItem(ctx context.Context, owner CartOwner, itemID string) (*models.Cart, error) {
+	if uuid.Validate(itemID) != nil {
+		return nil, ErrCartItemNotFound
+	}
+	cart, err := s.load(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart.ID == "" {
+		return nil, ErrCartItemNotFound
+	}
+	return cart, nil
+}
+
+// ensure возвращает ID корзины владельца, создавая её при первом добавлении товара
+func (s *cartService) ensure(ctx context.Context, owner CartOwner) (string, error) {
+	if owner.UserID != "" {
+		return s.repo.EnsureUserCart(ctx, owner.UserID)
+	}
+	if owner.CartID != "" {
+		_, err := s.repo.GetGuest(ctx, owner.CartID)
+		if err == nil {
+			return owner.CartID, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+	}
+	return s.repo.CreateGuest(ctx)
+}
+
+// checkAddable проверяет, что товар можно купить: он на витрине, в наличии,
+// а вариант (обязательный, если у товара есть варианты) принадлежит товару
+func (s *cartService) checkAddable(input dto.CartItemRequest) error {
+	products, err := s.products.GetByIDs([]string{input.ProductID})
+	if err != nil {
+		return err
+	}
+	if len(products) == 0 || !products[0].Visible(time.Now()) {
+		return ErrCartProductUnavailable
+	}
+	p := products[0]
+
+	if input.VariantID == nil {
+		if len(p.Variants) > 0 {
+			return ErrCartVariantRequired
+		}
+		if !p.InStock {
+			return ErrCartOutOfStock
+		}
+		return nil
+	}
+
+	v := findVariant(p, *input.VariantID)
+	if v == nil {
+		return ErrVariantNotFound
+	}
+	if !v.InStock {
+		return ErrCartOutOfStock
+	}
+	return nil
+}
+
+// price подставляет в позиции данные товаров по текущим ценам витрины и считает суммы.
+// Позиции, которые нельзя заказать, попадают в предупреждения и в суммы не входят.
+func (s *cartService) price(ctx context.Context, cart *models.Cart) error {
+	if len(cart.Items) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		ids = append(ids, item.ProductID)
+	}
+	products, err := s.products.GetByIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	byID := make(map[string]*models.Product, len(products))
+	// цены без акций — для суммы скидки
+	basePrices := make(map[string]int)
+	for _, p := range products {
+		if !p.Visible(now) {
+			continue
+		}
+		byID[p.ID] = p
+		basePrices[p.ID] = p.Price
+		for _, v := range p.Variants {
+			if v.Price != nil {
+				basePrices[v.ID] = *v.Price
+			}
+		}
+	}
+	if err := s.promotions.Apply(ctx, products...); err != nil {
+		return err
+	}
+
+	for i := range cart.Items {
+		item := &cart.Items[i]
+		warning := fillCartItem(item, byID[item.ProductID])
+		if warning != nil {
+			cart.Warnings = append(cart.Warnings, *warning)
+			continue
+		}
+
+		base := basePrices[item.ProductID]
+		if item.VariantID != nil {
+			if vb, found := basePrices[*item.VariantID]; found {
+				base = vb
+			}
+		}
+		cart.Quantity += item.Quantity
+		cart.Subtotal += base * item.Quantity
+		cart.Total += item.LineTotal
+	}
+	cart.Discount = cart.Subtotal - cart.Total
+	return nil
+}
+
+// fillCartItem заполняет позицию данными товара p (nil — товар недоступен)
+// и возвращает предупреждение, если позицию нельзя заказать
+func fillCartItem(item *models.CartItem, p *models.Product) *models.CartWarning {
+	warn := func(code models.CartWarningCode, available *int, msg string) *models.CartWarning {
+		item.Available = false
+		return &models.CartWarning{ItemID: item.ID, ProductID: item.ProductID, Code: code, Available: available, Message: msg}
+	}
+	if p == nil {
+		return warn(models.CartItemUnavailable, nil, "product is no longer available")
+	}
+
+	item.Type = p.Type
+	item.Slug = p.Slug
+	item.Title = p.Title
+	item.Price = p.Price
+	item.OldPrice = p.OldPrice
+	if len(p.Images) > 0 {
+		item.Image = p.Images[0].URL
+	}
+
+	inStock, stock := p.InStock, p.UnitCount
+	if item.VariantID != nil {
+		v := findVariant(p, *item.VariantID)
+		if v == nil {
+			return warn(models.CartItemUnavailable, nil, "product variant is no longer available")
+		}
+		item.SKU = v.SKU
+		item.Options = v.Options
+		if v.Price != nil {
+			item.Price = *v.Price
+			item.OldPrice = v.OldPrice
+		}
+		if len(v.Images) > 0 {
+			item.Image = v.Images[0].URL
+		}
+		inStock, stock = v.InStock, nil
+		if v.UnitCount > 0 {
+			stock = &v.UnitCount
+		}
+	}
+	item.LineTotal = item.Price * item.Quantity
+
+	switch {
+	case !inStock || stock != nil && *stock == 0:
+		return warn(models.CartItemOutOfStock, nil, "product is out of stock")
+	case stock != nil && *stock < item.Quantity:
+		return warn(models.CartItemInsufficient, stock, fmt.Sprintf("only %d in stock", *stock))
+	}
+	item.Available = true
+	return nil
+}
+
+func findVariant(p *models.Product, id string) *models.ProductVariant {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i]
+		}
+	}
+	return nil
+}
+
+func cartItemError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCartItemNotFound
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/metrics"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GuestCartCleaner периодически удаляет гостевые корзины покупателей, не менявшиеся дольше ttl
+// (срок жизни их cookie истёк, и покупатель к ним уже не вернётся)
+type GuestCartCleaner struct {
+	repo     repository.CartRepository
+	logger   logger.Logger
+	ttl      time.Duration
+	interval time.Duration
+}
+
+func NewGuestCartCleaner(r repository.CartRepository, l logger.Logger, ttl, interval time.Duration) *GuestCartCleaner {
+	return &GuestCartCleaner{repo: r, logger: l, ttl: ttl, interval: interval}
+}
+
+// Run выполняет очистку сразу и затем каждые interval, пока не отменён ctx
+func (c *GuestCartCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := c.repo.DeleteStaleGuests(ctx, time.Now().UTC().Add(-c.ttl))
+		switch {
+		case err != nil && ctx.Err() == nil:
+			c.logger.Error("failed to delete stale guest carts", zap.Error(err))
+		case n > 0:
+			metrics.GuestCartsPurged.Add(float64(n))
+			c.logger.Info("stale guest carts deleted", zap.Int64("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
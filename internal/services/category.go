@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+var (
+	ErrCategoryNotFound       = errors.New("category not found")
+	ErrCategoryParent         = errors.New("category cannot be moved under itself or its descendant")
+	ErrCategoryHasChildren    = errors.New("category has subcategories")
+	ErrCategoryHasProducts    = errors.New("category has products")
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+)
+
+type CategoryService interface {
+	Create(ctx context.Context, c *models.Category) error
+	GetBySlug(ctx context.Context, slug string) (*models.Category, error)
+	GetAll(ctx context.Context) ([]*models.Category, error)
+	GetTree(ctx context.Context) ([]*models.Category, error)
+	Update(ctx context.Context, id string, c *models.Category) error
+	Delete(ctx context.Context, id string) error
+}
+
+type categoryService struct {
+	repo repository.CategoryRepository
+}
+
+func NewCategoryService(r repository.CategoryRepository) CategoryService {
+	return &categoryService{repo: r}
+}
+
+func (s *categoryService) Create(ctx context.Context, c *models.Category) error {
+	if err := s.checkParent(ctx, "", c.ParentID); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	c.ID = uuid.NewString()
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	return s.repo.Create(ctx, c)
+}
+
+func (s *categoryService) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	c, err := s.repo.GetBySlug(ctx, slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCategoryNotFound
+	}
+	return c, err
+}
+
+func (s *categoryService) GetAll(ctx context.Context) ([]*models.Category, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// GetTree возвращает корневые категории с вложенными подкатегориями
+func (s *categoryService) GetTree(ctx context.Context) ([]*models.Category, error) {
+	all, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*models.Category, len(all))
+	for _, c := range all {
+		byID[c.ID] = c
+	}
+
+	// порядок all (sort_order, name) сохраняется и внутри каждого уровня
+	var roots []*models.Category
+	for _, c := range all {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+
+	return roots, nil
+}
+
+func (s *categoryService) Update(ctx context.Context, id string, c *models.Category) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkParent(ctx, id, c.ParentID); err != nil {
+		return err
+	}
+
+	c.ID = existing.ID
+	c.CreatedAt = existing.CreatedAt
+	c.UpdatedAt = time.Now().UTC()
+
+	return s.repo.Update(ctx, c)
+}
+
+func (s *categoryService) Delete(ctx context.Context, id string) error {
+	all, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, c := range all {
+		if c.ID == id {
+			found = true
+		}
+		if c.ParentID != nil && *c.ParentID == id {
+			return ErrCategoryHasChildren
+		}
+	}
+	if !found {
+		return ErrCategoryNotFound
+	}
+
+	n, err := s.repo.CountProducts(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrCategoryHasProducts
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// checkParent проверяет, что родитель существует и не приведёт к циклу в дереве
+func (s *categoryService) checkParent(ctx context.Context, id string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+
+	all, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	parents := make(map[string]*string, len(all))
+	for _, c := range all {
+		parents[c.ID] = c.ParentID
+	}
+
+	if _, ok := parents[*parentID]; !ok {
+		return ErrParentCategoryNotFound
+	}
+
+	// поднимаемся от нового родителя к корню; если встретили саму категорию — это цикл
+	for cur := parentID; cur != nil; cur = parents[*cur] {
+		if id != "" && *cur == id {
+			return ErrCategoryParent
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/metrics"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/config"
+	"dozenChairs/pkg/logger"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FeedService генерирует фиды каталога для маркетплейсов и хранит их в файлах,
+// чтобы отдавать без обращения к базе
+type FeedService interface {
+	// Open возвращает файл фида, предварительно перегенерировав его, если он отсутствует или устарел.
+	// Файл закрывает вызывающий.
+	Open(ctx context.Context, name string) (*os.File, error)
+	Regenerate(ctx context.Context, name string) (*models.FeedInfo, error)
+}
+
+var ErrFeedNotFound = errors.New("feed not found")
+
+// feedGenerator пишет фид в w и возвращает число выгруженных товаров и пропущенные товары
+type feedGenerator func(ctx context.Context, w io.Writer) (*feedResult, error)
+
+type feedResult struct {
+	Offers  int
+	Skipped []models.FeedSkippedItem
+}
+
+// feedPageSize — сколько товаров читается из базы за раз при генерации фида
+const feedPageSize = 500
+
+type feedService struct {
+	logger     logger.Logger
+	dir        string
+	ttl        time.Duration
+	generators map[string]feedGenerator
+
+	mu sync.Mutex // одновременно генерируется не больше одного фида
+}
+
+func NewFeedService(
+	products repository.ProductRepository,
+	catalog ProductService,
+	categories repository.CategoryRepository,
+	shop config.ShopConfig,
+	dir string,
+	ttl time.Duration,
+	l logger.Logger,
+) FeedService {
+	return &feedService{
+		logger: l,
+		dir:    dir,
+		ttl:    ttl,
+		generators: map[string]feedGenerator{
+			YandexFeedName: newYandexFeed(products, categories, shop).write,
+			GoogleFeedName: newGoogleFeed(catalog, categories, shop).write,
+		},
+	}
+}
+
+func (s *feedService) Open(ctx context.Context, name string) (*os.File, error) {
+	if _, ok := s.generators[name]; !ok {
+		return nil, ErrFeedNotFound
+	}
+
+	if s.stale(name) {
+		s.mu.Lock()
+		// пока ждали блокировку, фид мог перегенерировать другой запрос
+		if s.stale(name) {
+			if _, err := s.generate(ctx, name); err != nil {
+				s.mu.Unlock()
+				return nil, err
+			}
+		}
+		s.mu.Unlock()
+	}
+	return os.Open(s.path(name))
+}
+
+func (s *feedService) Regenerate(ctx context.Context, name string) (*models.FeedInfo, error) {
+	if _, ok := s.generators[name]; !ok {
+		return nil, ErrFeedNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generate(ctx, name)
+}
+
+func (s *feedService) stale(name string) bool {
+	info, err := os.Stat(s.path(name))
+	return err != nil || time.Since(info.ModTime()) > s.ttl
+}
+
+func (s *feedService) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+// generate пишет фид во временный файл и подменяет им предыдущую версию,
+// так что читатели никогда не видят недописанный фид
+func (s *feedService) generate(ctx context.Context, name string) (*models.FeedInfo, error) {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	result, err := s.generators[name](ctx, tmp)
+	if err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), s.path(name)); err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(s.path(name))
+	if err != nil {
+		return nil, err
+	}
+	metrics.FeedsGenerated.WithLabelValues(name).Inc()
+	if len(result.Skipped) > 0 {
+		s.logger.Warn("feed generated with skipped products", zap.String("feed", name), zap.Int("skipped", len(result.Skipped)))
+	}
+	return &models.FeedInfo{
+		Name:        name,
+		GeneratedAt: stat.ModTime().UTC(),
+		Offers:      result.Offers,
+		Size:        stat.Size(),
+		Skipped:     result.Skipped,
+	}, nil
+}
+
+// eachProduct обходит витринные товары страницами по feedPageSize, не загружая каталог в память целиком
+func eachProduct(ctx context.Context, getAll func(repository.ProductFilter) ([]*models.Product, error), fn func(p *models.Product) error) error {
+	filter := repository.ProductFilter{Sort: "createdAt", Limit: feedPageSize}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		items, err := getAll(filter)
+		if err != nil {
+			return err
+		}
+		for _, p := range items {
+			if err := fn(p); err != nil {
+				return err
+			}
+		}
+		if len(items) < feedPageSize {
+			return nil
+		}
+		filter.Cursor = repository.CursorAfter(filter, items[len(items)-1])
+	}
+}
+
+// absoluteURL дополняет путь вида /uploads/x.jpg адресом base; полные адреса не меняются
+func absoluteURL(base, path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return base + "/" + strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/config"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GoogleFeedName — имя фида Google Merchant Center (RSS 2.0 с пространством имён g:)
+const GoogleFeedName = "google.xml"
+
+const (
+	googleNamespace = "http://base.google.com/ns/1.0"
+	// googleMaxAdditionalImages — сколько дополнительных изображений принимает Merchant Center
+	googleMaxAdditionalImages = 10
+	googleMaxTitle            = 150
+	googleMaxDescription      = 5000
+)
+
+// googleItem — товар фида (https://support.google.com/merchants/answer/7052112).
+// encoding/xml не умеет префиксы пространств имён, поэтому g: записан прямо в имени элемента.
+type googleItem struct {
+	XMLName          xml.Name `xml:"item"`
+	ID               string   `xml:"g:id"`
+	Title            string   `xml:"g:title"`
+	Description      string   `xml:"g:description"`
+	Link             string   `xml:"g:link"`
+	ImageLink        string   `xml:"g:image_link"`
+	AdditionalImages []string `xml:"g:additional_image_link"`
+	Availability     string   `xml:"g:availability"`
+	Price            string   `xml:"g:price"`
+	SalePrice        string   `xml:"g:sale_price,omitempty"`
+	Condition        string   `xml:"g:condition"`
+	Brand            string   `xml:"g:brand"`
+	ProductType      string   `xml:"g:product_type,omitempty"`
+	IsBundle         string   `xml:"g:is_bundle,omitempty"`
+	IdentifierExists string   `xml:"g:identifier_exists"`
+}
+
+// googleFeed строит фид для Google Shopping по витринным товарам ProductService
+type googleFeed struct {
+	catalog    ProductService
+	categories repository.CategoryRepository
+	shop       config.ShopConfig
+}
+
+func newGoogleFeed(catalog ProductService, categories repository.CategoryRepository, shop config.ShopConfig) *googleFeed {
+	return &googleFeed{catalog: catalog, categories: categories, shop: shop}
+}
+
+// write пишет фид потоково; товары без обязательных для Merchant Center полей
+// пропускаются и возвращаются в отчёте
+func (f *googleFeed) write(ctx context.Context, w io.Writer) (*feedResult, error) {
+	categories, err := f.categories.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	paths := categoryPaths(categories)
+
+	bw := bufio.NewWriter(w)
+	if _, err := io.WriteString(bw, xml.Header); err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(bw)
+	enc.Indent("", "  ")
+
+	rss := xml.StartElement{
+		Name: xml.Name{Local: "rss"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "version"}, Value: "2.0"},
+			{Name: xml.Name{Local: "xmlns:g"}, Value: googleNamespace},
+		},
+	}
+	channel := xml.StartElement{Name: xml.Name{Local: "channel"}}
+	if err := encodeTokens(enc, rss, channel); err != nil {
+		return nil, err
+	}
+	for _, el := range [][2]string{
+		{"title", f.shop.Name},
+		{"link", f.shop.BaseURL},
+		{"description", f.shop.Company},
+	} {
+		if err := enc.EncodeElement(el[1], xml.StartElement{Name: xml.Name{Local: el[0]}}); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &feedResult{}
+	err = eachProduct(ctx, f.catalog.GetAll, func(p *models.Product) error {
+		item := f.item(p, paths)
+		if missing := item.missing(); len(missing) > 0 {
+			result.Skipped = append(result.Skipped, models.FeedSkippedItem{ID: p.ID, Slug: p.Slug, Missing: missing})
+			return nil
+		}
+		result.Offers++
+		return enc.Encode(item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encodeTokens(enc, channel.End(), rss.End()); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return result, bw.Flush()
+}
+
+func (f *googleFeed) item(p *models.Product, categoryPaths map[string]string) googleItem {
+	item := googleItem{
+		ID:          p.ID,
+		Title:       truncateRunes(p.Title, googleMaxTitle),
+		Description: truncateRunes(p.Description, googleMaxDescription),
+		Link:        productURL(f.shop.BaseURL, p),
+		Condition:   "new",
+		Brand:       f.brand(p),
+		ProductType: categoryPaths[p.CategoryID],
+		// собственная мебель магазина не имеет GTIN/MPN
+		IdentifierExists: "no",
+	}
+
+	if p.InStock {
+		item.Availability = "in_stock"
+	} else {
+		item.Availability = "out_of_stock"
+	}
+
+	if p.Price > 0 {
+		item.Price = f.price(p.Price)
+		if p.OldPrice != nil && *p.OldPrice > p.Price {
+			item.Price = f.price(*p.OldPrice)
+			item.SalePrice = f.price(p.Price)
+		}
+	}
+
+	for i, img := range p.Images {
+		url := absoluteURL(f.shop.MediaURL, img.URL)
+		if i == 0 {
+			item.ImageLink = url
+			continue
+		}
+		if len(item.AdditionalImages) == googleMaxAdditionalImages {
+			break
+		}
+		item.AdditionalImages = append(item.AdditionalImages, url)
+	}
+
+	// набор продаётся как комплект из нескольких разных товаров по одной цене
+	if p.Type == models.TypeSet {
+		item.IsBundle = "yes"
+	}
+	return item
+}
+
+// brand — бренд из атрибута brand товара, иначе бренд магазина
+func (f *googleFeed) brand(p *models.Product) string {
+	if b := attributeString(p.Attributes["brand"]); b != "" {
+		return b
+	}
+	return f.shop.Brand
+}
+
+// price форматирует цену в рублях в виде "1990.00 RUB"
+func (f *googleFeed) price(amount int) string {
+	return fmt.Sprintf("%d.00 %s", amount, f.shop.Currency)
+}
+
+// missing возвращает обязательные для Merchant Center поля, которые не заполнены
+func (i googleItem) missing() []string {
+	var missing []string
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"title", i.Title},
+		{"description", i.Description},
+		{"link", i.Link},
+		{"image_link", i.ImageLink},
+		{"price", i.Price},
+		{"brand", i.Brand},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
+}
+
+// categoryPaths строит для каждой категории путь от корня вида "Мебель > Стулья"
+func categoryPaths(categories []*models.Category) map[string]string {
+	byID := make(map[string]*models.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	paths := make(map[string]string, len(categories))
+	for _, c := range categories {
+		var names []string
+		seen := make(map[string]bool)
+		for cur := c; cur != nil && !seen[cur.ID]; {
+			seen[cur.ID] = true
+			names = append([]string{cur.Name}, names...)
+			if cur.ParentID == nil {
+				break
+			}
+			cur = byID[*cur.ParentID]
+		}
+		paths[c.ID] = strings.Join(names, " > ")
+	}
+	return paths
+}
+
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
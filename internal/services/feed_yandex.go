@@ -0,0 +1,234 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/config"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// YandexFeedName — имя фида Яндекс Маркета в формате YML
+const YandexFeedName = "yandex.yml"
+
+// ymlMaxPictures — сколько изображений товара принимает Маркет
+const ymlMaxPictures = 10
+
+type ymlCategory struct {
+	ID       string `xml:"id,attr"`
+	ParentID string `xml:"parentId,attr,omitempty"`
+	Name     string `xml:",chardata"`
+}
+
+type ymlCurrency struct {
+	ID   string `xml:"id,attr"`
+	Rate string `xml:"rate,attr"`
+}
+
+type ymlOffer struct {
+	XMLName     xml.Name   `xml:"offer"`
+	ID          string     `xml:"id,attr"`
+	Available   bool       `xml:"available,attr"`
+	Name        string     `xml:"name"`
+	URL         string     `xml:"url"`
+	Price       int        `xml:"price"`
+	OldPrice    int        `xml:"oldprice,omitempty"`
+	CurrencyID  string     `xml:"currencyId"`
+	CategoryID  string     `xml:"categoryId,omitempty"`
+	Pictures    []string   `xml:"picture"`
+	Description string     `xml:"description,omitempty"`
+	Count       *int       `xml:"count"`
+	Params      []ymlParam `xml:"param"`
+}
+
+type ymlParam struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// yandexFeed строит YML-фид (https://yandex.ru/support/marketplace/assortment/files/yml.html)
+type yandexFeed struct {
+	products   repository.ProductRepository
+	categories repository.CategoryRepository
+	shop       config.ShopConfig
+}
+
+func newYandexFeed(products repository.ProductRepository, categories repository.CategoryRepository, shop config.ShopConfig) *yandexFeed {
+	return &yandexFeed{products: products, categories: categories, shop: shop}
+}
+
+// write пишет каталог потоково: заголовок и категории, затем товары по одному
+func (f *yandexFeed) write(ctx context.Context, w io.Writer) (*feedResult, error) {
+	categories, err := f.categories.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := io.WriteString(bw, xml.Header); err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(bw)
+	enc.Indent("", "  ")
+
+	catalog := xml.StartElement{
+		Name: xml.Name{Local: "yml_catalog"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "date"}, Value: time.Now().Format(time.RFC3339)}},
+	}
+	shop := xml.StartElement{Name: xml.Name{Local: "shop"}}
+	if err := encodeTokens(enc, catalog, shop); err != nil {
+		return nil, err
+	}
+
+	for _, el := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"name", f.shop.Name},
+		{"company", f.shop.Company},
+		{"url", f.shop.BaseURL},
+		{"currencies", struct {
+			Items []ymlCurrency `xml:"currency"`
+		}{[]ymlCurrency{{ID: f.shop.Currency, Rate: "1"}}}},
+		{"categories", struct {
+			Items []ymlCategory `xml:"category"`
+		}{ymlCategories(categories)}},
+	} {
+		if err := enc.EncodeElement(el.value, xml.StartElement{Name: xml.Name{Local: el.name}}); err != nil {
+			return nil, err
+		}
+	}
+
+	offers := xml.StartElement{Name: xml.Name{Local: "offers"}}
+	if err := encodeTokens(enc, offers); err != nil {
+		return nil, err
+	}
+
+	count := 0
+	err = eachProduct(ctx, f.products.GetAll, func(p *models.Product) error {
+		count++
+		return enc.Encode(f.offer(p))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encodeTokens(enc, offers.End(), shop.End(), catalog.End()); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return &feedResult{Offers: count}, bw.Flush()
+}
+
+func (f *yandexFeed) offer(p *models.Product) ymlOffer {
+	o := ymlOffer{
+		ID:          p.ID,
+		Available:   p.InStock,
+		Name:        p.Title,
+		URL:         productURL(f.shop.BaseURL, p),
+		Price:       p.Price,
+		CurrencyID:  f.shop.Currency,
+		CategoryID:  ymlCategoryID(p.CategoryID),
+		Description: p.Description,
+		Count:       p.UnitCount,
+		Params:      ymlParams(p.Attributes),
+	}
+	if p.OldPrice != nil && *p.OldPrice > p.Price {
+		o.OldPrice = *p.OldPrice
+	}
+	for _, img := range p.Images {
+		if len(o.Pictures) == ymlMaxPictures {
+			break
+		}
+		o.Pictures = append(o.Pictures, absoluteURL(f.shop.MediaURL, img.URL))
+	}
+	return o
+}
+
+// ymlCategories переводит дерево категорий в плоский список с parentId
+func ymlCategories(categories []*models.Category) []ymlCategory {
+	result := make([]ymlCategory, 0, len(categories))
+	for _, c := range categories {
+		yc := ymlCategory{ID: ymlCategoryID(c.ID), Name: c.Name}
+		if c.ParentID != nil {
+			yc.ParentID = ymlCategoryID(*c.ParentID)
+		}
+		result = append(result, yc)
+	}
+	return result
+}
+
+// ymlCategoryID превращает UUID категории в число: Маркет принимает только
+// целочисленные id категорий длиной не больше 18 цифр. Используется хэш FNV-1a
+// от UUID, усечённый до 59 бит, так что id стабилен между выгрузками.
+func ymlCategoryID(id string) string {
+	if id == "" {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return strconv.FormatUint(h.Sum64()>>5, 10)
+}
+
+// ymlParams переводит атрибуты товара в элементы <param>, упорядоченные по имени
+func ymlParams(attributes map[string]interface{}) []ymlParam {
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	params := make([]ymlParam, 0, len(keys))
+	for _, k := range keys {
+		if v := attributeString(attributes[k]); v != "" {
+			params = append(params, ymlParam{Name: k, Value: v})
+		}
+	}
+	return params
+}
+
+// attributeString форматирует значение атрибута из JSON: списки перечисляются через запятую
+func attributeString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if s := attributeString(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// productURL — ссылка на страницу товара на витрине
+func productURL(baseURL string, p *models.Product) string {
+	return baseURL + "/products/" + p.Slug
+}
+
+func encodeTokens(enc *xml.Encoder, tokens ...xml.Token) error {
+	for _, t := range tokens {
+		if err := enc.EncodeToken(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,28 +4,69 @@ import (
 	"context"
 	"dozenChairs/internal/models"
 	"dozenChairs/internal/repository"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type ImageService interface {
+	// CheckVariant возвращает ErrVariantNotFound, если варианта нет или он принадлежит другому товару
+	CheckVariant(ctx context.Context, productID, variantID string) error
 	SaveImage(ctx context.Context, img *models.Image) error
 	DeleteImage(ctx context.Context, id string) error
 	GetImagesByProductID(ctx context.Context, productID string) ([]models.Image, error)
 }
 
 type imageService struct {
-	repo repository.ImageRepository
+	repo     repository.ImageRepository
+	variants repository.VariantRepository
+}
+
+func NewImageService(r repository.ImageRepository, v repository.VariantRepository) ImageService {
+	return &imageService{repo: r, variants: v}
 }
 
-func NewImageService(r repository.ImageRepository) ImageService {
-	return &imageService{repo: r}
+func (s *imageService) CheckVariant(ctx context.Context, productID, variantID string) error {
+	v, err := s.variants.GetByID(ctx, variantID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrVariantNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if v.ProductID != productID {
+		return ErrVariantNotFound
+	}
+	return nil
 }
 
+// SaveImage сохраняет изображение и ревизию товара в одной транзакции
 func (s *imageService) SaveImage(ctx context.Context, img *models.Image) error {
-	return s.repo.Save(ctx, img)
+	return s.repo.WithTx(ctx, func(tx repository.ImageRepository) error {
+		if err := tx.Save(ctx, img); err != nil {
+			return err
+		}
+		return tx.Revisions().Create(ctx, newRevision(ctx, img.ProductID, models.RevisionImageAdded, map[string]models.FieldChange{
+			"image": {Old: nil, New: img.URL},
+		}))
+	})
 }
 
+// DeleteImage удаляет запись изображения вместе с записью ревизии; отсутствие записи не считается ошибкой
 func (s *imageService) DeleteImage(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	err := s.repo.WithTx(ctx, func(tx repository.ImageRepository) error {
+		img, err := tx.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+		return tx.Revisions().Create(ctx, newRevision(ctx, img.ProductID, models.RevisionImageDeleted, map[string]models.FieldChange{
+			"image": {Old: img.URL, New: nil},
+		}))
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
 }
 
 func (s *imageService) GetImagesByProductID(ctx context.Context, productID string) ([]models.Image, error) {
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrUnsupportedImportFormat = errors.New("unsupported import file format, expected .csv or .xlsx")
+	ErrEmptyImportFile         = errors.New("import file has no header row")
+)
+
+// ImportRow — строка файла импорта: значения по именам колонок из заголовка
+type ImportRow struct {
+	Line   int // номер строки в файле, считая заголовок с 1
+	Values map[string]string
+}
+
+// ReadImportFile читает CSV или XLSX (формат определяется по расширению name).
+// Первая строка — заголовок с именами колонок, пустые строки пропускаются.
+func ReadImportFile(r io.Reader, name string) ([]ImportRow, error) {
+	var (
+		records [][]string
+		err     error
+	)
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		records, err = readCSV(r)
+	case ".xlsx":
+		records, err = readXLSX(r)
+	default:
+		return nil, ErrUnsupportedImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toImportRows(records)
+}
+
+// readCSV читает CSV с разделителем "," или ";" (Excel с русской локалью сохраняет через ";").
+// Разделитель выбирается по заголовку.
+func readCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if i := bytes.IndexByte(header, '\n'); i >= 0 {
+		header = header[:i]
+	}
+
+	cr := csv.NewReader(br)
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	// BOM, который добавляет Excel при сохранении в UTF-8
+	if len(records) > 0 && len(records[0]) > 0 {
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+	}
+	return records, nil
+}
+
+// readXLSX читает первый лист книги
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrEmptyImportFile
+	}
+	return f.GetRows(sheets[0])
+}
+
+func toImportRows(records [][]string) ([]ImportRow, error) {
+	if len(records) == 0 {
+		return nil, ErrEmptyImportFile
+	}
+
+	header := make([]string, len(records[0]))
+	for i, h := range records[0] {
+		header[i] = strings.TrimSpace(h)
+	}
+
+	var rows []ImportRow
+	for i, rec := range records[1:] {
+		values := make(map[string]string, len(header))
+		empty := true
+		for j, v := range rec {
+			if j >= len(header) || header[j] == "" {
+				continue
+			}
+			v = strings.TrimSpace(v)
+			if v != "" {
+				empty = false
+			}
+			values[header[j]] = v
+		}
+		if empty {
+			continue
+		}
+		rows = append(rows, ImportRow{Line: i + 2, Values: values})
+	}
+	return rows, nil
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// OrderService оформляет заказы из корзины покупателя и ведёт их по статусам.
+// Статус меняется только по допустимым переходам (models.OrderStatus.CanTransitionTo),
+// каждый переход записывается в историю с временем и автором.
+type OrderService interface {
+	// Checkout оформляет заказ из корзины пользователя: фиксирует позиции по текущим ценам витрины,
+	// применяет промокод и очищает корзину
+	Checkout(ctx context.Context, userID string, req dto.CheckoutRequest) (*models.Order, error)
+	// GetForUser возвращает заказ пользователя; чужие заказы не находятся
+	GetForUser(ctx context.Context, userID, id string) (*models.Order, error)
+	ListForUser(ctx context.Context, userID string, limit, offset int) ([]*models.Order, int, error)
+	// Cancel отменяет заказ пользователя, пока он не оплачен
+	Cancel(ctx context.Context, userID, id, comment string) (*models.Order, error)
+	GetByID(ctx context.Context, id string) (*models.Order, error)
+	List(ctx context.Context, filter repository.OrderFilter) ([]*models.Order, int, error)
+	// Transition переводит заказ в статус to; автор перехода берётся из контекста запроса,
+	// без пользователя в контексте переход записывается как системный
+	Transition(ctx context.Context, id string, to models.OrderStatus, comment string) (*models.Order, error)
+	// ExpireReservations снимает резервы, срок которых истёк к now, и отменяет неоплаченные заказы с ними
+	// (их промокоды освобождаются); возвращает число обработанных заказов
+	ExpireReservations(ctx context.Context, now time.Time) (int, error)
+}
+
+var (
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrCartEmpty           = errors.New("cart is empty")
+	ErrCartNotOrderable    = errors.New("cart has items that cannot be ordered")
+	ErrCartChanged         = errors.New("cart has changed, please review it and try again")
+	ErrInvalidOrderStatus  = errors.New("invalid order status")
+	ErrOrderTransition     = errors.New("order status transition is not allowed")
+	ErrOrderStatusConflict = errors.New("order status has been changed concurrently")
+	ErrOrderNotCancellable = errors.New("order can no longer be cancelled")
+	ErrPickupPointNotFound = errors.New("pickup point not found")
+)
+
+type orderService struct {
+	repo       repository.OrderRepository
+	stock      repository.StockRepository
+	warehouses repository.WarehouseRepository
+	carts      CartService
+	promoCodes PromoCodeService
+	// reservationTTL — сколько товары оформленного заказа держатся в резерве до оплаты
+	reservationTTL time.Duration
+}
+
+func NewOrderService(
+	r repository.OrderRepository,
+	stock repository.StockRepository,
+	warehouses repository.WarehouseRepository,
+	carts CartService,
+	promoCodes PromoCodeService,
+	reservationTTL time.Duration,
+) OrderService {
+	return &orderService{
+		repo:           r,
+		stock:          stock,
+		warehouses:     warehouses,
+		carts:          carts,
+		promoCodes:     promoCodes,
+		reservationTTL: reservationTTL,
+	}
+}
+
+func (s *orderService) Checkout(ctx context.Context, userID string, req dto.CheckoutRequest) (*models.Order, error) {
+	cart, err := s.carts.Get(ctx, CartOwner{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, ErrCartEmpty
+	}
+	if len(cart.Warnings) > 0 {
+		messages := make([]string, 0, len(cart.Warnings))
+		for _, w := range cart.Warnings {
+			messages = append(messages, fmt.Sprintf("%s: %s", w.ProductID, w.Message))
+		}
+		return nil, fmt.Errorf("%w: %s", ErrCartNotOrderable, strings.Join(messages, "; "))
+	}
+
+	city, address := strings.TrimSpace(req.City), strings.TrimSpace(req.Address)
+	var pickupPointID *string
+	if req.DeliveryMethod == models.DeliveryPickup {
+		point, err := s.pickupPoint(ctx, req.PickupPointID)
+		if err != nil {
+			return nil, err
+		}
+		pickupPointID, city, address = &point.ID, point.City, point.Address
+	}
+
+	now := time.Now().UTC()
+	reservedUntil := now.Add(s.reservationTTL)
+	order := &models.Order{
+		ID:             uuid.NewString(),
+		UserID:         userID,
+		Status:         models.OrderNew,
+		CustomerName:   strings.TrimSpace(req.CustomerName),
+		Email:          strings.TrimSpace(req.Email),
+		Phone:          strings.TrimSpace(req.Phone),
+		DeliveryMethod: req.DeliveryMethod,
+		City:           city,
+		Address:        address,
+		PickupPointID:  pickupPointID,
+		Comment:        strings.TrimSpace(req.Comment),
+		Subtotal:       cart.Subtotal,
+		Discount:       cart.Discount,
+		Total:          cart.Total,
+		ReservedUntil:  &reservedUntil,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		History: []models.OrderStatusChange{{
+			To:        models.OrderNew,
+			ChangedBy: &userID,
+			ChangedAt: now,
+		}},
+	}
+	for _, item := range cart.Items {
+		order.Items = append(order.Items, models.OrderItem{
+			ID:        uuid.NewString(),
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Type:      item.Type,
+			Slug:      item.Slug,
+			Title:     item.Title,
+			SKU:       item.SKU,
+			Options:   item.Options,
+			Price:     item.Price,
+			OldPrice:  item.OldPrice,
+			Quantity:  item.Quantity,
+			LineTotal: item.LineTotal,
+		})
+	}
+
+	var redemption *models.PromoCodeRedemption
+	if code := strings.TrimSpace(req.PromoCode); code != "" {
+		if redemption, err = s.applyPromoCode(ctx, order, code); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.Create(ctx, order, cart.ID, cart.Items, redemption)
+	switch {
+	case errors.Is(err, repository.ErrCartChanged):
+		return nil, ErrCartChanged
+	case errors.Is(err, repository.ErrInsufficientStock):
+		return nil, fmt.Errorf("%w (%v)", ErrCartOutOfStock, err)
+	case err != nil:
+		return nil, redemptionError(err)
+	}
+	return withNextStatuses(order), nil
+}
+
+// applyPromoCode считает скидку по промокоду для позиций заказа, распределяет её по позициям
+// и возвращает применение промокода, которое записывается вместе с заказом
+func (s *orderService) applyPromoCode(ctx context.Context, order *models.Order, code string) (*models.PromoCodeRedemption, error) {
+	inputs := make([]models.OrderItemInput, 0, len(order.Items))
+	for _, item := range order.Items {
+		inputs = append(inputs, models.OrderItemInput{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+	quote, err := s.promoCodes.Quote(ctx, code, order.UserID, inputs)
+	if err != nil {
+		return nil, err
+	}
+	// промокод считается по ценам витрины, как и корзина; расхождение значит, что цены изменились между расчётами
+	if quote.Subtotal != order.Total {
+		return nil, ErrCartChanged
+	}
+
+	discounts := make(map[string]int, len(quote.Items))
+	for _, qi := range quote.Items {
+		discounts[orderLineKey(qi.ProductID, qi.VariantID)] = qi.Discount
+	}
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.PromoDiscount = discounts[orderLineKey(item.ProductID, item.VariantID)]
+		item.LineTotal -= item.PromoDiscount
+	}
+	order.PromoCode = &quote.Code
+	order.PromoDiscount = quote.Discount
+	order.Total = quote.Total
+
+	return &models.PromoCodeRedemption{
+		ID:          uuid.NewString(),
+		PromoCodeID: quote.PromoCodeID,
+		UserID:      order.UserID,
+		OrderID:     &order.ID,
+		Discount:    quote.Discount,
+		CreatedAt:   order.CreatedAt,
+	}, nil
+}
+
+func (s *orderService) GetForUser(ctx context.Context, userID, id string) (*models.Order, error) {
+	o, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if o.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+	return o, nil
+}
+
+func (s *orderService) ListForUser(ctx context.Context, userID string, limit, offset int) ([]*models.Order, int, error) {
+	return s.List(ctx, repository.OrderFilter{UserID: userID, Limit: limit, Offset: offset})
+}
+
+func (s *orderService) Cancel(ctx context.Context, userID, id, comment string) (*models.Order, error) {
+	o, err := s.GetForUser(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	// оплаченный заказ покупатель не отменяет сам: деньги возвращает магазин (статус refunded)
+	if o.Status != models.OrderNew && o.Status != models.OrderAwaitingPayment {
+		return nil, ErrOrderNotCancellable
+	}
+	return s.transition(ctx, o, models.OrderCancelled, comment)
+}
+
+func (s *orderService) GetByID(ctx context.Context, id string) (*models.Order, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrOrderNotFound
+	}
+	o, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withNextStatuses(o), nil
+}
+
+func (s *orderService) List(ctx context.Context, filter repository.OrderFilter) ([]*models.Order, int, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, 0, fmt.Errorf("%w: %q", ErrInvalidOrderStatus, filter.Status)
+	}
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	orders, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, o := range orders {
+		withNextStatuses(o)
+	}
+	return orders, total, nil
+}
+
+func (s *orderService) Transition(ctx context.Context, id string, to models.OrderStatus, comment string) (*models.Order, error) {
+	if !to.Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOrderStatus, to)
+	}
+	o, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(ctx, o, to, comment)
+}
+
+func (s *orderService) transition(ctx context.Context, o *models.Order, to models.OrderStatus, comment string) (*models.Order, error) {
+	if !o.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrOrderTransition, o.Status, to)
+	}
+
+	from := o.Status
+	err := s.repo.Transition(ctx, o.ID, &models.OrderStatusChange{
+		From:      &from,
+		To:        to,
+		ChangedBy: actorID(ctx),
+		Comment:   strings.TrimSpace(comment),
+		ChangedAt: time.Now().UTC(),
+	}, orderStockEffect(from, to))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, ErrOrderNotFound
+	case errors.Is(err, repository.ErrVersionConflict):
+		return nil, ErrOrderStatusConflict
+	case err != nil:
+		return nil, err
+	}
+	return s.GetByID(ctx, o.ID)
+}
+
+func (s *orderService) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.stock.ExpiredReservationOrders(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, id := range ids {
+		o, err := s.GetByID(ctx, id)
+		if err != nil {
+			return n, err
+		}
+		if o.Status.CanTransitionTo(models.OrderCancelled) {
+			_, err = s.transition(ctx, o, models.OrderCancelled, "reservation expired")
+			if errors.Is(err, ErrOrderStatusConflict) {
+				// заказ оплатили или отменили одновременно с истечением резерва — разберёмся на следующем проходе
+				continue
+			}
+		} else {
+			err = s.stock.ReleaseOrder(ctx, o.ID, nil, "reservation expired")
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// pickupPoint возвращает работающий пункт самовывоза id
+func (s *orderService) pickupPoint(ctx context.Context, id string) (*models.Warehouse, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrPickupPointNotFound
+	}
+	w, err := s.warehouses.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && (!w.Active || !w.Pickup)) {
+		return nil, ErrPickupPointNotFound
+	}
+	return w, err
+}
+
+// orderStockEffect — что переход заказа из from в to делает с резервами его товаров:
+// отмена снимает резерв, оплата списывает зарезервированное как продажу, а возврат денег
+// до отгрузки возвращает товар на склад. Отгруженный товар возвращается на склад вручную, по факту.
+func orderStockEffect(from, to models.OrderStatus) repository.StockEffect {
+	switch {
+	case to == models.OrderCancelled:
+		return repository.StockRelease
+	case to == models.OrderPaid:
+		return repository.StockSell
+	case to == models.OrderRefunded && (from == models.OrderPaid || from == models.OrderAssembling):
+		return repository.StockReturn
+	}
+	return repository.StockKeep
+}
+
+func withNextStatuses(o *models.Order) *models.Order {
+	o.NextStatuses = o.Status.NextStatuses()
+	return o
+}
+
+// orderLineKey — ключ позиции заказа: товар и, если есть, его вариант
+func orderLineKey(productID string, variantID *string) string {
+	if variantID == nil {
+		return productID
+	}
+	return productID + "/" + *variantID
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/metrics"
+	"dozenChairs/internal/middlewares"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/payments"
+	"dozenChairs/internal/repository"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PaymentService принимает оплату заказов через платёжного провайдера.
+// Состояние платежа всегда берётся у провайдера: уведомление только сообщает, что его пора перепроверить,
+// поэтому повторные и пришедшие не по порядку уведомления безопасны.
+type PaymentService interface {
+	// Pay начинает оплату заказа пользователя: заказ переводится в awaiting_payment и у провайдера
+	// создаётся платёж на сумму заказа. Пока прошлый платёж не завершён, возвращается он.
+	Pay(ctx context.Context, userID, orderID string) (*models.Payment, error)
+	// GetForUser возвращает последний платёж заказа пользователя, сверив незавершённый платёж с провайдером
+	GetForUser(ctx context.Context, userID, orderID string) (*models.Payment, error)
+	// ListByOrder возвращает все платежи заказа, последний первым
+	ListByOrder(ctx context.Context, orderID string) ([]*models.Payment, error)
+	// HandleWebhook обрабатывает уведомление провайдера provider, пришедшее с адреса source:
+	// проверяет отправителя, сверяет с провайдером только платёж, уже сохранённый у нас,
+	// и отмечает заказ оплаченным. Если заказ успели отменить, деньги возвращаются.
+	HandleWebhook(ctx context.Context, provider string, source netip.Addr, body []byte) error
+	// Refund возвращает покупателю деньги за оплаченный заказ и переводит его в refunded
+	Refund(ctx context.Context, orderID, comment string) (*models.Order, error)
+	// Transition — ручная смена статуса заказа админом. Заказ с платежом через провайдера
+	// не переводится вручную в paid или refunded (ErrProviderPayment): оплату подтверждает
+	// провайдер, а деньги возвращает Refund.
+	Transition(ctx context.Context, orderID string, to models.OrderStatus, comment string) (*models.Order, error)
+}
+
+var (
+	ErrPaymentNotFound        = errors.New("payment not found")
+	ErrUnknownPaymentProvider = errors.New("unknown payment provider")
+	ErrOrderNotPayable        = errors.New("order cannot be paid in its current status")
+	ErrNothingToRefund        = errors.New("order has no succeeded payment to refund")
+	ErrPaymentMismatch        = errors.New("payment does not match the order")
+	ErrProviderPayment        = errors.New("order has a payment through the payment provider")
+)
+
+// paymentKeys — пространство имён ключей идемпотентности: ключ выводится из заказа и номера попытки,
+// поэтому одновременные запросы оплаты одного заказа получают один и тот же платёж
+var paymentKeys = uuid.MustParse("6f1c7c1e-4b8e-4f3a-9d8e-2a5f0c9b7d41")
+
+type paymentService struct {
+	repo      repository.PaymentRepository
+	orders    OrderService
+	provider  payments.PaymentProvider
+	currency  string
+	returnURL string
+}
+
+func NewPaymentService(
+	r repository.PaymentRepository,
+	orders OrderService,
+	provider payments.PaymentProvider,
+	currency, returnURL string,
+) PaymentService {
+	return &paymentService{
+		repo:      r,
+		orders:    orders,
+		provider:  provider,
+		currency:  currency,
+		returnURL: returnURL,
+	}
+}
+
+func (s *paymentService) Pay(ctx context.Context, userID, orderID string) (*models.Payment, error) {
+	o, err := s.orders.GetForUser(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != models.OrderNew && o.Status != models.OrderAwaitingPayment {
+		return nil, fmt.Errorf("%w: %s", ErrOrderNotPayable, o.Status)
+	}
+	if o.Total <= 0 {
+		return nil, fmt.Errorf("%w: nothing to pay", ErrOrderNotPayable)
+	}
+
+	history, err := s.repo.ListByOrder(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > 0 {
+		p, err := s.syncPending(ctx, history[0])
+		if err != nil || p.Status != models.PaymentCanceled {
+			return p, err
+		}
+	}
+
+	if o.Status == models.OrderNew {
+		if o, err = s.orders.Transition(ctx, o.ID, models.OrderAwaitingPayment, "payment started"); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	p := &models.Payment{
+		ID:        uuid.NewSHA1(paymentKeys, []byte(o.ID+"/"+strconv.Itoa(len(history)))).String(),
+		OrderID:   o.ID,
+		Provider:  s.provider.Name(),
+		Amount:    o.Total,
+		Currency:  s.currency,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	pp, err := s.provider.CreatePayment(ctx, payments.CreatePaymentRequest{
+		IdempotenceKey: p.ID,
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		Description:    fmt.Sprintf("Заказ №%d", o.Number),
+		ReturnURL:      s.returnURL,
+		Metadata:       map[string]string{"order_id": o.ID, "payment_id": p.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.ExternalID, p.Status, p.ConfirmationURL = pp.ID, models.PaymentPending, pp.ConfirmationURL
+
+	err = s.repo.Create(ctx, p)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		// тот же платёж уже сохранил параллельный запрос: ключ идемпотентности у них общий
+		if p, err = s.repo.GetByID(ctx, p.ID); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	// встроенный провайдер и сохранённые карты могут провести платёж сразу, без уведомления
+	return s.apply(ctx, p, pp)
+}
+
+func (s *paymentService) GetForUser(ctx context.Context, userID, orderID string) (*models.Payment, error) {
+	o, err := s.orders.GetForUser(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.repo.ListByOrder(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, ErrPaymentNotFound
+	}
+	return s.syncPending(ctx, history[0])
+}
+
+func (s *paymentService) ListByOrder(ctx context.Context, orderID string) ([]*models.Payment, error) {
+	o, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListByOrder(ctx, o.ID)
+}
+
+func (s *paymentService) HandleWebhook(ctx context.Context, provider string, source netip.Addr, body []byte) error {
+	if provider != s.provider.Name() {
+		return fmt.Errorf("%w: %q", ErrUnknownPaymentProvider, provider)
+	}
+	event, err := s.provider.VerifyWebhook(source, body)
+	if err != nil {
+		return err
+	}
+
+	// к провайдеру обращаемся только за своими платежами: уведомление о неизвестном ID
+	// отклоняется до запроса, и его содержимое не попадает в метрики
+	p, err := s.repo.GetByExternalID(ctx, provider, event.PaymentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// уведомление пришло раньше, чем платёж сохранён, или о чужом платеже; провайдер повторит его позже
+		return fmt.Errorf("%w: %s", ErrPaymentNotFound, event.PaymentID)
+	}
+	if err != nil {
+		return err
+	}
+	metrics.PaymentWebhooks.WithLabelValues(provider, event.Event).Inc()
+
+	_, err = s.sync(ctx, p)
+	return err
+}
+
+func (s *paymentService) Refund(ctx context.Context, orderID, comment string) (*models.Order, error) {
+	o, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !o.Status.CanTransitionTo(models.OrderRefunded) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrOrderTransition, o.Status, models.OrderRefunded)
+	}
+
+	history, err := s.repo.ListByOrder(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	var paid *models.Payment
+	for _, p := range history {
+		if p.Status == models.PaymentSucceeded {
+			paid = p
+			break
+		}
+	}
+	if paid == nil {
+		return nil, ErrNothingToRefund
+	}
+	if err := s.refund(ctx, paid, comment); err != nil {
+		return nil, err
+	}
+	return s.orders.Transition(ctx, o.ID, models.OrderRefunded, comment)
+}
+
+func (s *paymentService) Transition(ctx context.Context, orderID string, to models.OrderStatus, comment string) (*models.Order, error) {
+	if to == models.OrderPaid || to == models.OrderRefunded {
+		history, err := s.repo.ListByOrder(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		// отменённые платежи денег не двигали: такой заказ можно провести вручную.
+		// Незавершённый платёж сверяется с провайдером — брошенный покупателем мог уже отмениться
+		for _, p := range history {
+			if p, err = s.syncPending(ctx, p); err != nil {
+				return nil, err
+			}
+			if p.Status == models.PaymentCanceled {
+				continue
+			}
+			if to == models.OrderRefunded {
+				return nil, fmt.Errorf("%w: refund it via POST /api/v1/admin/orders/%s/refund", ErrProviderPayment, orderID)
+			}
+			return nil, fmt.Errorf("%w: the order becomes paid when the provider confirms payment %s", ErrProviderPayment, p.ExternalID)
+		}
+	}
+	return s.orders.Transition(ctx, orderID, to, comment)
+}
+
+// syncPending сверяет с провайдером платёж, статус которого ещё может измениться
+func (s *paymentService) syncPending(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	if p.Status.Final() {
+		return p, nil
+	}
+	return s.sync(ctx, p)
+}
+
+// sync сверяет платёж с провайдером
+func (s *paymentService) sync(ctx context.Context, p *models.Payment) (*models.Payment, error) {
+	if p.Provider != s.provider.Name() {
+		// платёж другого провайдера (настройки сменились) — показываем последнее известное состояние
+		return p, nil
+	}
+	pp, err := s.provider.GetPayment(ctx, p.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, p, pp)
+}
+
+// apply записывает состояние платежа у провайдера и, если платёж прошёл, отмечает заказ оплаченным.
+// Повторный вызов с тем же состоянием ничего не меняет.
+func (s *paymentService) apply(ctx context.Context, p *models.Payment, pp *payments.Payment) (*models.Payment, error) {
+	if pp.Amount != p.Amount || pp.Currency != p.Currency {
+		return nil, fmt.Errorf("%w: payment %s is %d %s at provider, expected %d %s",
+			ErrPaymentMismatch, p.ID, pp.Amount, pp.Currency, p.Amount, p.Currency)
+	}
+
+	var paidAt *time.Time
+	if pp.Status == models.PaymentSucceeded {
+		now := time.Now().UTC()
+		paidAt = &now
+	}
+	if err := s.repo.UpdateStatus(ctx, p.ID, pp.Status, pp.RefundedAmount, paidAt); err != nil {
+		return nil, err
+	}
+	p, err := s.repo.GetByID(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Status == models.PaymentSucceeded {
+		if err := s.markOrderPaid(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// markOrderPaid переводит заказ платежа p в paid от имени системы. Уже оплаченный заказ не меняется,
+// а за отменённый (например, резерв истёк, пока покупатель платил) деньги возвращаются.
+func (s *paymentService) markOrderPaid(ctx context.Context, p *models.Payment) error {
+	ctx = systemContext(ctx)
+	comment := fmt.Sprintf("payment %s succeeded", p.ExternalID)
+
+	o, err := s.orders.GetByID(ctx, p.OrderID)
+	if err != nil {
+		return err
+	}
+	switch o.Status {
+	case models.OrderNew:
+		if o, err = s.orders.Transition(ctx, o.ID, models.OrderAwaitingPayment, comment); err != nil {
+			return err
+		}
+		fallthrough
+	case models.OrderAwaitingPayment:
+		if o.Total != p.Amount {
+			return fmt.Errorf("%w: order %s total is %d, payment %s is %d", ErrPaymentMismatch, o.ID, o.Total, p.ID, p.Amount)
+		}
+		_, err = s.orders.Transition(ctx, o.ID, models.OrderPaid, comment)
+		if errors.Is(err, ErrOrderStatusConflict) {
+			// заказ одновременно оплатили другим уведомлением или отменили — разберёмся при повторе уведомления
+			return fmt.Errorf("order %s: %w", o.ID, err)
+		}
+		return err
+	case models.OrderCancelled:
+		return s.refund(ctx, p, "order was cancelled before the payment succeeded")
+	}
+	// заказ уже оплачен этим платежом; за второй успешный платёж по тому же заказу деньги возвращаются
+	paid, err := s.repo.ListByOrder(ctx, o.ID)
+	if err != nil {
+		return err
+	}
+	for _, other := range paid {
+		if other.ID != p.ID && other.Status == models.PaymentSucceeded && other.CreatedAt.Before(p.CreatedAt) {
+			return s.refund(ctx, p, "order has already been paid")
+		}
+	}
+	return nil
+}
+
+// refund возвращает не возвращённый ещё остаток платежа p. Ключ идемпотентности выводится из платежа,
+// поэтому повторный вызов не вернёт деньги дважды.
+func (s *paymentService) refund(ctx context.Context, p *models.Payment, reason string) error {
+	amount := p.Amount - p.RefundedAmount
+	if amount <= 0 {
+		return nil
+	}
+	_, err := s.provider.Refund(ctx, payments.RefundRequest{
+		IdempotenceKey: uuid.NewSHA1(paymentKeys, []byte(p.ID+"/refund/"+strconv.Itoa(p.RefundedAmount))).String(),
+		PaymentID:      p.ExternalID,
+		Amount:         amount,
+		Currency:       p.Currency,
+		Description:    reason,
+	})
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateStatus(ctx, p.ID, p.Status, p.RefundedAmount+amount, nil)
+}
+
+// systemContext убирает пользователя из контекста: действия записываются как системные
+func systemContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, middlewares.UserID(), "")
+}
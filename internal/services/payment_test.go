@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/dto"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/payments"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/config"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// memoryPayments — PaymentRepository в памяти с той же семантикой UpdateStatus, что и в базе
+type memoryPayments struct {
+	mu       sync.Mutex
+	payments map[string]*models.Payment
+}
+
+func newMemoryPayments() *memoryPayments {
+	return &memoryPayments{payments: map[string]*models.Payment{}}
+}
+
+func (r *memoryPayments) Create(_ context.Context, p *models.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.payments {
+		if existing.ID == p.ID || (existing.Provider == p.Provider && existing.ExternalID == p.ExternalID) {
+			return repository.ErrAlreadyExists
+		}
+	}
+	cp := *p
+	r.payments[p.ID] = &cp
+	return nil
+}
+
+func (r *memoryPayments) GetByID(_ context.Context, id string) (*models.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.payments[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *memoryPayments) GetByExternalID(_ context.Context, provider, externalID string) (*models.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.payments {
+		if p.Provider == provider && p.ExternalID == externalID {
+			cp := *p
+			return &cp, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *memoryPayments) ListByOrder(_ context.Context, orderID string) ([]*models.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := []*models.Payment{}
+	for _, p := range r.payments {
+		if p.OrderID == orderID {
+			cp := *p
+			list = append(list, &cp)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list, nil
+}
+
+func (r *memoryPayments) UpdateStatus(_ context.Context, id string, status models.PaymentStatus, refundedAmount int, paidAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.payments[id]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+	p.Status = status
+	p.RefundedAmount = max(p.RefundedAmount, refundedAmount)
+	if p.PaidAt == nil {
+		p.PaidAt = paidAt
+	}
+	p.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// memoryOrders — OrderService в памяти: хранит заказы и проверяет переходы статусов
+type memoryOrders struct {
+	mu      sync.Mutex
+	orders  map[string]*models.Order
+	history []models.OrderStatus
+}
+
+func newMemoryOrders(orders ...*models.Order) *memoryOrders {
+	m := &memoryOrders{orders: map[string]*models.Order{}}
+	for _, o := range orders {
+		m.orders[o.ID] = o
+	}
+	return m
+}
+
+func (m *memoryOrders) Checkout(context.Context, string, dto.CheckoutRequest) (*models.Order, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryOrders) GetForUser(ctx context.Context, userID, id string) (*models.Order, error) {
+	o, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if o.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+	return o, nil
+}
+
+func (m *memoryOrders) ListForUser(context.Context, string, int, int) ([]*models.Order, int, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (m *memoryOrders) Cancel(ctx context.Context, userID, id, comment string) (*models.Order, error) {
+	if _, err := m.GetForUser(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return m.Transition(ctx, id, models.OrderCancelled, comment)
+}
+
+func (m *memoryOrders) GetByID(_ context.Context, id string) (*models.Order, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	cp := *o
+	return &cp, nil
+}
+
+func (m *memoryOrders) List(context.Context, repository.OrderFilter) ([]*models.Order, int, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (m *memoryOrders) Transition(_ context.Context, id string, to models.OrderStatus, _ string) (*models.Order, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, ok := m.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	if !o.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrOrderTransition, o.Status, to)
+	}
+	o.Status = to
+	m.history = append(m.history, to)
+	cp := *o
+	return &cp, nil
+}
+
+func (m *memoryOrders) ExpireReservations(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *memoryOrders) status(t *testing.T, id string) models.OrderStatus {
+	t.Helper()
+	o, err := m.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get order: %v", err)
+	}
+	return o.Status
+}
+
+// testSource — адрес отправителя уведомлений в тестах; встроенный провайдер принимает любой
+var testSource = netip.MustParseAddr("127.0.0.1")
+
+func newTestPaymentService(orders ...*models.Order) (PaymentService, *payments.Fake, *memoryOrders) {
+	fake := payments.NewFake(false)
+	memOrders := newMemoryOrders(orders...)
+	svc := NewPaymentService(newMemoryPayments(), memOrders, fake, "RUB", "https://shop.test/orders")
+	return svc, fake, memOrders
+}
+
+func testOrder(id string) *models.Order {
+	return &models.Order{ID: id, Number: 1000, UserID: "user-1", Status: models.OrderNew, Total: 4990}
+}
+
+func TestPaymentWebhookMarksOrderPaid(t *testing.T) {
+	ctx := context.Background()
+	svc, fake, orders := newTestPaymentService(testOrder("order-1"))
+
+	p, err := svc.Pay(ctx, "user-1", "order-1")
+	if err != nil {
+		t.Fatalf("Pay: %v", err)
+	}
+	if p.Status != models.PaymentPending || p.Amount != 4990 || p.Provider != "fake" {
+		t.Fatalf("payment = %+v", p)
+	}
+	if got := orders.status(t, "order-1"); got != models.OrderAwaitingPayment {
+		t.Fatalf("order status after Pay = %s, want %s", got, models.OrderAwaitingPayment)
+	}
+
+	// повторный Pay, пока платёж не завершён, возвращает тот же платёж
+	again, err := svc.Pay(ctx, "user-1", "order-1")
+	if err != nil {
+		t.Fatalf("second Pay: %v", err)
+	}
+	if again.ID != p.ID {
+		t.Fatalf("second Pay created payment %s, want %s", again.ID, p.ID)
+	}
+
+	if err := fake.SetStatus(p.ExternalID, models.PaymentSucceeded); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+	body := fake.Notification("payment.succeeded", p.ExternalID)
+	if err := svc.HandleWebhook(ctx, "fake", testSource, body); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	if got := orders.status(t, "order-1"); got != models.OrderPaid {
+		t.Fatalf("order status after webhook = %s, want %s", got, models.OrderPaid)
+	}
+
+	// повторное уведомление ничего не меняет
+	if err := svc.HandleWebhook(ctx, "fake", testSource, body); err != nil {
+		t.Fatalf("duplicate HandleWebhook: %v", err)
+	}
+	if got := orders.status(t, "order-1"); got != models.OrderPaid {
+		t.Fatalf("order status after duplicate webhook = %s, want %s", got, models.OrderPaid)
+	}
+	want := []models.OrderStatus{models.OrderAwaitingPayment, models.OrderPaid}
+	if fmt.Sprint(orders.history) != fmt.Sprint(want) {
+		t.Errorf("transitions = %v, want %v", orders.history, want)
+	}
+
+	paid, err := svc.GetForUser(ctx, "user-1", "order-1")
+	if err != nil {
+		t.Fatalf("GetForUser: %v", err)
+	}
+	if paid.Status != models.PaymentSucceeded || paid.PaidAt == nil {
+		t.Errorf("payment after webhook = %+v", paid)
+	}
+}
+
+// countingProvider считает запросы состояния платежа к провайдеру
+type countingProvider struct {
+	payments.PaymentProvider
+	gets int
+}
+
+func (p *countingProvider) GetPayment(ctx context.Context, id string) (*payments.Payment, error) {
+	p.gets++
+	return p.PaymentProvider.GetPayment(ctx, id)
+}
+
+func TestPaymentWebhookRejectsUnknownPayment(t *testing.T) {
+	ctx := context.Background()
+	fake := payments.NewFake(false)
+	provider := &countingProvider{PaymentProvider: fake}
+	svc := NewPaymentService(newMemoryPayments(), newMemoryOrders(), provider, "RUB", "https://shop.test/orders")
+
+	p, err := fake.CreatePayment(ctx, payments.CreatePaymentRequest{Amount: 4990, Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	// платёж есть у провайдера, но не у нас: к провайдеру не обращаемся
+	err = svc.HandleWebhook(ctx, "fake", testSource, fake.Notification("payment.succeeded", p.ID))
+	if !errors.Is(err, ErrPaymentNotFound) {
+		t.Fatalf("HandleWebhook error = %v, want ErrPaymentNotFound", err)
+	}
+	if provider.gets != 0 {
+		t.Errorf("GetPayment called %d times for unknown payment, want 0", provider.gets)
+	}
+
+	if err := svc.HandleWebhook(ctx, "fake", testSource, []byte("payment.succeeded")); !errors.Is(err, payments.ErrInvalidWebhook) {
+		t.Errorf("HandleWebhook(not json) error = %v, want ErrInvalidWebhook", err)
+	}
+	if err := svc.HandleWebhook(ctx, "yookassa", testSource, fake.Notification("payment.succeeded", p.ID)); !errors.Is(err, ErrUnknownPaymentProvider) {
+		t.Errorf("HandleWebhook(yookassa) error = %v, want ErrUnknownPaymentProvider", err)
+	}
+}
+
+func TestPaymentWebhookRejectsUntrustedSource(t *testing.T) {
+	ctx := context.Background()
+	yk, err := payments.NewYooKassa(config.YooKassaConfig{WebhookIPs: []string{"185.71.76.0/27"}}, nil)
+	if err != nil {
+		t.Fatalf("NewYooKassa: %v", err)
+	}
+	provider := &countingProvider{PaymentProvider: yk}
+	svc := NewPaymentService(newMemoryPayments(), newMemoryOrders(), provider, "RUB", "https://shop.test/orders")
+
+	body := []byte(`{"type":"notification","event":"payment.succeeded","object":{"id":"yk-1"}}`)
+	err = svc.HandleWebhook(ctx, "yookassa", netip.MustParseAddr("203.0.113.7"), body)
+	if !errors.Is(err, payments.ErrUntrustedSource) {
+		t.Fatalf("HandleWebhook error = %v, want ErrUntrustedSource", err)
+	}
+	if provider.gets != 0 {
+		t.Errorf("GetPayment called %d times for untrusted webhook, want 0", provider.gets)
+	}
+}
+
+func TestPaymentManualTransitionWithProviderPayment(t *testing.T) {
+	ctx := context.Background()
+	svc, fake, orders := newTestPaymentService(testOrder("order-1"), testOrder("order-2"))
+
+	p, err := svc.Pay(ctx, "user-1", "order-1")
+	if err != nil {
+		t.Fatalf("Pay: %v", err)
+	}
+	if _, err := svc.Transition(ctx, "order-1", models.OrderPaid, "paid by phone"); !errors.Is(err, ErrProviderPayment) {
+		t.Fatalf("manual paid error = %v, want ErrProviderPayment", err)
+	}
+
+	if err := fake.SetStatus(p.ExternalID, models.PaymentSucceeded); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+	body := fake.Notification("payment.succeeded", p.ExternalID)
+	if err := svc.HandleWebhook(ctx, "fake", testSource, body); err != nil {
+		t.Fatalf("HandleWebhook: %v", err)
+	}
+	if _, err := svc.Transition(ctx, "order-1", models.OrderRefunded, ""); !errors.Is(err, ErrProviderPayment) {
+		t.Fatalf("manual refunded error = %v, want ErrProviderPayment", err)
+	}
+
+	refunded, err := svc.Refund(ctx, "order-1", "customer changed their mind")
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if refunded.Status != models.OrderRefunded {
+		t.Errorf("order status after Refund = %s, want %s", refunded.Status, models.OrderRefunded)
+	}
+	after, err := fake.GetPayment(ctx, p.ExternalID)
+	if err != nil {
+		t.Fatalf("GetPayment: %v", err)
+	}
+	if after.RefundedAmount != p.Amount {
+		t.Errorf("refunded at provider = %d, want %d", after.RefundedAmount, p.Amount)
+	}
+
+	// заказ без платежей через провайдера проводится вручную
+	for _, to := range []models.OrderStatus{models.OrderAwaitingPayment, models.OrderPaid, models.OrderRefunded} {
+		if _, err := svc.Transition(ctx, "order-2", to, "cash"); err != nil {
+			t.Fatalf("manual transition to %s: %v", to, err)
+		}
+	}
+	if got := orders.status(t, "order-2"); got != models.OrderRefunded {
+		t.Errorf("order-2 status = %s, want %s", got, models.OrderRefunded)
+	}
+}
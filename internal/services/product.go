@@ -1,49 +1,686 @@
 package services
 
 import (
+	"context"
 	"dozenChairs/internal/models"
 	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/slugify"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"time"
 )
 
 type ProductService interface {
-	Create(p *models.Product) error
+	Create(ctx context.Context, p *models.Product) error
 	GetBySlug(slug string) (*models.Product, error)
+	GetProduct(slug string) (*models.Product, error)
+	GetSet(slug string) (*models.Product, error)
+	ResolveSlug(oldSlug string) (string, error)
 	GetAll(filter repository.ProductFilter) ([]*models.Product, error)
-	GetCategories() ([]string, error)
-	Update(slug string, p *models.Product) error
-	Delete(slug string) error
+	GetPage(filter repository.ProductFilter) (*models.ProductPage, error)
+	Count(filter repository.ProductFilter) (int, error)
+	Search(filter repository.ProductFilter) ([]*models.ProductSearchResult, error)
+	GetFacets(filter repository.ProductFilter) (*models.ProductFacets, error)
+	Update(ctx context.Context, slug string, p *models.Product) error
+	Patch(ctx context.Context, slug string, version int, patch Patch) (*models.Product, error)
+	Delete(ctx context.Context, slug string) error
+	GetTrash(filter repository.ProductFilter) (*models.ProductPage, error)
+	Restore(ctx context.Context, slug string) error
+	Import(ctx context.Context, rows []ImportRow, dryRun bool) (*models.ImportReport, error)
+
+	GetRevisions(ctx context.Context, slug string, limit, offset int) ([]*models.ProductRevision, int, error)
+	GetRevision(ctx context.Context, slug, id string) (*models.ProductRevision, error)
+	Rollback(ctx context.Context, slug, revisionID string) (*models.Product, error)
+	GetPriceHistory(ctx context.Context, slug string, since time.Time) ([]models.PricePoint, error)
+
+	GetVariants(ctx context.Context, slug string) ([]models.ProductVariant, error)
+	CreateVariant(ctx context.Context, slug string, v *models.ProductVariant) error
+	UpdateVariant(ctx context.Context, slug, id string, v *models.ProductVariant) error
+	DeleteVariant(ctx context.Context, slug, id string) error
 }
 
+var (
+	ErrProductNotFound      = errors.New("product not found")
+	ErrVariantNotFound      = errors.New("variant not found")
+	ErrVariantsNotSupported = errors.New("variants are only supported for products of type product")
+	ErrNotASet              = errors.New("item is not a set")
+	ErrInvalidSetItems      = errors.New("invalid set items")
+	ErrInvalidSchedule      = errors.New("unpublishAt must be later than publishAt")
+	ErrRevisionNotFound     = errors.New("revision not found")
+	ErrRevisionNoSnapshot   = errors.New("revision has no snapshot to roll back to")
+)
+
 type productService struct {
-	repo repository.ProductRepository
+	repo       repository.ProductRepository
+	categories repository.CategoryRepository
+	variants   repository.VariantRepository
+	revisions  repository.RevisionRepository
+	prices     repository.PriceHistoryRepository
+	promotions PromotionService
 }
 
-func NewProductService(r repository.ProductRepository) ProductService {
-	return &productService{repo: r}
+func NewProductService(
+	r repository.ProductRepository,
+	c repository.CategoryRepository,
+	v repository.VariantRepository,
+	rev repository.RevisionRepository,
+	ph repository.PriceHistoryRepository,
+	promo PromotionService,
+) ProductService {
+	return &productService{repo: r, categories: c, variants: v, revisions: rev, prices: ph, promotions: promo}
 }
 
-func (s *productService) Create(p *models.Product) error {
-	return s.repo.Create(p)
+func (s *productService) Create(ctx context.Context, p *models.Product) error {
+	now := time.Now().UTC()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	if err := applyStatus(p, "", now); err != nil {
+		return err
+	}
+	if err := s.resolveCategory(p); err != nil {
+		return err
+	}
+	if err := s.checkSetItems(p); err != nil {
+		return err
+	}
+	if err := s.assignSlug(p); err != nil {
+		return err
+	}
+
+	return s.inTx(ctx, func(tx *productService) error {
+		if err := tx.repo.Create(p); err != nil {
+			return err
+		}
+		if err := tx.reload(p); err != nil {
+			return err
+		}
+		return tx.record(ctx, nil, p, models.RevisionCreate, nil)
+	})
 }
 
+// GetBySlug возвращает товар витрины с применёнными акциями; отсутствие товара — ErrProductNotFound
 func (s *productService) GetBySlug(slug string) (*models.Product, error) {
-	return s.repo.GetBySlug(slug)
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+	return p, s.applyPromotions(p)
+}
+
+// GetProduct возвращает товар в любом статусе; отсутствие товара — ErrProductNotFound
+func (s *productService) GetProduct(slug string) (*models.Product, error) {
+	return s.getProduct(slug)
+}
+
+// GetSet возвращает набор с раскрытыми данными входящих в него товаров
+func (s *productService) GetSet(slug string) (*models.Product, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+	if p.Type != models.TypeSet {
+		return nil, ErrNotASet
+	}
+	if len(p.Includes) == 0 {
+		return p, s.applyPromotions(p)
+	}
+
+	ids := make([]string, len(p.Includes))
+	for i, item := range p.Includes {
+		ids[i] = item.ProductID
+	}
+	components, err := s.repo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*models.Product, len(components))
+	for _, c := range components {
+		byID[c.ID] = c
+	}
+	for i := range p.Includes {
+		p.Includes[i].Product = byID[p.Includes[i].ProductID]
+	}
+	return p, s.applyPromotions(append(components, p)...)
 }
 
 func (s *productService) GetAll(filter repository.ProductFilter) ([]*models.Product, error) {
-	return s.repo.GetAll(filter)
+	items, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+	if !filter.WithUnpublished {
+		err = s.applyPromotions(items...)
+	}
+	return items, err
 }
 
-func (s *productService) GetCategories() ([]string, error) {
-	return s.repo.GetCategories()
+// GetPage возвращает страницу товаров с общим количеством и курсором следующей страницы.
+// Запрашивается на одну запись больше лимита, чтобы понять, есть ли следующая страница.
+func (s *productService) GetPage(filter repository.ProductFilter) (*models.ProductPage, error) {
+	total, err := s.repo.Count(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filter.Limit
+	if limit > 0 {
+		filter.Limit = limit + 1
+	}
+
+	items, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.ProductPage{Items: items, Total: total}
+	if limit > 0 && len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = repository.EncodeCursor(filter, page.Items[limit-1])
+	}
+
+	// курсор строится по цене из базы: акции применяются только после него,
+	// иначе сортировка по price сравнивала бы цену со скидкой с колонкой price
+	if !filter.WithUnpublished {
+		if err := s.applyPromotions(page.Items...); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (s *productService) Count(filter repository.ProductFilter) (int, error) {
+	return s.repo.Count(filter)
+}
+
+func (s *productService) Search(filter repository.ProductFilter) ([]*models.ProductSearchResult, error) {
+	results, err := s.repo.Search(filter)
+	if err != nil || filter.WithUnpublished {
+		return results, err
+	}
+	products := make([]*models.Product, len(results))
+	for i, r := range results {
+		products[i] = r.Product
+	}
+	return results, s.applyPromotions(products...)
+}
+
+func (s *productService) GetFacets(filter repository.ProductFilter) (*models.ProductFacets, error) {
+	return s.repo.GetFacets(filter)
 }
 
-func (s *productService) Update(slug string, p *models.Product) error {
+// Update сохраняет товар. Ненулевой p.Version — версия, которую видел клиент:
+// если товар с тех пор изменился, возвращается repository.ErrVersionConflict.
+func (s *productService) Update(ctx context.Context, slug string, p *models.Product) error {
+	existing, err := s.getProduct(slug)
+	if err != nil {
+		return err
+	}
+	return s.update(ctx, existing, p, models.RevisionUpdate, nil)
+}
+
+// update сохраняет p поверх existing и записывает ревизию с действием action
+func (s *productService) update(ctx context.Context, existing, p *models.Product, action models.RevisionAction, sourceRevisionID *string) error {
+	if err := s.prepareUpdate(existing, p); err != nil {
+		return err
+	}
+	return s.inTx(ctx, func(tx *productService) error {
+		if err := tx.repo.Update(existing.Slug, p); err != nil {
+			return err
+		}
+		return tx.afterUpdate(ctx, existing, p, action, sourceRevisionID)
+	})
+}
+
+// prepareUpdate переносит в p неизменяемые поля existing и проверяет новые значения
+func (s *productService) prepareUpdate(existing, p *models.Product) error {
+	p.ID = existing.ID
+	p.CreatedAt = existing.CreatedAt
+
+	if err := applyStatus(p, existing.Status, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := s.resolveCategory(p); err != nil {
+		return err
+	}
+	if err := s.checkSetItems(p); err != nil {
+		return err
+	}
+	if p.Slug == "" {
+		p.Slug = existing.Slug
+	}
+	if p.Slug != existing.Slug {
+		if err := s.checkSlugFree(p.Slug, p.ID); err != nil {
+			return err
+		}
+	}
 	p.UpdatedAt = time.Now().UTC()
-	return s.repo.Update(slug, p)
+	return nil
+}
+
+// afterUpdate перечитывает сохранённый товар и записывает ревизию;
+// вызывается в транзакции изменения (см. inTx)
+func (s *productService) afterUpdate(ctx context.Context, existing, p *models.Product, action models.RevisionAction, sourceRevisionID *string) error {
+	if err := s.reload(p); err != nil {
+		return err
+	}
+	return s.record(ctx, existing, p, action, sourceRevisionID)
+}
+
+// Delete переносит товар в корзину
+func (s *productService) Delete(ctx context.Context, slug string) error {
+	existing, err := s.getProduct(slug)
+	if err != nil {
+		return err
+	}
+
+	err = s.inTx(ctx, func(tx *productService) error {
+		if err := tx.repo.Delete(slug); err != nil {
+			return err
+		}
+		rev := newRevision(ctx, existing.ID, models.RevisionDelete, map[string]models.FieldChange{
+			"deletedAt": {Old: nil, New: time.Now().UTC()},
+		})
+		rev.Snapshot = revisionSnapshot(existing)
+		return tx.revisions.Create(ctx, rev)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// GetTrash возвращает страницу удалённых товаров в любом статусе
+func (s *productService) GetTrash(filter repository.ProductFilter) (*models.ProductPage, error) {
+	filter.Trashed = true
+	filter.WithUnpublished = true
+	return s.GetPage(filter)
+}
+
+// Restore возвращает товар из корзины. Набор нельзя восстановить,
+// пока какие-то из его товаров сами находятся в корзине.
+func (s *productService) Restore(ctx context.Context, slug string) error {
+	p, err := s.repo.GetDeletedBySlug(slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if p.Type == models.TypeSet && len(p.Includes) > 0 {
+		ids := make([]string, len(p.Includes))
+		for i, item := range p.Includes {
+			ids[i] = item.ProductID
+		}
+		active, err := s.repo.GetByIDs(ids)
+		if err != nil {
+			return err
+		}
+		if len(active) < len(ids) {
+			return fmt.Errorf("%w: set includes deleted products, restore them first", ErrInvalidSetItems)
+		}
+	}
+
+	err = s.inTx(ctx, func(tx *productService) error {
+		if err := tx.repo.Restore(slug); err != nil {
+			return err
+		}
+		rev := newRevision(ctx, p.ID, models.RevisionRestore, map[string]models.FieldChange{
+			"deletedAt": {Old: p.DeletedAt, New: nil},
+		})
+		rev.Snapshot = revisionSnapshot(p)
+		return tx.revisions.Create(ctx, rev)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// GetRevisions возвращает журнал правок товара (в том числе находящегося в корзине) от новых к старым
+func (s *productService) GetRevisions(ctx context.Context, slug string, limit, offset int) ([]*models.ProductRevision, int, error) {
+	p, err := s.getAnyProduct(slug)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.revisions.GetByProductID(ctx, p.ID, limit, offset)
+}
+
+// GetRevision возвращает ревизию товара вместе со снимком состояния
+func (s *productService) GetRevision(ctx context.Context, slug, id string) (*models.ProductRevision, error) {
+	p, err := s.getAnyProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.revisions.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && rev.ProductID != p.ID) {
+		return nil, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+// Rollback возвращает поля товара к состоянию из ревизии. Изображения и варианты не затрагиваются,
+// а сам откат записывается новой ревизией.
+func (s *productService) Rollback(ctx context.Context, slug, revisionID string) (*models.Product, error) {
+	existing, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.GetRevision(ctx, slug, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if rev.Snapshot == nil {
+		return nil, ErrRevisionNoSnapshot
+	}
+
+	p := *rev.Snapshot
+	p.Images, p.Variants, p.PriceRange, p.DeletedAt = nil, nil, nil, nil
+	p.Version = existing.Version
+	if err := s.update(ctx, existing, &p, models.RevisionRollback, &rev.ID); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPriceHistory возвращает изменения цены товара с момента since (от новых к старым)
+// вместе с ценой, которая действовала на этот момент
+func (s *productService) GetPriceHistory(ctx context.Context, slug string, since time.Time) ([]models.PricePoint, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.prices.GetByProductID(ctx, p.ID, since)
+}
+
+// record сохраняет ревизию с diff между before и after и снимком after
+func (s *productService) record(ctx context.Context, before, after *models.Product, action models.RevisionAction, sourceRevisionID *string) error {
+	rev := newRevision(ctx, after.ID, action, diffProducts(revisionSnapshot(before), revisionSnapshot(after)))
+	rev.Snapshot = revisionSnapshot(after)
+	rev.SourceRevisionID = sourceRevisionID
+	return s.revisions.Create(ctx, rev)
+}
+
+// inTx выполняет fn с копией сервиса, у которой товары и ревизии пишутся в одной транзакции.
+// Вложенный вызов открывает точку сохранения.
+func (s *productService) inTx(ctx context.Context, fn func(tx *productService) error) error {
+	return s.repo.WithTx(ctx, func(repo repository.ProductRepository) error {
+		tx := *s
+		tx.repo = repo
+		tx.revisions = repo.Revisions()
+		return fn(&tx)
+	})
+}
+
+// reload перечитывает сохранённый товар, чтобы вернуть и записать в ревизию
+// производные поля (остатки, цену набора, изображения)
+func (s *productService) reload(p *models.Product) error {
+	fresh, err := s.repo.GetBySlug(p.Slug)
+	if err != nil {
+		return err
+	}
+	*p = *fresh
+	return nil
 }
 
-func (s *productService) Delete(slug string) error {
-	return s.repo.Delete(slug)
+// getAnyProduct ищет товар по slug, в том числе в корзине
+func (s *productService) getAnyProduct(slug string) (*models.Product, error) {
+	p, err := s.repo.GetBySlug(slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		p, err = s.repo.GetDeletedBySlug(slug)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProductNotFound
+	}
+	return p, err
+}
+
+// checkSetItems проверяет состав набора: он не пуст, а каждый компонент существует
+// и имеет тип product без вариантов. Повторяющиеся позиции объединяются. У товаров состав и скидка набора сбрасываются.
+func (s *productService) checkSetItems(p *models.Product) error {
+	if p.Type != models.TypeSet {
+		p.Includes = nil
+		p.SetDiscountPercent = nil
+		return nil
+	}
+	if len(p.Includes) == 0 {
+		return fmt.Errorf("%w: set must include at least one product", ErrInvalidSetItems)
+	}
+
+	var items []models.IncludeItem
+	index := make(map[string]int)
+	for _, item := range p.Includes {
+		if item.ProductID == p.ID {
+			return fmt.Errorf("%w: set cannot include itself", ErrInvalidSetItems)
+		}
+		if i, ok := index[item.ProductID]; ok {
+			items[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(items)
+		items = append(items, models.IncludeItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID
+	}
+	components, err := s.repo.GetByIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]*models.Product, len(components))
+	for _, c := range components {
+		found[c.ID] = c
+	}
+	for _, id := range ids {
+		c, ok := found[id]
+		if !ok {
+			return fmt.Errorf("%w: product %s not found", ErrInvalidSetItems, id)
+		}
+		if c.Type != models.TypeProduct {
+			return fmt.Errorf("%w: %s is not a product", ErrInvalidSetItems, c.Slug)
+		}
+		// состав набора не указывает вариант, а остаток товара с вариантами учитывается по ним
+		if len(c.Variants) > 0 {
+			return fmt.Errorf("%w: %s has variants", ErrInvalidSetItems, c.Slug)
+		}
+	}
+
+	p.Includes = items
+	return nil
+}
+
+// applyStatus проверяет окно публикации и подставляет статус, если он не задан:
+// при обновлении сохраняется текущий, при создании товар с будущим publishAt
+// становится черновиком, иначе публикуется сразу.
+// Окно публикации приводится к UTC: колонки TIMESTAMP хранят время без часового пояса.
+func applyStatus(p *models.Product, current models.ProductStatus, now time.Time) error {
+	p.PublishAt = utcTime(p.PublishAt)
+	p.UnpublishAt = utcTime(p.UnpublishAt)
+	if p.PublishAt != nil && p.UnpublishAt != nil && !p.UnpublishAt.After(*p.PublishAt) {
+		return ErrInvalidSchedule
+	}
+	if p.Status != "" {
+		return nil
+	}
+
+	switch {
+	case current != "":
+		p.Status = current
+	case p.PublishAt != nil && p.PublishAt.After(now):
+		p.Status = models.StatusDraft
+	default:
+		p.Status = models.StatusPublished
+	}
+	return nil
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// ResolveSlug возвращает текущий slug товара по прежнему, чтобы ответить редиректом
+func (s *productService) ResolveSlug(oldSlug string) (string, error) {
+	slug, err := s.repo.ResolveSlug(oldSlug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrProductNotFound
+	}
+	return slug, err
+}
+
+// assignSlug проверяет заданный slug или генерирует его из названия.
+// Сгенерированный slug при совпадении получает числовой суффикс: stul, stul-2, stul-3…
+func (s *productService) assignSlug(p *models.Product) error {
+	if p.Slug != "" {
+		return s.checkSlugFree(p.Slug, p.ID)
+	}
+
+	base := slugify.Make(p.Title)
+	if base == "" {
+		base = string(p.Type)
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+		taken, err := s.repo.SlugTaken(candidate, p.ID)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			p.Slug = candidate
+			return nil
+		}
+	}
+}
+
+// checkSlugFree возвращает repository.ErrAlreadyExists, если slug занят другим товаром, в том числе как прежний
+func (s *productService) checkSlugFree(slug, productID string) error {
+	taken, err := s.repo.SlugTaken(slug, productID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return repository.ErrAlreadyExists
+	}
+	return nil
+}
+
+// resolveCategory привязывает товар к существующей категории по categoryId,
+// либо по slug/названию из поля category, и проставляет её название
+func (s *productService) resolveCategory(p *models.Product) error {
+	ctx := context.Background()
+
+	var c *models.Category
+	var err error
+	if p.CategoryID != "" {
+		c, err = s.categories.GetByID(ctx, p.CategoryID)
+	} else {
+		c, err = s.categories.FindBySlugOrName(ctx, p.Category)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	p.CategoryID = c.ID
+	p.Category = c.Name
+	return nil
+}
+
+func (s *productService) GetVariants(ctx context.Context, slug string) ([]models.ProductVariant, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.variants.GetByProductID(ctx, p.ID)
+}
+
+func (s *productService) CreateVariant(ctx context.Context, slug string, v *models.ProductVariant) error {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return err
+	}
+	if p.Type != models.TypeProduct {
+		return ErrVariantsNotSupported
+	}
+
+	now := time.Now().UTC()
+	v.ID = uuid.NewString()
+	v.ProductID = p.ID
+	v.InStock = v.UnitCount > 0
+	v.CreatedAt = now
+	v.UpdatedAt = now
+
+	return s.variants.Create(ctx, v)
+}
+
+func (s *productService) UpdateVariant(ctx context.Context, slug, id string, v *models.ProductVariant) error {
+	existing, err := s.getVariant(ctx, slug, id)
+	if err != nil {
+		return err
+	}
+
+	v.ID = existing.ID
+	v.ProductID = existing.ProductID
+	v.InStock = v.UnitCount > 0
+	v.CreatedAt = existing.CreatedAt
+	v.UpdatedAt = time.Now().UTC()
+
+	return s.variants.Update(ctx, v)
+}
+
+func (s *productService) DeleteVariant(ctx context.Context, slug, id string) error {
+	if _, err := s.getVariant(ctx, slug, id); err != nil {
+		return err
+	}
+	return s.variants.Delete(ctx, id)
+}
+
+// applyPromotions пересчитывает цены товаров витрины по действующим акциям.
+// Методы витрины не принимают контекст, поэтому акции загружаются с context.Background().
+func (s *productService) applyPromotions(products ...*models.Product) error {
+	if s.promotions == nil {
+		return nil
+	}
+	return s.promotions.Apply(context.Background(), products...)
+}
+
+func (s *productService) getProduct(slug string) (*models.Product, error) {
+	p, err := s.repo.GetBySlug(slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProductNotFound
+	}
+	return p, err
+}
+
+// getVariant загружает вариант и проверяет, что он принадлежит товару slug
+func (s *productService) getVariant(ctx context.Context, slug, id string) (*models.ProductVariant, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.variants.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && v.ProductID != p.ID) {
+		return nil, ErrVariantNotFound
+	}
+	return v, err
 }
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/pkg/slugify"
+	"dozenChairs/pkg/validation"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// attrColumnPrefix — префикс колонок с атрибутами товара: attr.color → attributes["color"]
+const attrColumnPrefix = "attr."
+
+// importColumns — колонки файла импорта, соответствующие полям models.Product
+var importColumns = map[string]bool{
+	"slug": true, "type": true, "title": true, "category": true, "categoryId": true,
+	"description": true, "price": true, "oldPrice": true, "inStock": true, "unitCount": true,
+	"tags": true, "includes": true, "setDiscountPercent": true,
+	"status": true, "publishAt": true, "unpublishAt": true,
+}
+
+var ErrInvalidImportColumns = errors.New("unknown import columns")
+
+// errDryRun откатывает транзакцию импорта в режиме проверки
+var errDryRun = errors.New("dry run")
+
+// importedProduct — товар, сохранённый импортом; before == nil для созданных
+type importedProduct struct {
+	before, after *models.Product
+}
+
+// Import создаёт или обновляет товары по строкам файла, сопоставляя их по slug
+// (если колонки slug нет или она пуста — по slug, построенному из title).
+// У существующих товаров меняются только поля из заполненных ячеек.
+// Все строки обрабатываются в одной транзакции; ошибочная строка попадает в отчёт
+// и не мешает остальным. В режиме dryRun транзакция откатывается.
+func (s *productService) Import(ctx context.Context, rows []ImportRow, dryRun bool) (*models.ImportReport, error) {
+	if err := checkImportColumns(rows); err != nil {
+		return nil, err
+	}
+
+	report := &models.ImportReport{DryRun: dryRun, Total: len(rows), Rows: make([]models.ImportRowResult, 0, len(rows))}
+
+	err := s.inTx(ctx, func(tx *productService) error {
+		for _, row := range rows {
+			res := models.ImportRowResult{Row: row.Line, Slug: importSlug(row)}
+
+			var item importedProduct
+			// каждая строка — в своей точке сохранения, чтобы ошибка не прерывала транзакцию;
+			// ревизия строки пишется в той же точке сохранения
+			err := tx.inTx(ctx, func(rs *productService) error {
+				var err error
+				item, err = rs.importRow(ctx, res.Slug, row)
+				return err
+			})
+			if err == nil {
+				res.Slug = item.after.Slug
+			}
+
+			switch {
+			case err != nil:
+				res.Status = models.ImportFailed
+				res.Errors = validation.Messages(err)
+				report.Failed++
+			case item.before == nil:
+				res.Status = models.ImportCreated
+				report.Created++
+			default:
+				res.Status = models.ImportUpdated
+				report.Updated++
+			}
+			report.Rows = append(report.Rows, res)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if dryRun && errors.Is(err, errDryRun) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// importSlug возвращает slug, по которому строка сопоставляется с товаром
+func importSlug(row ImportRow) string {
+	if slug := row.Values["slug"]; slug != "" {
+		return slug
+	}
+	return slugify.Make(row.Values["title"])
+}
+
+// importRow сохраняет одну строку: обновляет товар с тем же slug (в том числе прежним) или создаёт новый,
+// и записывает ревизию
+func (s *productService) importRow(ctx context.Context, slug string, row ImportRow) (importedProduct, error) {
+	if slug == "" {
+		return importedProduct{}, errors.New("slug or title is required")
+	}
+
+	existing, err := s.repo.GetBySlug(slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		existing, err = s.findByOldSlug(slug)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s.importCreate(ctx, slug, row)
+	}
+	if err != nil {
+		return importedProduct{}, err
+	}
+	return s.importUpdate(ctx, existing, row)
+}
+
+// findByOldSlug ищет товар, которому slug принадлежал раньше
+func (s *productService) findByOldSlug(slug string) (*models.Product, error) {
+	current, err := s.repo.ResolveSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetBySlug(current)
+}
+
+func (s *productService) importCreate(ctx context.Context, slug string, row ImportRow) (importedProduct, error) {
+	now := time.Now().UTC()
+	p := &models.Product{
+		ID:        uuid.NewString(),
+		Type:      models.TypeProduct,
+		Slug:      slug,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.applyImportRow(p, row); err != nil {
+		return importedProduct{}, err
+	}
+	if err := validation.ValidateStruct(p); err != nil {
+		return importedProduct{}, err
+	}
+
+	if err := applyStatus(p, "", now); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.resolveCategory(p); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.checkSetItems(p); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.assignSlug(p); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.repo.Create(p); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.reload(p); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.record(ctx, nil, p, models.RevisionCreate, nil); err != nil {
+		return importedProduct{}, err
+	}
+	return importedProduct{after: p}, nil
+}
+
+func (s *productService) importUpdate(ctx context.Context, existing *models.Product, row ImportRow) (importedProduct, error) {
+	p := *existing
+	p.Images, p.Variants, p.PriceRange = nil, nil, nil
+	if err := s.applyImportRow(&p, row); err != nil {
+		return importedProduct{}, err
+	}
+	// slug остаётся прежним: по нему товар и был найден
+	p.Slug = existing.Slug
+	if err := validation.ValidateStruct(&p); err != nil {
+		return importedProduct{}, err
+	}
+
+	if err := s.prepareUpdate(existing, &p); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.repo.Update(existing.Slug, &p); err != nil {
+		return importedProduct{}, err
+	}
+	if err := s.afterUpdate(ctx, existing, &p, models.RevisionUpdate, nil); err != nil {
+		return importedProduct{}, err
+	}
+	return importedProduct{before: existing, after: &p}, nil
+}
+
+// applyImportRow переносит в p значения заполненных ячеек строки
+func (s *productService) applyImportRow(p *models.Product, row ImportRow) error {
+	var errs []error
+	fail := func(column string, err error) {
+		errs = append(errs, fmt.Errorf("%s: %w", column, err))
+	}
+
+	// атрибуты копируются, чтобы не менять карту исходного товара, с которым потом строится diff
+	attributes := make(map[string]interface{}, len(p.Attributes))
+	for k, v := range p.Attributes {
+		attributes[k] = v
+	}
+
+	for column, v := range row.Values {
+		if v == "" {
+			continue
+		}
+		if key, ok := strings.CutPrefix(column, attrColumnPrefix); ok {
+			attributes[key] = parseAttributeValue(v)
+			continue
+		}
+
+		switch column {
+		case "type":
+			p.Type = models.ProductType(v)
+		case "title":
+			p.Title = v
+		case "category":
+			p.Category = v
+			if row.Values["categoryId"] == "" {
+				p.CategoryID = ""
+			}
+		case "categoryId":
+			p.CategoryID = v
+		case "description":
+			p.Description = v
+		case "price":
+			n, err := parseImportInt(v)
+			if err != nil {
+				fail(column, err)
+			}
+			p.Price = n
+		case "oldPrice", "unitCount", "setDiscountPercent":
+			n, err := parseImportInt(v)
+			if err != nil {
+				fail(column, err)
+			}
+			switch column {
+			case "oldPrice":
+				p.OldPrice = &n
+			case "unitCount":
+				p.UnitCount = &n
+			default:
+				p.SetDiscountPercent = &n
+			}
+		case "inStock":
+			b, err := parseImportBool(v)
+			if err != nil {
+				fail(column, err)
+			}
+			p.InStock = b
+		case "tags":
+			p.Tags = splitList(v)
+		case "includes":
+			items, err := s.parseIncludes(v)
+			if err != nil {
+				fail(column, err)
+			}
+			p.Includes = items
+		case "status":
+			p.Status = models.ProductStatus(v)
+		case "publishAt", "unpublishAt":
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				fail(column, errors.New("expected RFC 3339 time, e.g. 2025-09-01T10:00:00+03:00"))
+			}
+			if column == "publishAt" {
+				p.PublishAt = &t
+			} else {
+				p.UnpublishAt = &t
+			}
+		}
+	}
+	if len(attributes) > 0 {
+		p.Attributes = attributes
+	}
+	return errors.Join(errs...)
+}
+
+// parseIncludes разбирает состав набора вида "slug-stula:4, slug-stola:1"
+func (s *productService) parseIncludes(v string) ([]models.IncludeItem, error) {
+	var items []models.IncludeItem
+	for _, part := range splitList(v) {
+		slug, qty, found := strings.Cut(part, ":")
+		quantity := 1
+		if found {
+			n, err := strconv.Atoi(strings.TrimSpace(qty))
+			if err != nil {
+				return nil, fmt.Errorf("invalid quantity in %q", part)
+			}
+			quantity = n
+		}
+
+		component, err := s.repo.GetBySlug(strings.TrimSpace(slug))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("product %s not found", slug)
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, models.IncludeItem{ProductID: component.ID, Quantity: quantity})
+	}
+	return items, nil
+}
+
+// checkImportColumns отклоняет файл с колонками, которые не соответствуют полям товара,
+// чтобы опечатка в заголовке не приводила к молча пропущенным данным
+func checkImportColumns(rows []ImportRow) error {
+	unknown := make(map[string]bool)
+	for _, row := range rows {
+		for column := range row.Values {
+			if !importColumns[column] && !strings.HasPrefix(column, attrColumnPrefix) {
+				unknown[column] = true
+			}
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(unknown))
+	for column := range unknown {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: %s", ErrInvalidImportColumns, strings.Join(names, ", "))
+}
+
+// parseImportInt разбирает целое число, допуская пробелы-разделители разрядов ("12 500")
+func parseImportInt(v string) (int, error) {
+	v = strings.NewReplacer(" ", "", "\u00a0", "").Replace(v)
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("expected integer, got %q", v)
+	}
+	return n, nil
+}
+
+func parseImportBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "true", "1", "yes", "да":
+		return true, nil
+	case "false", "0", "no", "нет":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected boolean, got %q", v)
+}
+
+// parseAttributeValue приводит значение атрибута к числу или логическому значению, если это возможно
+func parseAttributeValue(v string) interface{} {
+	if n, err := strconv.ParseFloat(v, 64); err == nil {
+		return n
+	}
+	if b, err := strconv.ParseBool(v); err == nil {
+		return b
+	}
+	return v
+}
+
+// splitList разбирает список через запятую или точку с запятой
+func splitList(v string) []string {
+	parts := strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' })
+	items := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
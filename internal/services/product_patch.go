@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/pkg/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Типы патчей, которые принимает PATCH /products/{slug}
+const (
+	MergePatchType = "application/merge-patch+json" // RFC 7396
+	JSONPatchType  = "application/json-patch+json"  // RFC 6902
+)
+
+var (
+	ErrUnsupportedPatch = errors.New("unsupported patch type")
+	ErrInvalidPatch     = errors.New("invalid patch")
+)
+
+// Patch — документ частичного изменения товара
+type Patch struct {
+	Type string // MergePatchType или JSONPatchType
+	Body []byte
+}
+
+// readOnlyFields — поля представления товара, которые нельзя менять патчем
+var readOnlyFields = map[string]bool{
+	"id":                true,
+	"createdAt":         true,
+	"updatedAt":         true,
+	"deletedAt":         true,
+	"version":           true,
+	"images":            true,
+	"variants":          true,
+	"priceRange":        true,
+	"lowestPrice30Days": true,
+	"promotions":        true,
+}
+
+// Patch применяет патч к сохранённому товару, проверяет результат и сохраняет только изменившиеся поля.
+// Ненулевой version — версия, которую видел клиент (If-Match).
+func (s *productService) Patch(ctx context.Context, slug string, version int, patch Patch) (*models.Product, error) {
+	existing, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := json.Marshal(existing)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := applyPatch(original, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	var p models.Product
+	if err := json.Unmarshal(patched, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	if err := checkReadOnly(existing, &p); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateStruct(p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	// новое название категории без нового categoryId означает смену категории по названию или slug
+	if p.Category != existing.Category && p.CategoryID == existing.CategoryID {
+		p.CategoryID = ""
+	}
+
+	if err := s.prepareUpdate(existing, &p); err != nil {
+		return nil, err
+	}
+
+	fields := changedFields(existing, &p)
+	if len(fields) == 0 {
+		return existing, nil
+	}
+
+	p.Version = version
+	err = s.inTx(ctx, func(tx *productService) error {
+		if err := tx.repo.Patch(existing.Slug, &p, fields); err != nil {
+			return err
+		}
+		return tx.afterUpdate(ctx, existing, &p, models.RevisionUpdate, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func applyPatch(original []byte, patch Patch) ([]byte, error) {
+	switch patch.Type {
+	case MergePatchType:
+		patched, err := jsonpatch.MergePatch(original, patch.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return patched, nil
+	case JSONPatchType:
+		ops, err := jsonpatch.DecodePatch(patch.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		patched, err := ops.Apply(original)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return patched, nil
+	default:
+		return nil, ErrUnsupportedPatch
+	}
+}
+
+// checkReadOnly запрещает менять служебные и управляемые отдельно поля
+func checkReadOnly(existing, p *models.Product) error {
+	before, after := productFields(existing), productFields(p)
+	for _, key := range sortedFieldKeys(readOnlyFields) {
+		if !reflect.DeepEqual(before[key], after[key]) {
+			return fmt.Errorf("%w: field %q is read-only", ErrInvalidPatch, key)
+		}
+	}
+	return nil
+}
+
+// changedFields возвращает изменённые поля товара (JSON-имена), кроме служебных
+func changedFields(existing, p *models.Product) []string {
+	before, after := productFields(existing), productFields(p)
+
+	var fields []string
+	for key := range mergeKeys(before, after) {
+		if readOnlyFields[key] {
+			continue
+		}
+		if !reflect.DeepEqual(before[key], after[key]) {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func mergeKeys(a, b map[string]interface{}) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+func sortedFieldKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
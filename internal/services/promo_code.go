@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PromoCodeService управляет промокодами, считает по ним скидку и применяет их
+type PromoCodeService interface {
+	Create(ctx context.Context, c *models.PromoCode) error
+	GetByID(ctx context.Context, id string) (*models.PromoCode, error)
+	GetAll(ctx context.Context) ([]*models.PromoCode, error)
+	Update(ctx context.Context, id string, c *models.PromoCode) error
+	Delete(ctx context.Context, id string) error
+	// Quote проверяет промокод для позиций заказа и считает суммы со скидкой.
+	// userID может быть пустым (гость): тогда лимит на покупателя не проверяется.
+	Quote(ctx context.Context, code, userID string, items []models.OrderItemInput) (*models.PromoCodeQuote, error)
+}
+
+var (
+	ErrPromoCodeNotFound      = errors.New("promo code not found")
+	ErrPromoCodeNotStarted    = errors.New("promo code is not active yet")
+	ErrPromoCodeExpired       = errors.New("promo code has expired")
+	ErrPromoCodeExhausted     = errors.New("promo code usage limit reached")
+	ErrPromoCodeUserLimit     = errors.New("promo code has already been used the maximum number of times")
+	ErrPromoCodeMinOrder      = errors.New("order amount is below the promo code minimum")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to any item in the order")
+	ErrInvalidPromoCode       = errors.New("invalid promo code")
+	ErrInvalidOrderItems      = errors.New("invalid order items")
+	ErrPromoCodeExists        = errors.New("promo code already exists")
+)
+
+type promoCodeService struct {
+	repo       repository.PromoCodeRepository
+	products   repository.ProductRepository
+	categories repository.CategoryRepository
+	promotions PromotionService
+}
+
+func NewPromoCodeService(
+	r repository.PromoCodeRepository,
+	products repository.ProductRepository,
+	c repository.CategoryRepository,
+	promo PromotionService,
+) PromoCodeService {
+	return &promoCodeService{repo: r, products: products, categories: c, promotions: promo}
+}
+
+func (s *promoCodeService) Create(ctx context.Context, c *models.PromoCode) error {
+	if err := checkPromoCode(c); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	c.ID = uuid.NewString()
+	c.UsedCount = 0
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	err := s.repo.Create(ctx, c)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return ErrPromoCodeExists
+	}
+	return err
+}
+
+func (s *promoCodeService) GetByID(ctx context.Context, id string) (*models.PromoCode, error) {
+	c, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPromoCodeNotFound
+	}
+	return c, err
+}
+
+func (s *promoCodeService) GetAll(ctx context.Context) ([]*models.PromoCode, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *promoCodeService) Update(ctx context.Context, id string, c *models.PromoCode) error {
+	existing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkPromoCode(c); err != nil {
+		return err
+	}
+
+	c.ID = existing.ID
+	c.UsedCount = existing.UsedCount
+	c.CreatedAt = existing.CreatedAt
+	c.UpdatedAt = time.Now().UTC()
+	err = s.repo.Update(ctx, c)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrPromoCodeNotFound
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return ErrPromoCodeExists
+	}
+	return err
+}
+
+func (s *promoCodeService) Delete(ctx context.Context, id string) error {
+	err := s.repo.Delete(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPromoCodeNotFound
+	}
+	return err
+}
+
+func (s *promoCodeService) Quote(ctx context.Context, code, userID string, items []models.OrderItemInput) (*models.PromoCodeQuote, error) {
+	_, quote, err := s.quote(ctx, code, userID, items, time.Now().UTC())
+	return quote, err
+}
+
+// redemptionError переводит ошибки записи применения промокода в ошибки сервиса
+func redemptionError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrUsageLimitReached):
+		return ErrPromoCodeExhausted
+	case errors.Is(err, repository.ErrUserUsageLimitReached):
+		return ErrPromoCodeUserLimit
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrPromoCodeNotFound
+	}
+	return err
+}
+
+// quote проверяет действие промокода и считает заказ в момент now
+func (s *promoCodeService) quote(ctx context.Context, code, userID string, items []models.OrderItemInput, now time.Time) (*models.PromoCode, *models.PromoCodeQuote, error) {
+	c, err := s.repo.GetByCode(ctx, strings.TrimSpace(code))
+	if errors.Is(err, pgx.ErrNoRows) || err == nil && !c.Enabled {
+		return nil, nil, ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case c.StartsAt != nil && c.StartsAt.After(now):
+		return nil, nil, ErrPromoCodeNotStarted
+	case c.ExpiresAt != nil && !c.ExpiresAt.After(now):
+		return nil, nil, ErrPromoCodeExpired
+	case c.UsageLimit != nil && c.UsedCount >= *c.UsageLimit:
+		return nil, nil, ErrPromoCodeExhausted
+	}
+	if userID != "" && c.PerUserLimit != nil {
+		n, err := s.repo.CountRedemptions(ctx, c.ID, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if n >= *c.PerUserLimit {
+			return nil, nil, ErrPromoCodeUserLimit
+		}
+	}
+
+	quote, err := s.priceItems(ctx, c, items, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if quote.Subtotal < c.MinOrderAmount {
+		return nil, nil, fmt.Errorf("%w: minimum is %d", ErrPromoCodeMinOrder, c.MinOrderAmount)
+	}
+	if quote.EligibleSubtotal == 0 {
+		return nil, nil, ErrPromoCodeNotApplicable
+	}
+
+	applyPromoCodeDiscount(c, quote)
+	return c, quote, nil
+}
+
+// priceItems загружает товары заказа по ценам витрины (с акциями; у вариантов — цена варианта)
+// и отмечает позиции, на которые действует промокод. Одинаковые товары объединяются в одну позицию.
+func (s *promoCodeService) priceItems(ctx context.Context, c *models.PromoCode, items []models.OrderItemInput, now time.Time) (*models.PromoCodeQuote, error) {
+	type lineKey struct{ productID, variantID string }
+	quantities := make(map[lineKey]int)
+	var keys []lineKey
+	var ids []string
+	for _, item := range items {
+		key := lineKey{productID: item.ProductID}
+		if item.VariantID != nil {
+			key.variantID = *item.VariantID
+		}
+		if _, ok := quantities[key]; !ok {
+			keys = append(keys, key)
+			ids = append(ids, item.ProductID)
+		}
+		quantities[key] += item.Quantity
+	}
+
+	products, err := s.products.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Product, len(products))
+	for _, p := range products {
+		if p.Visible(now) {
+			byID[p.ID] = p
+		}
+	}
+	var missing []string
+	for _, key := range keys {
+		p := byID[key.productID]
+		if p == nil || key.variantID != "" && findVariant(p, key.variantID) == nil {
+			missing = append(missing, key.productID)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: products not available: %s", ErrInvalidOrderItems, strings.Join(missing, ", "))
+	}
+	if err := s.promotions.Apply(ctx, products...); err != nil {
+		return nil, err
+	}
+
+	var parents map[string]string
+	if len(c.CategoryIDs) > 0 {
+		if parents, err = categoryParents(ctx, s.categories); err != nil {
+			return nil, err
+		}
+	}
+
+	quote := &models.PromoCodeQuote{PromoCodeID: c.ID, Code: c.Code, Items: make([]models.PromoCodeQuoteItem, 0, len(keys))}
+	for _, key := range keys {
+		p := byID[key.productID]
+		item := models.PromoCodeQuoteItem{
+			ProductID: p.ID,
+			Title:     p.Title,
+			Quantity:  quantities[key],
+			Price:     p.Price,
+			Eligible:  promoCodeApplies(c, p, parents),
+		}
+		if key.variantID != "" {
+			item.VariantID = &key.variantID
+			if v := findVariant(p, key.variantID); v.Price != nil {
+				item.Price = *v.Price
+			}
+		}
+		item.Total = item.Price * item.Quantity
+		quote.Subtotal += item.Total
+		if item.Eligible {
+			quote.EligibleSubtotal += item.Total
+		}
+		quote.Items = append(quote.Items, item)
+	}
+	return quote, nil
+}
+
+func checkPromoCode(c *models.PromoCode) error {
+	c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+	if c.Code == "" {
+		return errors.Join(ErrInvalidPromoCode, errors.New("code is required"))
+	}
+	if c.DiscountType == models.DiscountPercent && c.DiscountValue > 100 {
+		return errors.Join(ErrInvalidPromoCode, errors.New("percent discount cannot exceed 100"))
+	}
+	if c.StartsAt != nil && c.ExpiresAt != nil && !c.ExpiresAt.After(*c.StartsAt) {
+		return errors.Join(ErrInvalidPromoCode, errors.New("expiresAt must be later than startsAt"))
+	}
+	return nil
+}
+
+// promoCodeApplies проверяет, действует ли промокод на товар: по списку товаров или категориям с подкатегориями
+func promoCodeApplies(c *models.PromoCode, p *models.Product, parents map[string]string) bool {
+	if len(c.CategoryIDs) == 0 && len(c.ProductIDs) == 0 {
+		return true
+	}
+	for _, id := range c.ProductIDs {
+		if id == p.ID {
+			return true
+		}
+	}
+	return inCategories(p.CategoryID, c.CategoryIDs, parents)
+}
+
+// applyPromoCodeDiscount считает скидку от суммы подходящих позиций и распределяет её по ним
+// пропорционально стоимости; остаток от округления достаётся последней подходящей позиции
+func applyPromoCodeDiscount(c *models.PromoCode, quote *models.PromoCodeQuote) {
+	switch c.DiscountType {
+	case models.DiscountPercent:
+		quote.Discount = quote.EligibleSubtotal * c.DiscountValue / 100
+	case models.DiscountFixed:
+		quote.Discount = min(c.DiscountValue, quote.EligibleSubtotal)
+	}
+
+	last, rest := -1, quote.Discount
+	for i := range quote.Items {
+		item := &quote.Items[i]
+		if !item.Eligible {
+			continue
+		}
+		item.Discount = quote.Discount * item.Total / quote.EligibleSubtotal
+		rest -= item.Discount
+		last = i
+	}
+	if last >= 0 {
+		quote.Items[last].Discount += rest
+	}
+	quote.Total = quote.Subtotal - quote.Discount
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PromotionService управляет акциями и пересчитывает по ним цены товаров на витрине
+type PromotionService interface {
+	Create(ctx context.Context, p *models.Promotion) error
+	GetByID(ctx context.Context, id string) (*models.Promotion, error)
+	GetAll(ctx context.Context) ([]*models.Promotion, error)
+	Update(ctx context.Context, id string, p *models.Promotion) error
+	Delete(ctx context.Context, id string) error
+	// Preview возвращает витринные товары, на которые подействует акция p, с ценой до и после неё.
+	// Учитывается только сама акция, без других действующих акций и периода действия.
+	Preview(ctx context.Context, p *models.Promotion) ([]models.PromotionPreviewItem, error)
+	// Apply применяет к товарам действующие акции: Price становится ценой со скидкой,
+	// а OldPrice — ценой без неё (если введённая вручную старая цена меньше)
+	Apply(ctx context.Context, products ...*models.Product) error
+}
+
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidPromotion  = errors.New("invalid promotion")
+)
+
+type promotionService struct {
+	repo       repository.PromotionRepository
+	products   repository.ProductRepository
+	categories repository.CategoryRepository
+}
+
+func NewPromotionService(r repository.PromotionRepository, products repository.ProductRepository, c repository.CategoryRepository) PromotionService {
+	return &promotionService{repo: r, products: products, categories: c}
+}
+
+func (s *promotionService) Create(ctx context.Context, p *models.Promotion) error {
+	if err := checkPromotion(p); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	p.ID = uuid.NewString()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	return s.repo.Create(ctx, p)
+}
+
+func (s *promotionService) GetByID(ctx context.Context, id string) (*models.Promotion, error) {
+	p, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPromotionNotFound
+	}
+	return p, err
+}
+
+func (s *promotionService) GetAll(ctx context.Context) ([]*models.Promotion, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *promotionService) Update(ctx context.Context, id string, p *models.Promotion) error {
+	existing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkPromotion(p); err != nil {
+		return err
+	}
+
+	p.ID = existing.ID
+	p.CreatedAt = existing.CreatedAt
+	p.UpdatedAt = time.Now().UTC()
+	err = s.repo.Update(ctx, p)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPromotionNotFound
+	}
+	return err
+}
+
+func (s *promotionService) Delete(ctx context.Context, id string) error {
+	err := s.repo.Delete(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPromotionNotFound
+	}
+	return err
+}
+
+func (s *promotionService) Preview(ctx context.Context, p *models.Promotion) ([]models.PromotionPreviewItem, error) {
+	if err := checkPromotion(p); err != nil {
+		return nil, err
+	}
+	tree, err := s.categoryTree(ctx, []*models.Promotion{p})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []models.PromotionPreviewItem{}
+	err = eachProduct(ctx, s.products.GetAll, func(product *models.Product) error {
+		if !promotionMatches(p, product, tree) {
+			return nil
+		}
+		items = append(items, models.PromotionPreviewItem{
+			ProductID:     product.ID,
+			Slug:          product.Slug,
+			Title:         product.Title,
+			Type:          product.Type,
+			Price:         product.Price,
+			DiscountPrice: discountPrice(product.Price, p),
+		})
+		return nil
+	})
+	return items, err
+}
+
+func (s *promotionService) Apply(ctx context.Context, products ...*models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	active, err := s.repo.GetActive(ctx, time.Now().UTC())
+	if err != nil || len(active) == 0 {
+		return err
+	}
+	tree, err := s.categoryTree(ctx, active)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		var matched []*models.Promotion
+		for _, promo := range active {
+			if promotionMatches(promo, p, tree) {
+				matched = append(matched, promo)
+			}
+		}
+		applyPromotions(p, selectPromotions(matched))
+	}
+	return nil
+}
+
+// categoryTree загружает родителей категорий, если хотя бы одна акция ограничена категориями;
+// иначе возвращает nil
+func (s *promotionService) categoryTree(ctx context.Context, promotions []*models.Promotion) (map[string]string, error) {
+	for _, p := range promotions {
+		if len(p.CategoryIDs) > 0 {
+			return categoryParents(ctx, s.categories)
+		}
+	}
+	return nil, nil
+}
+
+// categoryParents возвращает родительскую категорию для каждой вложенной категории
+func categoryParents(ctx context.Context, categories repository.CategoryRepository) (map[string]string, error) {
+	all, err := categories.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	parents := make(map[string]string, len(all))
+	for _, c := range all {
+		if c.ParentID != nil {
+			parents[c.ID] = *c.ParentID
+		}
+	}
+	return parents, nil
+}
+
+// inCategories проверяет, лежит ли категория categoryID в одной из targets или в её подкатегориях
+func inCategories(categoryID string, targets []string, parents map[string]string) bool {
+	for _, target := range targets {
+		seen := make(map[string]bool)
+		for c := categoryID; c != "" && !seen[c]; c = parents[c] {
+			if c == target {
+				return true
+			}
+			seen[c] = true
+		}
+	}
+	return false
+}
+
+func checkPromotion(p *models.Promotion) error {
+	if p.DiscountType == models.DiscountPercent && p.DiscountValue > 100 {
+		return errors.Join(ErrInvalidPromotion, errors.New("percent discount cannot exceed 100"))
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return errors.Join(ErrInvalidPromotion, errors.New("endsAt must be later than startsAt"))
+	}
+	return nil
+}
+
+// promotionMatches проверяет условия акции: товар подходит под любое из условий
+// (категория с подкатегориями, тег, сам товар) и под тип, если он задан.
+// parents — родительская категория для каждой категории.
+func promotionMatches(promo *models.Promotion, p *models.Product, parents map[string]string) bool {
+	if promo.ProductType != nil && *promo.ProductType != p.Type {
+		return false
+	}
+	if len(promo.CategoryIDs) == 0 && len(promo.Tags) == 0 && len(promo.ProductIDs) == 0 {
+		return true
+	}
+
+	for _, id := range promo.ProductIDs {
+		if id == p.ID {
+			return true
+		}
+	}
+	for _, tag := range promo.Tags {
+		for _, t := range p.Tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	return inCategories(p.CategoryID, promo.CategoryIDs, parents)
+}
+
+// selectPromotions выбирает из подходящих акций (упорядоченных по приоритету) применяемые:
+// если первая акция суммируемая — все суммируемые, иначе только она
+func selectPromotions(matched []*models.Promotion) []*models.Promotion {
+	if len(matched) == 0 || !matched[0].Stackable {
+		return matched[:min(len(matched), 1)]
+	}
+	var stack []*models.Promotion
+	for _, p := range matched {
+		if p.Stackable {
+			stack = append(stack, p)
+		}
+	}
+	return stack
+}
+
+// applyPromotions уменьшает цену товара, цены вариантов и диапазон цен на скидки акций по очереди
+func applyPromotions(p *models.Product, promotions []*models.Promotion) {
+	if len(promotions) == 0 {
+		return
+	}
+
+	discount := func(price int) int {
+		for _, promo := range promotions {
+			price = discountPrice(price, promo)
+		}
+		return price
+	}
+
+	p.Price, p.OldPrice = discount(p.Price), strikePrice(p.Price, p.OldPrice)
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		if v.Price != nil {
+			price := discount(*v.Price)
+			v.Price, v.OldPrice = &price, strikePrice(*v.Price, v.OldPrice)
+		}
+	}
+	if p.PriceRange != nil {
+		p.PriceRange = &models.PriceRange{Min: discount(p.PriceRange.Min), Max: discount(p.PriceRange.Max)}
+	}
+
+	for _, promo := range promotions {
+		p.Promotions = append(p.Promotions, models.AppliedPromotion{ID: promo.ID, Name: promo.Name})
+	}
+}
+
+// strikePrice — зачёркнутая цена после применения акции: большая из цены до скидки и введённой вручную
+func strikePrice(price int, oldPrice *int) *int {
+	if oldPrice != nil && *oldPrice > price {
+		return oldPrice
+	}
+	return &price
+}
+
+// discountPrice применяет скидку одной акции; цена не опускается ниже нуля
+func discountPrice(price int, promo *models.Promotion) int {
+	switch promo.DiscountType {
+	case models.DiscountPercent:
+		price -= price * promo.DiscountValue / 100
+	case models.DiscountFixed:
+		price -= promo.DiscountValue
+	}
+	return max(price, 0)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/metrics"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PublishScheduler периодически применяет расписание публикации товаров:
+// черновики с наступившим publishAt публикуются, товары с наступившим unpublishAt уходят в архив
+type PublishScheduler struct {
+	repo     repository.ProductRepository
+	logger   logger.Logger
+	interval time.Duration
+}
+
+func NewPublishScheduler(r repository.ProductRepository, l logger.Logger, interval time.Duration) *PublishScheduler {
+	return &PublishScheduler{repo: r, logger: l, interval: interval}
+}
+
+// Run выполняет проверку сразу и затем каждые interval, пока не отменён ctx
+func (s *PublishScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("publish scheduler started", zap.Duration("interval", s.interval))
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("publish scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PublishScheduler) tick(ctx context.Context) {
+	transitions, err := s.repo.ApplySchedule(ctx, time.Now().UTC())
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("failed to apply publish schedule", zap.Error(err))
+		}
+		return
+	}
+
+	for _, t := range transitions {
+		metrics.ProductStatusTransitions.WithLabelValues(string(t.From), string(t.To)).Inc()
+		s.logger.Info("product status changed by schedule",
+			zap.String("id", t.ProductID),
+			zap.String("slug", t.Slug),
+			zap.String("from", string(t.From)),
+			zap.String("to", string(t.To)),
+		)
+	}
+}
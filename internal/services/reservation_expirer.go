@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/metrics"
+	"dozenChairs/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ReservationExpirer периодически снимает истёкшие резервы товаров и отменяет неоплаченные заказы с ними
+type ReservationExpirer struct {
+	orders   OrderService
+	logger   logger.Logger
+	interval time.Duration
+}
+
+func NewReservationExpirer(orders OrderService, l logger.Logger, interval time.Duration) *ReservationExpirer {
+	return &ReservationExpirer{orders: orders, logger: l, interval: interval}
+}
+
+// Run выполняет проверку сразу и затем каждые interval, пока не отменён ctx
+func (e *ReservationExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := e.orders.ExpireReservations(ctx, time.Now().UTC())
+		if n > 0 {
+			metrics.StockReservationsExpired.Add(float64(n))
+			e.logger.Info("expired stock reservations released", zap.Int("orders", n))
+		}
+		if err != nil && ctx.Err() == nil {
+			e.logger.Error("failed to release expired stock reservations", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
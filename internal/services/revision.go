@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/middlewares"
+	"dozenChairs/internal/models"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// revisionIgnoredFields — поля, которые не попадают в diff ревизии: служебные
+// или управляемые отдельно (изображения, варианты)
+var revisionIgnoredFields = map[string]bool{
+	"createdAt":  true,
+	"updatedAt":  true,
+	"deletedAt":  true,
+	"images":     true,
+	"variants":   true,
+	"priceRange": true,
+}
+
+// actorID возвращает ID пользователя, выполняющего запрос (из middlewares.RequireAuth)
+func actorID(ctx context.Context) *string {
+	if id, ok := ctx.Value(middlewares.UserID()).(string); ok && id != "" {
+		return &id
+	}
+	return nil
+}
+
+func newRevision(ctx context.Context, productID string, action models.RevisionAction, changes map[string]models.FieldChange) *models.ProductRevision {
+	if changes == nil {
+		changes = map[string]models.FieldChange{}
+	}
+	return &models.ProductRevision{
+		ID:        uuid.NewString(),
+		ProductID: productID,
+		Action:    action,
+		UserID:    actorID(ctx),
+		Changes:   changes,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// diffProducts сравнивает JSON-представления товаров и возвращает изменённые поля.
+// old == nil означает создание: в diff попадают все заполненные поля.
+func diffProducts(old, new *models.Product) map[string]models.FieldChange {
+	before, after := productFields(old), productFields(new)
+
+	changes := map[string]models.FieldChange{}
+	for key, v := range after {
+		if revisionIgnoredFields[key] {
+			continue
+		}
+		if prev, ok := before[key]; !ok || !reflect.DeepEqual(prev, v) {
+			changes[key] = models.FieldChange{Old: before[key], New: v}
+		}
+	}
+	for key, prev := range before {
+		if _, ok := after[key]; !ok && !revisionIgnoredFields[key] {
+			changes[key] = models.FieldChange{Old: prev, New: nil}
+		}
+	}
+	return changes
+}
+
+func productFields(p *models.Product) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if p == nil {
+		return fields
+	}
+	raw, _ := json.Marshal(p)
+	_ = json.Unmarshal(raw, &fields)
+	return fields
+}
+
+// revisionSnapshot — копия товара без изображений, вариантов и служебных агрегатов
+func revisionSnapshot(p *models.Product) *models.Product {
+	if p == nil {
+		return nil
+	}
+	snapshot := *p
+	snapshot.Images, snapshot.Variants, snapshot.PriceRange, snapshot.LowestPrice30Days = nil, nil, nil, nil
+	snapshot.Promotions = nil
+	return &snapshot
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/config"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SitemapService генерирует sitemap.xml витрины и robots.txt.
+// Sitemap хранится в файлах и перегенерируется, когда меняются товары, изображения или категории.
+type SitemapService interface {
+	// Open возвращает файл sitemap.xml (индекс или единственный файл) либо sitemap-N.xml.
+	// Файл закрывает вызывающий.
+	Open(ctx context.Context, name string) (*os.File, error)
+	RobotsTxt() ([]byte, error)
+}
+
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+const (
+	SitemapName = "sitemap.xml"
+	// ограничения протокола sitemaps.org на один файл
+	sitemapMaxURLs  = 50000
+	sitemapMaxBytes = 50<<20 - 64<<10 // запас на закрывающие теги и буфер энкодера
+	// sitemapMaxImages — сколько изображений можно указать для одной страницы
+	sitemapMaxImages = 1000
+
+	sitemapNamespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	sitemapImageNamespace = "http://www.google.com/schemas/sitemap-image/1.1"
+)
+
+var sitemapPartName = regexp.MustCompile(`^sitemap-[0-9]+\.xml$`)
+
+type sitemapURL struct {
+	XMLName xml.Name       `xml:"url"`
+	Loc     string         `xml:"loc"`
+	LastMod string         `xml:"lastmod,omitempty"`
+	Images  []sitemapImage `xml:"image:image"`
+}
+
+type sitemapImage struct {
+	Loc string `xml:"image:loc"`
+}
+
+type sitemapService struct {
+	products       repository.ProductRepository
+	categories     repository.CategoryRepository
+	shop           config.ShopConfig
+	dir            string
+	ttl            time.Duration
+	robotsTxtPath  string
+	robotsDisallow []string
+
+	mu sync.Mutex
+}
+
+func NewSitemapService(
+	products repository.ProductRepository,
+	categories repository.CategoryRepository,
+	shop config.ShopConfig,
+	dir string,
+	ttl time.Duration,
+	robotsTxtPath string,
+	robotsDisallow []string,
+) SitemapService {
+	return &sitemapService{
+		products:       products,
+		categories:     categories,
+		shop:           shop,
+		dir:            dir,
+		ttl:            ttl,
+		robotsTxtPath:  robotsTxtPath,
+		robotsDisallow: robotsDisallow,
+	}
+}
+
+func (s *sitemapService) Open(ctx context.Context, name string) (*os.File, error) {
+	if name != SitemapName && !sitemapPartName.MatchString(name) {
+		return nil, ErrSitemapNotFound
+	}
+
+	s.mu.Lock()
+	err := s.refresh(ctx)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSitemapNotFound
+	}
+	return f, err
+}
+
+// RobotsTxt возвращает содержимое файла robotsTxtPath (читается при каждом запросе,
+// чтобы правки применялись без перезапуска) или robots.txt по умолчанию.
+// Если в файле нет директивы Sitemap, она добавляется.
+func (s *sitemapService) RobotsTxt() ([]byte, error) {
+	sitemapLine := "Sitemap: " + s.shop.BaseURL + "/" + SitemapName + "\n"
+
+	if s.robotsTxtPath != "" {
+		content, err := os.ReadFile(s.robotsTxtPath)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(strings.ToLower(string(content)), "sitemap:") {
+			return content, nil
+		}
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			content = append(content, '\n')
+		}
+		return append(content, sitemapLine...), nil
+	}
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	for _, path := range s.robotsDisallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\n" + sitemapLine)
+	return []byte(b.String()), nil
+}
+
+// refresh перегенерирует sitemap, если его нет, он старше ttl или витрина изменилась после генерации
+func (s *sitemapService) refresh(ctx context.Context) error {
+	info, err := os.Stat(filepath.Join(s.dir, SitemapName))
+	if err == nil && time.Since(info.ModTime()) <= s.ttl {
+		modified, err := s.products.LastModified(ctx)
+		if err != nil {
+			return err
+		}
+		if !modified.After(info.ModTime()) {
+			return nil
+		}
+	}
+	return s.generate(ctx)
+}
+
+// generate пишет страницы витрины потоково в файлы по sitemapMaxURLs адресов.
+// Если файл получился один, он становится sitemap.xml, иначе sitemap.xml — индекс файлов sitemap-N.xml.
+func (s *sitemapService) generate(ctx context.Context) error {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	w := &sitemapWriter{dir: s.dir}
+	defer w.cleanup()
+
+	if err := w.add(sitemapURL{Loc: s.shop.BaseURL + "/"}); err != nil {
+		return err
+	}
+
+	categories, err := s.categories.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		err := w.add(sitemapURL{Loc: s.shop.BaseURL + "/categories/" + c.Slug, LastMod: sitemapTime(c.UpdatedAt)})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = eachProduct(ctx, s.products.GetAll, func(p *models.Product) error {
+		u := sitemapURL{Loc: productURL(s.shop.BaseURL, p), LastMod: sitemapTime(p.UpdatedAt)}
+		for _, img := range p.Images {
+			if len(u.Images) == sitemapMaxImages {
+				break
+			}
+			u.Images = append(u.Images, sitemapImage{Loc: absoluteURL(s.shop.MediaURL, img.URL)})
+		}
+		return w.add(u)
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	return s.publish(w.files)
+}
+
+// publish переносит временные файлы на место: сначала части, потом индекс,
+// чтобы индекс никогда не ссылался на несуществующий файл. Лишние части от прошлой генерации удаляются.
+func (s *sitemapService) publish(files []string) error {
+	if len(files) == 1 {
+		if err := os.Rename(files[0], filepath.Join(s.dir, SitemapName)); err != nil {
+			return err
+		}
+		return s.removeParts(0)
+	}
+
+	parts := make([]string, len(files))
+	for i, tmp := range files {
+		parts[i] = fmt.Sprintf("sitemap-%d.xml", i+1)
+		if err := os.Rename(tmp, filepath.Join(s.dir, parts[i])); err != nil {
+			return err
+		}
+	}
+	if err := s.writeIndex(parts); err != nil {
+		return err
+	}
+	return s.removeParts(len(parts))
+}
+
+func (s *sitemapService) writeIndex(parts []string) error {
+	tmp, err := os.CreateTemp(s.dir, SitemapName+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	type sitemapRef struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	}
+	index := struct {
+		XMLName  xml.Name     `xml:"sitemapindex"`
+		XMLNS    string       `xml:"xmlns,attr"`
+		Sitemaps []sitemapRef `xml:"sitemap"`
+	}{XMLNS: sitemapNamespace}
+	now := sitemapTime(time.Now())
+	for _, part := range parts {
+		index.Sitemaps = append(index.Sitemaps, sitemapRef{Loc: s.shop.BaseURL + "/" + part, LastMod: now})
+	}
+
+	if _, err := io.WriteString(tmp, xml.Header); err != nil {
+		tmp.Close()
+		return err
+	}
+	enc := xml.NewEncoder(tmp)
+	enc.Indent("", "  ")
+	if err := enc.Encode(index); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, SitemapName))
+}
+
+// removeParts удаляет файлы sitemap-N.xml с N > keep
+func (s *sitemapService) removeParts(keep int) error {
+	for n := keep + 1; ; n++ {
+		err := os.Remove(filepath.Join(s.dir, fmt.Sprintf("sitemap-%d.xml", n)))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func sitemapTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// sitemapWriter пишет адреса во временные файлы, начиная новый по достижении
+// ограничений протокола на число адресов или размер файла
+type sitemapWriter struct {
+	dir   string
+	files []string
+
+	f    *os.File
+	bw   *bufio.Writer
+	cw   *countingWriter
+	enc  *xml.Encoder
+	urls int
+}
+
+var urlsetStart = xml.StartElement{
+	Name: xml.Name{Local: "urlset"},
+	Attr: []xml.Attr{
+		{Name: xml.Name{Local: "xmlns"}, Value: sitemapNamespace},
+		{Name: xml.Name{Local: "xmlns:image"}, Value: sitemapImageNamespace},
+	},
+}
+
+func (w *sitemapWriter) add(u sitemapURL) error {
+	if w.enc == nil || w.urls == sitemapMaxURLs || w.cw.n >= sitemapMaxBytes {
+		if err := w.next(); err != nil {
+			return err
+		}
+	}
+	w.urls++
+	return w.enc.Encode(u)
+}
+
+func (w *sitemapWriter) next() error {
+	if err := w.close(); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(w.dir, "sitemap.*.tmp")
+	if err != nil {
+		return err
+	}
+	w.files = append(w.files, f.Name())
+	w.f = f
+	w.bw = bufio.NewWriter(f)
+	w.cw = &countingWriter{w: w.bw}
+	w.enc = xml.NewEncoder(w.cw)
+	w.enc.Indent("", "  ")
+	w.urls = 0
+
+	if _, err := io.WriteString(w.cw, xml.Header); err != nil {
+		return err
+	}
+	return w.enc.EncodeToken(urlsetStart)
+}
+
+// close дописывает и закрывает текущий файл
+func (w *sitemapWriter) close() error {
+	if w.f == nil {
+		return nil
+	}
+	f := w.f
+	w.f = nil
+
+	if err := w.enc.EncodeToken(urlsetStart.End()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.enc.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// cleanup удаляет временные файлы, которые не удалось перенести на место
+func (w *sitemapWriter) cleanup() {
+	if w.f != nil {
+		w.f.Close()
+	}
+	for _, name := range w.files {
+		os.Remove(name)
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
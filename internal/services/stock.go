@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// StockService — складской учёт товаров: остатки по складам, журнал движений и наличие для покупателя
+type StockService interface {
+	// GetLevels возвращает остатки товара slug по складам: без варианта и по каждому варианту
+	GetLevels(ctx context.Context, slug string) ([]models.StockLevel, error)
+	// GetMovements возвращает журнал движений товара slug от новых к старым
+	GetMovements(ctx context.Context, slug string, filter repository.StockMovementFilter) ([]models.StockMovement, int, error)
+	// Record проводит ручное движение по товару slug (поступление, возврат, корректировку);
+	// автор движения берётся из контекста запроса. Если остатка не хватает — repository.ErrInsufficientStock.
+	Record(ctx context.Context, slug string, m *models.StockMovement) (*models.StockLevel, error)
+	// GetAvailability возвращает, на каких складах есть витринный товар slug (вариант variantID, если задан),
+	// где его можно забрать в городе city и за сколько дней его туда доставят
+	GetAvailability(ctx context.Context, slug, variantID, city string) (*models.ProductAvailability, error)
+}
+
+var (
+	ErrStockNotTracked      = errors.New("stock is not tracked for sets")
+	ErrStockVariantRequired = errors.New("variantId is required for products with variants")
+	ErrInvalidStockMovement = errors.New("invalid stock movement")
+)
+
+type stockService struct {
+	repo       repository.StockRepository
+	products   repository.ProductRepository
+	variants   repository.VariantRepository
+	warehouses repository.WarehouseRepository
+}
+
+func NewStockService(
+	r repository.StockRepository,
+	products repository.ProductRepository,
+	variants repository.VariantRepository,
+	warehouses repository.WarehouseRepository,
+) StockService {
+	return &stockService{repo: r, products: products, variants: variants, warehouses: warehouses}
+}
+
+func (s *stockService) GetLevels(ctx context.Context, slug string) ([]models.StockLevel, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetLevels(ctx, p.ID)
+}
+
+func (s *stockService) GetMovements(ctx context.Context, slug string, filter repository.StockMovementFilter) ([]models.StockMovement, int, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter.VariantID != "" && uuid.Validate(filter.VariantID) != nil {
+		return nil, 0, ErrVariantNotFound
+	}
+	if filter.WarehouseID != "" && uuid.Validate(filter.WarehouseID) != nil {
+		return nil, 0, ErrWarehouseNotFound
+	}
+	filter.ProductID = p.ID
+	return s.repo.GetMovements(ctx, filter)
+}
+
+func (s *stockService) Record(ctx context.Context, slug string, m *models.StockMovement) (*models.StockLevel, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+	if p.Type != models.TypeProduct {
+		// остаток набора складывается из остатков его компонентов
+		return nil, ErrStockNotTracked
+	}
+
+	switch m.Type {
+	case models.StockReceipt, models.StockReturn:
+		if m.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidStockMovement)
+		}
+	case models.StockAdjustment:
+		if m.Quantity == 0 {
+			return nil, fmt.Errorf("%w: quantity must not be zero", ErrInvalidStockMovement)
+		}
+	default:
+		// резервы, их снятие и продажи проводятся только заказами
+		return nil, fmt.Errorf("%w: type %q cannot be recorded manually", ErrInvalidStockMovement, m.Type)
+	}
+
+	variants, err := s.variants.GetByProductID(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case m.VariantID == nil && len(variants) > 0:
+		return nil, ErrStockVariantRequired
+	case m.VariantID != nil && !hasVariant(variants, *m.VariantID):
+		return nil, ErrVariantNotFound
+	}
+
+	_, err = s.warehouses.GetByID(ctx, m.WarehouseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWarehouseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.ProductID = p.ID
+	m.Reason = strings.TrimSpace(m.Reason)
+	m.OrderID = nil
+	m.ActorID = actorID(ctx)
+
+	return s.repo.Record(ctx, m)
+}
+
+func (s *stockService) GetAvailability(ctx context.Context, slug, variantID, city string) (*models.ProductAvailability, error) {
+	p, err := s.getProduct(slug)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Visible(time.Now()) {
+		return nil, ErrProductNotFound
+	}
+
+	city = strings.TrimSpace(city)
+	availability := &models.ProductAvailability{
+		ProductID:    p.ID,
+		City:         city,
+		InStock:      p.InStock,
+		Warehouses:   []models.WarehouseAvailability{},
+		PickupPoints: []models.WarehouseAvailability{},
+	}
+	if variantID != "" {
+		v := findVariant(p, variantID)
+		if v == nil {
+			return nil, ErrVariantNotFound
+		}
+		availability.VariantID = &v.ID
+		availability.InStock = v.InStock
+	}
+
+	// набор доступен на складе в количестве комплектов, которые собираются из его остатков
+	components := []models.IncludeItem{{ProductID: p.ID, Quantity: 1}}
+	if p.Type == models.TypeSet {
+		components = p.Includes
+	}
+	var units map[string]int
+	for _, c := range components {
+		levels, err := s.repo.GetLevels(ctx, c.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		available, tracked := make(map[string]int), false
+		for _, l := range levels {
+			switch {
+			case p.Type == models.TypeSet && l.VariantID != nil,
+				availability.VariantID != nil && (l.VariantID == nil || *l.VariantID != *availability.VariantID):
+				continue
+			}
+			tracked = true
+			available[l.WarehouseID] += l.Available
+		}
+		if !tracked {
+			// остаток компонента не учитывается и наличие не ограничивает
+			continue
+		}
+		availability.Tracked = true
+
+		if units == nil {
+			units = make(map[string]int, len(available))
+			for id, n := range available {
+				units[id] = n / c.Quantity
+			}
+			continue
+		}
+		for id, n := range units {
+			units[id] = min(n, available[id]/c.Quantity)
+		}
+	}
+	if !availability.Tracked {
+		return availability, nil
+	}
+
+	warehouses, err := s.warehouses.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	// склады города покупателя идут первыми — в этом порядке под заказ резервируется товар
+	sort.SliceStable(warehouses, func(i, j int) bool {
+		return models.SameCity(warehouses[i].City, city) && !models.SameCity(warehouses[j].City, city)
+	})
+
+	total := 0
+	for _, w := range warehouses {
+		if units[w.ID] <= 0 {
+			continue
+		}
+		total += units[w.ID]
+		wa := models.WarehouseAvailability{Warehouse: *w, Available: units[w.ID]}
+		if city != "" {
+			if days, ok := w.DeliveryDaysTo(city); ok {
+				wa.DeliveryDays = &days
+				if availability.DeliveryDays == nil || days < *availability.DeliveryDays {
+					availability.DeliveryDays = &days
+				}
+			}
+		}
+		if w.Pickup && (city == "" || models.SameCity(w.City, city)) {
+			availability.PickupPoints = append(availability.PickupPoints, wa)
+		}
+		availability.Warehouses = append(availability.Warehouses, wa)
+	}
+	availability.InStock = total > 0
+	return availability, nil
+}
+
+func (s *stockService) getProduct(slug string) (*models.Product, error) {
+	p, err := s.products.GetBySlug(slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProductNotFound
+	}
+	return p, err
+}
+
+func hasVariant(variants []models.ProductVariant, id string) bool {
+	for _, v := range variants {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/metrics"
+	"dozenChairs/internal/repository"
+	"dozenChairs/pkg/logger"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// uploadDir — каталог, в который сохраняются загруженные изображения
+const uploadDir = "uploads"
+
+// TrashPurger периодически окончательно удаляет товары, пролежавшие в корзине дольше retention,
+// вместе с записями и файлами их изображений
+type TrashPurger struct {
+	repo      repository.ProductRepository
+	logger    logger.Logger
+	retention time.Duration
+	interval  time.Duration
+}
+
+func NewTrashPurger(r repository.ProductRepository, l logger.Logger, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{repo: r, logger: l, retention: retention, interval: interval}
+}
+
+// Run выполняет очистку сразу и затем каждые interval, пока не отменён ctx
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Info("trash purger started",
+		zap.Duration("retention", p.retention),
+		zap.Duration("interval", p.interval),
+	)
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	res, err := p.repo.Purge(ctx, time.Now().UTC().Add(-p.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Error("failed to purge trash", zap.Error(err))
+		}
+		return
+	}
+	if len(res.ProductIDs) == 0 {
+		return
+	}
+
+	// файлы удаляются после фиксации транзакции: лишний файл безопаснее битой ссылки
+	for _, name := range res.Filenames {
+		err := os.Remove(filepath.Join(uploadDir, filepath.Base(name)))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			p.logger.Warn("failed to remove image file", zap.String("file", name), zap.Error(err))
+		}
+	}
+
+	metrics.ProductsPurged.Add(float64(len(res.ProductIDs)))
+	p.logger.Info("trash purged",
+		zap.Strings("ids", res.ProductIDs),
+		zap.Int("files", len(res.Filenames)),
+	)
+}
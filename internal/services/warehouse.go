@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"dozenChairs/internal/models"
+	"dozenChairs/internal/repository"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// WarehouseService управляет складами, шоурумами и пунктами самовывоза
+type WarehouseService interface {
+	Create(ctx context.Context, w *models.Warehouse) error
+	GetByID(ctx context.Context, id string) (*models.Warehouse, error)
+	// GetAll возвращает все склады, включая выключенные, в порядке резервирования
+	GetAll(ctx context.Context) ([]*models.Warehouse, error)
+	// GetPickupPoints возвращает работающие пункты самовывоза; city ограничивает их городом
+	GetPickupPoints(ctx context.Context, city string) ([]*models.Warehouse, error)
+	Update(ctx context.Context, id string, w *models.Warehouse) error
+	// Delete удаляет склад; склад с историей движений или заказами удалить нельзя (ErrWarehouseInUse)
+	Delete(ctx context.Context, id string) error
+}
+
+var (
+	ErrWarehouseNotFound   = errors.New("warehouse not found")
+	ErrWarehouseCodeExists = errors.New("warehouse code already exists")
+	ErrWarehouseInUse      = errors.New("warehouse has stock history or orders, deactivate it instead")
+)
+
+type warehouseService struct {
+	repo repository.WarehouseRepository
+}
+
+func NewWarehouseService(r repository.WarehouseRepository) WarehouseService {
+	return &warehouseService{repo: r}
+}
+
+func (s *warehouseService) Create(ctx context.Context, w *models.Warehouse) error {
+	normalizeWarehouse(w)
+	now := time.Now().UTC()
+	w.ID = uuid.NewString()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+
+	err := s.repo.Create(ctx, w)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return ErrWarehouseCodeExists
+	}
+	return err
+}
+
+func (s *warehouseService) GetByID(ctx context.Context, id string) (*models.Warehouse, error) {
+	if uuid.Validate(id) != nil {
+		return nil, ErrWarehouseNotFound
+	}
+	w, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrWarehouseNotFound
+	}
+	return w, err
+}
+
+func (s *warehouseService) GetAll(ctx context.Context) ([]*models.Warehouse, error) {
+	return s.repo.GetAll(ctx, false)
+}
+
+func (s *warehouseService) GetPickupPoints(ctx context.Context, city string) ([]*models.Warehouse, error) {
+	warehouses, err := s.repo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []*models.Warehouse{}
+	for _, w := range warehouses {
+		if w.Pickup && (strings.TrimSpace(city) == "" || models.SameCity(w.City, city)) {
+			points = append(points, w)
+		}
+	}
+	return points, nil
+}
+
+func (s *warehouseService) Update(ctx context.Context, id string, w *models.Warehouse) error {
+	existing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	normalizeWarehouse(w)
+	w.ID = existing.ID
+	w.CreatedAt = existing.CreatedAt
+	w.UpdatedAt = time.Now().UTC()
+
+	err = s.repo.Update(ctx, w)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrWarehouseNotFound
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return ErrWarehouseCodeExists
+	}
+	return err
+}
+
+func (s *warehouseService) Delete(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrWarehouseNotFound
+	}
+	err := s.repo.Delete(ctx, id)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return ErrWarehouseNotFound
+	case errors.Is(err, repository.ErrReferenced):
+		return ErrWarehouseInUse
+	}
+	return err
+}
+
+func normalizeWarehouse(w *models.Warehouse) {
+	w.Code = strings.ToLower(strings.TrimSpace(w.Code))
+	w.Name = strings.TrimSpace(w.Name)
+	w.City = strings.TrimSpace(w.City)
+	w.Address = strings.TrimSpace(w.Address)
+}
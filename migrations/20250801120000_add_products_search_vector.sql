@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE products ADD COLUMN search_vector tsvector;
+
+UPDATE products SET search_vector =
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(jsonb_to_tsvector('russian', coalesce(tags, '[]'::jsonb), '["string"]'), 'B') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'C') ||
+    setweight(jsonb_to_tsvector('russian', coalesce(attributes, '{}'::jsonb), '["string", "numeric"]'), 'D');
+
+CREATE INDEX idx_products_search_vector ON products USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
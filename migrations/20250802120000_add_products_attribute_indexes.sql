@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE INDEX idx_products_attributes ON products USING GIN (attributes jsonb_path_ops);
+CREATE INDEX idx_products_tags ON products USING GIN (tags jsonb_path_ops);
+CREATE INDEX idx_products_price ON products (price);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_price;
+DROP INDEX IF EXISTS idx_products_tags;
+DROP INDEX IF EXISTS idx_products_attributes;
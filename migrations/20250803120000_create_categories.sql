@@ -0,0 +1,84 @@
+-- +goose Up
+CREATE TABLE categories (
+                            id UUID PRIMARY KEY,
+                            parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT,
+                            name TEXT NOT NULL,
+                            slug TEXT NOT NULL UNIQUE,
+                            description TEXT,
+                            image_url TEXT,
+                            sort_order INTEGER NOT NULL DEFAULT 0,
+                            created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                            updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                            CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+ALTER TABLE products ADD COLUMN category_id UUID REFERENCES categories(id) ON DELETE RESTRICT;
+
+-- Существующие строковые категории превращаем в записи; варианты, отличающиеся
+-- регистром или пробелами, сливаются в одну категорию.
+-- Slug транслитерируется по ГОСТ 7.79-2000, как в slugify.Make; если он уже занят,
+-- к нему добавляется суффикс -2, -3 и т.д. до первого свободного
+-- +goose StatementBegin
+DO $$
+DECLARE
+    c RECORD;
+    base TEXT;
+    suffix INT;
+    candidate TEXT;
+BEGIN
+    FOR c IN
+        SELECT DISTINCT ON (key) key, name
+        FROM (
+            SELECT lower(regexp_replace(trim(category), '\s+', ' ', 'g')) AS key,
+                   trim(category) AS name
+            FROM products
+        ) src
+        ORDER BY key, name
+    LOOP
+        base := lower(translate(c.name,
+            'АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯІѢѲѴ',
+            'абвгдеёжзийклмнопрстуфхцчшщъыьэюяіѣѳѵ'));
+        -- «ц» перед i, e, y, j передаётся как «c», иначе «cz»
+        base := replace(regexp_replace(base, 'ц([иеіыйeiyj])', 'c\1', 'g'), 'ц', 'cz');
+        base := replace(replace(replace(replace(replace(replace(base,
+            'ё', 'yo'), 'ж', 'zh'), 'ч', 'ch'), 'щ', 'shh'), 'ш', 'sh'), 'ю', 'yu');
+        base := replace(replace(replace(replace(base,
+            'я', 'ya'), 'ѣ', 'ye'), 'ѳ', 'fh'), 'ѵ', 'yh');
+        -- ъ и ь не имеют пары во втором аргументе и удаляются
+        base := translate(base, 'абвгдезийклмнопрстуфхыэіъь', 'abvgdezijklmnoprstufxyei');
+        base := trim(BOTH '-' FROM regexp_replace(base, '[^a-z0-9]+', '-', 'g'));
+        IF base = '' THEN
+            base := 'category';
+        END IF;
+
+        suffix := 1;
+        candidate := base;
+        WHILE EXISTS (SELECT 1 FROM categories WHERE slug = candidate) LOOP
+            suffix := suffix + 1;
+            candidate := base || '-' || suffix;
+        END LOOP;
+
+        WITH created AS (
+            INSERT INTO categories (id, name, slug)
+            VALUES (gen_random_uuid(), c.name, candidate)
+            RETURNING id
+        )
+        UPDATE products
+        SET category_id = created.id,
+            category = c.name
+        FROM created
+        WHERE lower(regexp_replace(trim(products.category), '\s+', ' ', 'g')) = c.key;
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+ALTER TABLE products ALTER COLUMN category_id SET NOT NULL;
+
+CREATE INDEX idx_products_category_id ON products(category_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_category_id;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
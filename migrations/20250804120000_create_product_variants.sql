@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE product_variants (
+                                  id UUID PRIMARY KEY,
+                                  product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                                  sku TEXT NOT NULL UNIQUE,
+                                  options JSONB NOT NULL DEFAULT '{}'::jsonb,
+                                  price INTEGER,
+                                  old_price INTEGER,
+                                  unit_count INTEGER NOT NULL DEFAULT 0 CHECK (unit_count >= 0),
+                                  sort_order INTEGER NOT NULL DEFAULT 0,
+                                  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_product_variants_product_id ON product_variants(product_id);
+
+ALTER TABLE images ADD COLUMN variant_id UUID REFERENCES product_variants(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN IF EXISTS variant_id;
+DROP TABLE IF EXISTS product_variants;
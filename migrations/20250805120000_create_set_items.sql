@@ -0,0 +1,40 @@
+-- +goose Up
+CREATE TABLE set_items (
+                           set_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                           product_id TEXT NOT NULL REFERENCES products(id) ON DELETE RESTRICT,
+                           quantity INTEGER NOT NULL CHECK (quantity > 0),
+                           position INTEGER NOT NULL DEFAULT 0,
+                           PRIMARY KEY (set_id, product_id),
+                           CHECK (set_id <> product_id)
+);
+
+CREATE INDEX idx_set_items_product_id ON set_items(product_id);
+
+-- Переносим состав наборов из JSONB; ссылки на несуществующие товары и на другие наборы отбрасываются
+INSERT INTO set_items (set_id, product_id, quantity, position)
+SELECT s.id, i.item->>'productId', sum(greatest((i.item->>'quantity')::int, 1)), min(i.ord) - 1
+FROM products s
+CROSS JOIN LATERAL jsonb_array_elements(
+    CASE WHEN jsonb_typeof(s.includes) = 'array' THEN s.includes ELSE '[]'::jsonb END
+) WITH ORDINALITY AS i(item, ord)
+JOIN products p ON p.id = i.item->>'productId' AND p.type = 'product'
+WHERE s.type = 'set'
+GROUP BY s.id, i.item->>'productId';
+
+ALTER TABLE products ADD COLUMN set_discount_percent INTEGER CHECK (set_discount_percent BETWEEN 0 AND 100);
+ALTER TABLE products DROP COLUMN includes;
+
+-- +goose Down
+ALTER TABLE products ADD COLUMN includes JSONB;
+
+UPDATE products s
+SET includes = i.items
+FROM (
+    SELECT set_id, jsonb_agg(jsonb_build_object('productId', product_id, 'quantity', quantity) ORDER BY position) AS items
+    FROM set_items
+    GROUP BY set_id
+) i
+WHERE s.id = i.set_id;
+
+ALTER TABLE products DROP COLUMN IF EXISTS set_discount_percent;
+DROP TABLE IF EXISTS set_items;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Дубликаты slug получают суффикс: первым по дате создания остаётся исходный slug.
+-- Если slug с суффиксом уже занят другим товаром, суффикс увеличивается до первого свободного
+-- +goose StatementBegin
+DO $$
+DECLARE
+    d RECORD;
+    suffix INT;
+    candidate TEXT;
+BEGIN
+    FOR d IN
+        SELECT id, slug, n
+        FROM (
+            SELECT id, slug, row_number() OVER (PARTITION BY slug ORDER BY created_at, id) AS n
+            FROM products
+        ) ranked
+        WHERE n > 1
+        ORDER BY slug, n
+    LOOP
+        suffix := d.n;
+        candidate := d.slug || '-' || suffix;
+        WHILE EXISTS (SELECT 1 FROM products WHERE slug = candidate) LOOP
+            suffix := suffix + 1;
+            candidate := d.slug || '-' || suffix;
+        END LOOP;
+        UPDATE products SET slug = candidate WHERE id = d.id;
+    END LOOP;
+END $$;
+-- +goose StatementEnd
+
+CREATE UNIQUE INDEX idx_products_slug ON products(slug);
+
+CREATE TABLE product_slug_history (
+                                      slug TEXT PRIMARY KEY,
+                                      product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                                      created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_product_slug_history_product_id ON product_slug_history(product_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS product_slug_history;
+DROP INDEX IF EXISTS idx_products_slug;
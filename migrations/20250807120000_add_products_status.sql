@@ -0,0 +1,24 @@
+-- +goose Up
+-- Уже существующие товары остаются опубликованными
+ALTER TABLE products
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'published'
+        CHECK (status IN ('draft', 'published', 'archived')),
+    ADD COLUMN publish_at TIMESTAMP,
+    ADD COLUMN unpublish_at TIMESTAMP,
+    ADD CONSTRAINT products_publish_window CHECK (unpublish_at IS NULL OR publish_at IS NULL OR unpublish_at > publish_at);
+
+CREATE INDEX idx_products_status ON products(status);
+
+-- Для планировщика: черновики к публикации и опубликованные товары к снятию
+CREATE INDEX idx_products_publish_at ON products(publish_at) WHERE status = 'draft' AND publish_at IS NOT NULL;
+CREATE INDEX idx_products_unpublish_at ON products(unpublish_at) WHERE status = 'published' AND unpublish_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_unpublish_at;
+DROP INDEX IF EXISTS idx_products_publish_at;
+DROP INDEX IF EXISTS idx_products_status;
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS products_publish_window,
+    DROP COLUMN IF EXISTS unpublish_at,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMP;
+
+-- Корзина и задача очистки выбирают только удалённые товары
+CREATE INDEX idx_products_deleted_at ON products(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_deleted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE product_revisions (
+                                   id UUID PRIMARY KEY,
+                                   product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                                   action TEXT NOT NULL,
+                                   user_id TEXT,
+                                   changes JSONB NOT NULL DEFAULT '{}'::jsonb,
+                                   snapshot JSONB,
+                                   source_revision_id UUID REFERENCES product_revisions(id) ON DELETE SET NULL,
+                                   created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_product_revisions_product_id ON product_revisions(product_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS product_revisions;
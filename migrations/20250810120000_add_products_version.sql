@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE products ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE products DROP COLUMN IF EXISTS version;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE product_price_history (
+                                       id BIGSERIAL PRIMARY KEY,
+                                       product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                                       price INTEGER NOT NULL,
+                                       old_price INTEGER,
+                                       changed_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'UTC')
+);
+
+CREATE INDEX idx_product_price_history_product_id ON product_price_history(product_id, changed_at DESC, id DESC);
+
+-- текущие цены становятся начальной точкой истории
+INSERT INTO product_price_history (product_id, price, old_price, changed_at)
+SELECT id, price, old_price, updated_at FROM products;
+
+-- +goose Down
+DROP TABLE IF EXISTS product_price_history;
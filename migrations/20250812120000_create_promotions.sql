@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE promotions (
+                            id UUID PRIMARY KEY,
+                            name TEXT NOT NULL,
+                            description TEXT,
+                            discount_type TEXT NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+                            discount_value INTEGER NOT NULL CHECK (discount_value > 0),
+                            category_ids TEXT[] NOT NULL DEFAULT '{}',
+                            tags TEXT[] NOT NULL DEFAULT '{}',
+                            product_ids TEXT[] NOT NULL DEFAULT '{}',
+                            product_type TEXT CHECK (product_type IN ('product', 'set')),
+                            starts_at TIMESTAMP,
+                            ends_at TIMESTAMP,
+                            priority INTEGER NOT NULL DEFAULT 0,
+                            stackable BOOLEAN NOT NULL DEFAULT false,
+                            enabled BOOLEAN NOT NULL DEFAULT true,
+                            created_at TIMESTAMP NOT NULL DEFAULT now(),
+                            updated_at TIMESTAMP NOT NULL DEFAULT now(),
+                            CONSTRAINT promotions_period CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at),
+                            CONSTRAINT promotions_percent CHECK (discount_type <> 'percent' OR discount_value <= 100)
+);
+
+CREATE INDEX idx_promotions_active ON promotions(priority DESC) WHERE enabled;
+
+-- +goose Down
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE TABLE promo_codes (
+                             id UUID PRIMARY KEY,
+                             code TEXT NOT NULL,
+                             description TEXT,
+                             discount_type TEXT NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+                             discount_value INTEGER NOT NULL CHECK (discount_value > 0),
+                             min_order_amount INTEGER NOT NULL DEFAULT 0 CHECK (min_order_amount >= 0),
+                             category_ids TEXT[] NOT NULL DEFAULT '{}',
+                             product_ids TEXT[] NOT NULL DEFAULT '{}',
+                             usage_limit INTEGER CHECK (usage_limit > 0),
+                             per_user_limit INTEGER CHECK (per_user_limit > 0),
+                             used_count INTEGER NOT NULL DEFAULT 0,
+                             starts_at TIMESTAMP,
+                             expires_at TIMESTAMP,
+                             enabled BOOLEAN NOT NULL DEFAULT true,
+                             created_at TIMESTAMP NOT NULL DEFAULT now(),
+                             updated_at TIMESTAMP NOT NULL DEFAULT now(),
+                             CONSTRAINT promo_codes_period CHECK (expires_at IS NULL OR starts_at IS NULL OR expires_at > starts_at),
+                             CONSTRAINT promo_codes_percent CHECK (discount_type <> 'percent' OR discount_value <= 100),
+                             CONSTRAINT promo_codes_usage CHECK (usage_limit IS NULL OR used_count <= usage_limit)
+);
+
+-- коды вводятся покупателями в любом регистре
+CREATE UNIQUE INDEX idx_promo_codes_code ON promo_codes(upper(code));
+
+-- user_id без внешнего ключа: при выключенной авторизации запросы идут от debug-user
+CREATE TABLE promo_code_redemptions (
+                                        id UUID PRIMARY KEY,
+                                        promo_code_id UUID NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+                                        user_id TEXT NOT NULL,
+                                        order_id TEXT,
+                                        discount INTEGER NOT NULL,
+                                        created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_promo_code_redemptions_user ON promo_code_redemptions(promo_code_id, user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS promo_code_redemptions;
+DROP TABLE IF EXISTS promo_codes;
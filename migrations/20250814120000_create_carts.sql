@@ -0,0 +1,28 @@
+-- +goose Up
+-- корзины покупателей: user_id NULL — гостевая корзина, её id хранится в подписанной cookie
+CREATE TABLE carts (
+                       id UUID PRIMARY KEY,
+                       user_id TEXT UNIQUE,
+                       created_at TIMESTAMP NOT NULL DEFAULT now(),
+                       updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_carts_guest_updated ON carts(updated_at) WHERE user_id IS NULL;
+
+CREATE TABLE cart_items (
+                            id UUID PRIMARY KEY,
+                            cart_id UUID NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+                            product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                            variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE,
+                            quantity INTEGER NOT NULL CHECK (quantity > 0),
+                            created_at TIMESTAMP NOT NULL DEFAULT now(),
+                            updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- один товар (вариант) — одна позиция корзины; повторное добавление увеличивает количество
+CREATE UNIQUE INDEX idx_cart_items_unique
+    ON cart_items(cart_id, product_id, (coalesce(variant_id, '00000000-0000-0000-0000-000000000000'::uuid)));
+
+-- +goose Down
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS carts;
@@ -0,0 +1,65 @@
+-- +goose Up
+-- заказы: состав, цены и контакты сохраняются снимком на момент оформления,
+-- поэтому позиции не ссылаются на товары и не меняются вместе с каталогом
+CREATE TABLE orders (
+                        id UUID PRIMARY KEY,
+                        number BIGINT GENERATED ALWAYS AS IDENTITY (START WITH 1000) UNIQUE,
+                        user_id TEXT NOT NULL,
+                        status TEXT NOT NULL CHECK (status IN ('new', 'awaiting_payment', 'paid', 'assembling', 'shipped', 'delivered', 'cancelled', 'refunded')),
+                        customer_name TEXT NOT NULL,
+                        email TEXT NOT NULL,
+                        phone TEXT NOT NULL,
+                        delivery_method TEXT NOT NULL CHECK (delivery_method IN ('courier', 'pickup')),
+                        city TEXT NOT NULL,
+                        address TEXT NOT NULL DEFAULT '',
+                        comment TEXT NOT NULL DEFAULT '',
+                        subtotal INTEGER NOT NULL,
+                        discount INTEGER NOT NULL DEFAULT 0,
+                        promo_code TEXT,
+                        promo_discount INTEGER NOT NULL DEFAULT 0,
+                        total INTEGER NOT NULL CHECK (total >= 0),
+                        created_at TIMESTAMP NOT NULL DEFAULT now(),
+                        updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_orders_user ON orders(user_id, created_at DESC);
+CREATE INDEX idx_orders_status ON orders(status, created_at DESC);
+CREATE INDEX idx_orders_created ON orders(created_at DESC);
+
+CREATE TABLE order_items (
+                             id UUID PRIMARY KEY,
+                             order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+                             position INTEGER NOT NULL,
+                             product_id TEXT NOT NULL,
+                             variant_id UUID,
+                             type TEXT NOT NULL,
+                             slug TEXT NOT NULL,
+                             title TEXT NOT NULL,
+                             sku TEXT NOT NULL DEFAULT '',
+                             options JSONB,
+                             price INTEGER NOT NULL,
+                             old_price INTEGER,
+                             quantity INTEGER NOT NULL CHECK (quantity > 0),
+                             promo_discount INTEGER NOT NULL DEFAULT 0,
+                             line_total INTEGER NOT NULL
+);
+
+CREATE INDEX idx_order_items_order ON order_items(order_id, position);
+
+-- история статусов: changed_by NULL — переход выполнила система (например, по уведомлению об оплате)
+CREATE TABLE order_status_history (
+                                      id UUID PRIMARY KEY,
+                                      order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+                                      from_status TEXT,
+                                      to_status TEXT NOT NULL,
+                                      changed_by TEXT,
+                                      comment TEXT NOT NULL DEFAULT '',
+                                      changed_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_order_status_history_order ON order_status_history(order_id, changed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS order_status_history;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,67 @@
+-- +goose Up
+-- остатки по товарам без вариантов (variant_id NULL) и по вариантам: on_hand — на складе,
+-- reserved — зарезервировано под неоплаченные заказы. Меняются только вместе с записью в stock_movements;
+-- products.unit_count / in_stock и product_variants.unit_count хранят доступный остаток (on_hand - reserved).
+CREATE TABLE stock_levels (
+                              id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                              product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+                              variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE,
+                              on_hand INTEGER NOT NULL DEFAULT 0 CHECK (on_hand >= 0),
+                              reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+                              updated_at TIMESTAMP NOT NULL DEFAULT now(),
+                              CONSTRAINT stock_levels_reserved CHECK (reserved <= on_hand)
+);
+
+CREATE UNIQUE INDEX idx_stock_levels_item
+    ON stock_levels(product_id, (coalesce(variant_id, '00000000-0000-0000-0000-000000000000'::uuid)));
+
+-- журнал движений: строки не меняются и не удаляются, поэтому без внешних ключей на товары
+CREATE TABLE stock_movements (
+                                 id UUID PRIMARY KEY,
+                                 product_id TEXT NOT NULL,
+                                 variant_id UUID,
+                                 type TEXT NOT NULL CHECK (type IN ('receipt', 'reservation', 'release', 'sale', 'return', 'adjustment')),
+                                 quantity INTEGER NOT NULL,
+                                 on_hand_after INTEGER NOT NULL,
+                                 reserved_after INTEGER NOT NULL,
+                                 reason TEXT NOT NULL DEFAULT '',
+                                 order_id UUID,
+                                 actor_id TEXT,
+                                 created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_stock_movements_product ON stock_movements(product_id, created_at DESC);
+CREATE INDEX idx_stock_movements_order ON stock_movements(order_id) WHERE order_id IS NOT NULL;
+
+CREATE TABLE stock_reservations (
+                                    id UUID PRIMARY KEY,
+                                    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+                                    product_id TEXT NOT NULL,
+                                    variant_id UUID,
+                                    quantity INTEGER NOT NULL CHECK (quantity > 0),
+                                    status TEXT NOT NULL CHECK (status IN ('active', 'released', 'sold', 'returned')),
+                                    expires_at TIMESTAMP NOT NULL,
+                                    created_at TIMESTAMP NOT NULL DEFAULT now(),
+                                    closed_at TIMESTAMP
+);
+
+CREATE INDEX idx_stock_reservations_order ON stock_reservations(order_id);
+CREATE INDEX idx_stock_reservations_expires ON stock_reservations(expires_at) WHERE status = 'active';
+
+-- текущие остатки становятся начальными: варианты и товары без вариантов с заданным unit_count
+INSERT INTO stock_levels (product_id, variant_id, on_hand)
+SELECT product_id, id, unit_count FROM product_variants;
+
+INSERT INTO stock_levels (product_id, on_hand)
+SELECT p.id, greatest(p.unit_count, 0) FROM products p
+WHERE p.type = 'product' AND p.unit_count IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id);
+
+INSERT INTO stock_movements (id, product_id, variant_id, type, quantity, on_hand_after, reserved_after, reason)
+SELECT gen_random_uuid(), product_id, variant_id, 'adjustment', on_hand, on_hand, 0, 'initial stock'
+FROM stock_levels WHERE on_hand > 0;
+
+-- +goose Down
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS stock_movements;
+DROP TABLE IF EXISTS stock_levels;
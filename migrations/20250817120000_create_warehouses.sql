@@ -0,0 +1,68 @@
+-- +goose Up
+-- склады и шоурумы. pickup — пункт самовывоза, pickup_days — через сколько дней заказ готов к выдаче;
+-- delivery_days — срок курьерской доставки по городу склада, intercity_delivery_days — в другие города
+-- (NULL — склад туда не отгружает); priority — порядок, в котором склады резервируют товар под заказ
+CREATE TABLE warehouses (
+                            id UUID PRIMARY KEY,
+                            code TEXT NOT NULL UNIQUE,
+                            name TEXT NOT NULL,
+                            kind TEXT NOT NULL DEFAULT 'warehouse' CHECK (kind IN ('warehouse', 'showroom')),
+                            city TEXT NOT NULL DEFAULT '',
+                            address TEXT NOT NULL DEFAULT '',
+                            pickup BOOLEAN NOT NULL DEFAULT false,
+                            pickup_days INTEGER NOT NULL DEFAULT 0 CHECK (pickup_days >= 0),
+                            delivery_days INTEGER CHECK (delivery_days >= 0),
+                            intercity_delivery_days INTEGER CHECK (intercity_delivery_days >= 0),
+                            priority INTEGER NOT NULL DEFAULT 0,
+                            active BOOLEAN NOT NULL DEFAULT true,
+                            created_at TIMESTAMP NOT NULL DEFAULT now(),
+                            updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_warehouses_city ON warehouses(lower(city)) WHERE active;
+
+-- существующие остатки относятся к основному складу
+INSERT INTO warehouses (id, code, name, delivery_days)
+VALUES ('00000000-0000-0000-0000-000000000001', 'main', 'Основной склад', 3);
+
+ALTER TABLE stock_levels ADD COLUMN warehouse_id UUID REFERENCES warehouses(id);
+UPDATE stock_levels SET warehouse_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE stock_levels ALTER COLUMN warehouse_id SET NOT NULL;
+
+DROP INDEX idx_stock_levels_item;
+CREATE UNIQUE INDEX idx_stock_levels_item
+    ON stock_levels(product_id, (coalesce(variant_id, '00000000-0000-0000-0000-000000000000'::uuid)), warehouse_id);
+
+ALTER TABLE stock_movements ADD COLUMN warehouse_id UUID;
+UPDATE stock_movements SET warehouse_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE stock_movements ALTER COLUMN warehouse_id SET NOT NULL;
+
+ALTER TABLE stock_reservations ADD COLUMN warehouse_id UUID;
+UPDATE stock_reservations SET warehouse_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE stock_reservations ALTER COLUMN warehouse_id SET NOT NULL;
+
+ALTER TABLE orders ADD COLUMN pickup_point_id UUID REFERENCES warehouses(id);
+
+-- +goose Down
+ALTER TABLE orders DROP COLUMN IF EXISTS pickup_point_id;
+ALTER TABLE stock_reservations DROP COLUMN IF EXISTS warehouse_id;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS warehouse_id;
+
+-- остатки разных складов складываются обратно в одну строку
+CREATE TEMP TABLE stock_levels_merged AS
+SELECT product_id, variant_id, sum(on_hand)::int AS on_hand, sum(reserved)::int AS reserved, max(updated_at) AS updated_at
+FROM stock_levels
+GROUP BY product_id, variant_id;
+
+DELETE FROM stock_levels;
+DROP INDEX idx_stock_levels_item;
+ALTER TABLE stock_levels DROP COLUMN warehouse_id;
+
+INSERT INTO stock_levels (product_id, variant_id, on_hand, reserved, updated_at)
+SELECT product_id, variant_id, on_hand, reserved, updated_at FROM stock_levels_merged;
+DROP TABLE stock_levels_merged;
+
+CREATE UNIQUE INDEX idx_stock_levels_item
+    ON stock_levels(product_id, (coalesce(variant_id, '00000000-0000-0000-0000-000000000000'::uuid)));
+
+DROP TABLE IF EXISTS warehouses;
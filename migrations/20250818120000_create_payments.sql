@@ -0,0 +1,23 @@
+-- +goose Up
+-- платежи по заказам. external_id — ID платежа у провайдера; id передаётся провайдеру
+-- как ключ идемпотентности, поэтому повтор запроса не создаёт второй платёж
+CREATE TABLE payments (
+                          id UUID PRIMARY KEY,
+                          order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+                          provider TEXT NOT NULL,
+                          external_id TEXT NOT NULL,
+                          status TEXT NOT NULL CHECK (status IN ('pending', 'waiting_for_capture', 'succeeded', 'canceled')),
+                          amount INTEGER NOT NULL CHECK (amount > 0),
+                          currency TEXT NOT NULL,
+                          confirmation_url TEXT NOT NULL DEFAULT '',
+                          refunded_amount INTEGER NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0 AND refunded_amount <= amount),
+                          paid_at TIMESTAMP,
+                          created_at TIMESTAMP NOT NULL DEFAULT now(),
+                          updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_payments_external ON payments(provider, external_id);
+CREATE INDEX idx_payments_order ON payments(order_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS payments;
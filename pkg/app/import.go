@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"dozenChairs/internal/repository"
+	"dozenChairs/internal/services"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RunImport выполняет подкоманду import: импорт товаров из CSV/XLSX, как POST /admin/products/import.
+// Отчёт печатается в out в формате JSON; если хотя бы одна строка не импортирована, возвращается ошибка.
+//
+//	app import [-dry-run] products.xlsx
+func RunImport(ctx context.Context, conn *pgxpool.Pool, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "проверить файл без сохранения изменений")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: app import [-dry-run] <file.csv|file.xlsx>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("import file is required")
+	}
+
+	path := fs.Arg(0)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := services.ReadImportFile(f, path)
+	if err != nil {
+		return err
+	}
+
+	productService := services.NewProductService(
+		repository.NewProductRepo(conn),
+		repository.NewCategoryRepo(conn),
+		repository.NewVariantRepo(conn),
+		repository.NewRevisionRepo(conn),
+		repository.NewPriceHistoryRepo(conn),
+		// импорт работает с ценами без акций
+		nil,
+	)
+	report, err := productService.Import(ctx, rows, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", report.Failed, report.Total)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"dozenChairs/internal/repository"
+	"dozenChairs/internal/services"
+	"dozenChairs/pkg/config"
+	"dozenChairs/pkg/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StartBackgroundJobs запускает фоновые задачи сервера (планировщик публикаций, очистка корзины удалённых товаров и гостевых корзин покупателей,
+// снятие истёкших резервов товаров); они завершаются при отмене ctx
+func StartBackgroundJobs(ctx context.Context, cfg *config.Config, log logger.Logger, conn *pgxpool.Pool) {
+	productRepo := repository.NewProductRepo(conn)
+
+	go services.NewPublishScheduler(productRepo, log, cfg.PublishInterval).Run(ctx)
+	go services.NewTrashPurger(productRepo, log, cfg.TrashRetention, cfg.TrashPurgeInterval).Run(ctx)
+	go services.NewGuestCartCleaner(repository.NewCartRepo(conn), log, cfg.GuestCartTTL, cfg.TrashPurgeInterval).Run(ctx)
+	go services.NewReservationExpirer(newOrderService(cfg, conn), log, cfg.PublishInterval).Run(ctx)
+}
+
+// newOrderService собирает сервис заказов со всеми зависимостями
+func newOrderService(cfg *config.Config, conn *pgxpool.Pool) services.OrderService {
+	productRepo := repository.NewProductRepo(conn)
+	categoryRepo := repository.NewCategoryRepo(conn)
+	promotionService := services.NewPromotionService(repository.NewPromotionRepo(conn), productRepo, categoryRepo)
+	return services.NewOrderService(
+		repository.NewOrderRepo(conn),
+		repository.NewStockRepo(conn),
+		repository.NewWarehouseRepo(conn),
+		services.NewCartService(repository.NewCartRepo(conn), productRepo, promotionService),
+		services.NewPromoCodeService(repository.NewPromoCodeRepo(conn), productRepo, categoryRepo, promotionService),
+		cfg.ReservationTTL,
+	)
+}
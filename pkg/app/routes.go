@@ -5,6 +5,7 @@ import (
 	"dozenChairs/internal/auth"
 	"dozenChairs/internal/handlers"
 	"dozenChairs/internal/middlewares"
+	"dozenChairs/internal/payments"
 	"dozenChairs/internal/repository"
 	"dozenChairs/internal/services"
 	"dozenChairs/pkg/config"
@@ -12,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.uber.org/zap"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -22,12 +24,27 @@ func RegisterRoutes(
 	productHandler *handlers.ProductHandler,
 	authHandler *handlers.AuthHandler,
 	imageHandler *handlers.ImageHandler,
+	categoryHandler *handlers.CategoryHandler,
+	feedHandler *handlers.FeedHandler,
+	sitemapHandler *handlers.SitemapHandler,
+	promotionHandler *handlers.PromotionHandler,
+	promoCodeHandler *handlers.PromoCodeHandler,
+	cartHandler *handlers.CartHandler,
+	orderHandler *handlers.OrderHandler,
+	stockHandler *handlers.StockHandler,
+	warehouseHandler *handlers.WarehouseHandler,
+	paymentHandler *handlers.PaymentHandler,
 	jwtManager *auth.JWTManager,
 ) {
 
 	// Swagger
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
 
+	// SEO: поисковые роботы ищут эти файлы в корне сайта
+	r.Get("/robots.txt", sitemapHandler.GetRobotsTxt)
+	r.Get("/sitemap.xml", sitemapHandler.GetSitemap)
+	r.Get("/sitemap-{n:[0-9]+}.xml", sitemapHandler.GetSitemapPart)
+
 	r.Route("/api/v1", func(r chi.Router) {
 
 		// --- Public ---
@@ -41,21 +58,53 @@ func RegisterRoutes(
 			r.Get("/api/v1/auth/callback/{provider}", authHandler.OAuthCallback)
 
 			r.Get("/products", productHandler.GetAll)
+			r.Get("/products/search", productHandler.Search)
 			r.Get("/products/{slug}", productHandler.GetBySlug)
 			r.Get("/products/sets/{slug}", productHandler.GetSetBySlug)
 			r.Get("/products/new", productHandler.GetNew)
+			r.Get("/products/{slug}/variants", productHandler.GetVariants)
+			r.Get("/products/{slug}/price-history", productHandler.GetPriceHistory)
+			r.Get("/products/{slug}/availability", stockHandler.GetAvailability)
+			r.Get("/pickup-points", warehouseHandler.GetPickupPoints)
 
 			r.Get("/sets", productHandler.GetSets)
-			r.Get("/categories", productHandler.GetCategories)
+			r.Get("/categories", categoryHandler.GetAll)
+			r.Get("/categories/tree", categoryHandler.GetTree)
+			r.Get("/categories/{slug}", categoryHandler.GetBySlug)
+			r.Get("/feeds/{name}", feedHandler.Get)
+
+			// Уведомления платёжных провайдеров
+			r.Post("/payments/webhook/{provider}", paymentHandler.Webhook)
 
 			// Публичный просмотр изображений по товару
 			r.Get("/products/{product_id}/images", imageHandler.GetByProductID)
 		})
 
+		// --- Public, с необязательной авторизацией ---
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.OptionalAuth(jwtManager))
+			r.Post("/promo-codes/validate", promoCodeHandler.Validate)
+
+			// Корзина покупателя: гостевая по cookie или корзина вошедшего пользователя
+			r.Get("/cart", cartHandler.Get)
+			r.Delete("/cart", cartHandler.Clear)
+			r.Post("/cart/items", cartHandler.AddItem)
+			r.Put("/cart/items/{id}", cartHandler.UpdateItem)
+			r.Delete("/cart/items/{id}", cartHandler.RemoveItem)
+		})
+
 		// --- Authorized Users ---
 		r.Group(func(r chi.Router) {
 			r.Use(middlewares.RequireAuth(jwtManager))
 			r.Get("/auth/me", authHandler.Me)
+
+			// Заказы покупателя
+			r.Post("/orders", orderHandler.Checkout)
+			r.Get("/orders", orderHandler.GetMine)
+			r.Get("/orders/{id}", orderHandler.GetMineByID)
+			r.Post("/orders/{id}/cancel", orderHandler.Cancel)
+			r.Post("/orders/{id}/payments", paymentHandler.Pay)
+			r.Get("/orders/{id}/payment", paymentHandler.GetMine)
 		})
 
 		// --- Admin-only ---
@@ -64,10 +113,65 @@ func RegisterRoutes(
 			r.Use(middlewares.RequireRole("admin"))
 
 			// Товары
+			r.Get("/admin/products", productHandler.AdminGetAll)
+			r.Get("/admin/products/{slug}", productHandler.AdminGetBySlug)
+			r.Post("/admin/products/import", productHandler.Import)
+			r.Get("/admin/trash", productHandler.GetTrash)
+			r.Post("/admin/trash/{slug}/restore", productHandler.Restore)
+			r.Get("/admin/products/{slug}/revisions", productHandler.GetRevisions)
+			r.Get("/admin/products/{slug}/revisions/{id}", productHandler.GetRevision)
+			r.Post("/admin/products/{slug}/revisions/{id}/rollback", productHandler.Rollback)
+			r.Post("/admin/feeds/{name}/regenerate", feedHandler.Regenerate)
 			r.Post("/products", productHandler.Create)
 			r.Put("/products/{slug}", productHandler.Update)
+			r.Patch("/products/{slug}", productHandler.Patch)
 			r.Delete("/products/{slug}", productHandler.Delete)
 
+			// Варианты товаров
+			r.Post("/products/{slug}/variants", productHandler.CreateVariant)
+			r.Put("/products/{slug}/variants/{id}", productHandler.UpdateVariant)
+			r.Delete("/products/{slug}/variants/{id}", productHandler.DeleteVariant)
+
+			// Склад
+			r.Get("/admin/products/{slug}/stock", stockHandler.GetLevels)
+			r.Get("/admin/products/{slug}/stock/movements", stockHandler.GetMovements)
+			r.Post("/admin/products/{slug}/stock/movements", stockHandler.RecordMovement)
+
+			// Склады
+			r.Get("/admin/warehouses", warehouseHandler.GetAll)
+			r.Post("/admin/warehouses", warehouseHandler.Create)
+			r.Get("/admin/warehouses/{id}", warehouseHandler.GetByID)
+			r.Put("/admin/warehouses/{id}", warehouseHandler.Update)
+			r.Delete("/admin/warehouses/{id}", warehouseHandler.Delete)
+
+			// Акции
+			r.Get("/admin/promotions", promotionHandler.GetAll)
+			r.Post("/admin/promotions", promotionHandler.Create)
+			r.Post("/admin/promotions/preview", promotionHandler.Preview)
+			r.Get("/admin/promotions/{id}", promotionHandler.GetByID)
+			r.Put("/admin/promotions/{id}", promotionHandler.Update)
+			r.Delete("/admin/promotions/{id}", promotionHandler.Delete)
+			r.Get("/admin/promotions/{id}/preview", promotionHandler.PreviewSaved)
+
+			// Промокоды
+			r.Get("/admin/promo-codes", promoCodeHandler.GetAll)
+			r.Post("/admin/promo-codes", promoCodeHandler.Create)
+			r.Get("/admin/promo-codes/{id}", promoCodeHandler.GetByID)
+			r.Put("/admin/promo-codes/{id}", promoCodeHandler.Update)
+			r.Delete("/admin/promo-codes/{id}", promoCodeHandler.Delete)
+
+			// Заказы
+			r.Get("/admin/orders", orderHandler.AdminGetAll)
+			r.Get("/admin/orders/{id}", orderHandler.AdminGetByID)
+			r.Post("/admin/orders/{id}/transitions", orderHandler.Transition)
+			r.Get("/admin/orders/{id}/payments", paymentHandler.AdminGetByOrder)
+			r.Post("/admin/orders/{id}/refund", paymentHandler.Refund)
+
+			// Категории
+			r.Post("/categories", categoryHandler.Create)
+			r.Put("/categories/{id}", categoryHandler.Update)
+			r.Delete("/categories/{id}", categoryHandler.Delete)
+
 			// Изображения
 			r.Post("/upload", imageHandler.Upload)
 			r.Delete("/images/{id}", imageHandler.Delete)
@@ -81,19 +185,64 @@ func SetupRouter(cfg *config.Config, log logger.Logger, conn *pgxpool.Pool) http
 	sessionRepo := repository.NewSessionRepo(conn)
 	imageRepo := repository.NewImageRepo(conn)
 	productRepo := repository.NewProductRepo(conn)
+	categoryRepo := repository.NewCategoryRepo(conn)
+	variantRepo := repository.NewVariantRepo(conn)
+	revisionRepo := repository.NewRevisionRepo(conn)
+	priceHistoryRepo := repository.NewPriceHistoryRepo(conn)
+	promotionRepo := repository.NewPromotionRepo(conn)
+	promoCodeRepo := repository.NewPromoCodeRepo(conn)
+	cartRepo := repository.NewCartRepo(conn)
+	orderRepo := repository.NewOrderRepo(conn)
+	stockRepo := repository.NewStockRepo(conn)
+	warehouseRepo := repository.NewWarehouseRepo(conn)
+	paymentRepo := repository.NewPaymentRepo(conn)
+
+	// Платёжный провайдер
+	paymentProvider, err := payments.New(cfg.Payment, cfg.Production())
+	if err != nil {
+		log.Fatal("failed to configure payment provider", zap.Error(err))
+	}
 
 	// Сервисы
 	authService := services.NewAuthService(userRepo, sessionRepo)
-	imageService := services.NewImageService(imageRepo)
-	productService := services.NewProductService(productRepo)
+	imageService := services.NewImageService(imageRepo, variantRepo)
+	promotionService := services.NewPromotionService(promotionRepo, productRepo, categoryRepo)
+	productService := services.NewProductService(productRepo, categoryRepo, variantRepo, revisionRepo, priceHistoryRepo, promotionService)
+	categoryService := services.NewCategoryService(categoryRepo)
+	promoCodeService := services.NewPromoCodeService(promoCodeRepo, productRepo, categoryRepo, promotionService)
+	cartService := services.NewCartService(cartRepo, productRepo, promotionService)
+	orderService := services.NewOrderService(orderRepo, stockRepo, warehouseRepo, cartService, promoCodeService, cfg.ReservationTTL)
+	stockService := services.NewStockService(stockRepo, productRepo, variantRepo, warehouseRepo)
+	warehouseService := services.NewWarehouseService(warehouseRepo)
+	paymentService := services.NewPaymentService(paymentRepo, orderService, paymentProvider, cfg.Shop.Currency, cfg.Payment.ReturnURL)
+	feedService := services.NewFeedService(productRepo, productService, categoryRepo, cfg.Shop, cfg.FeedDir, cfg.FeedTTL, log)
+	sitemapService := services.NewSitemapService(productRepo, categoryRepo, cfg.Shop, cfg.SitemapDir, cfg.FeedTTL, cfg.RobotsTxtPath, cfg.RobotsDisallow)
 
 	// JWT
 	jwtManager := auth.NewJWTManager(cfg.JWT.AccessSecret, cfg.JWT.RefreshSecret)
 
 	// Хендлеры
-	authHandler := handlers.NewAuthHandler(authService, log, jwtManager)
+	// cookie гостевой корзины подписывается отдельным ключом: пустой ключ позволяет подделать подпись
+	switch cfg.CartSecret {
+	case "":
+		log.Fatal("CART_SECRET is not set")
+	case cfg.JWT.AccessSecret, cfg.JWT.RefreshSecret:
+		log.Fatal("CART_SECRET must differ from JWT secrets")
+	}
+	cartCookie := handlers.CartCookie{Secret: cfg.CartSecret, TTL: cfg.GuestCartTTL}
+	authHandler := handlers.NewAuthHandler(authService, log, jwtManager, cartService, cartCookie)
 	imageHandler := handlers.NewImageHandler(imageService)
 	productHandler := handlers.NewProductHandler(productService, log)
+	categoryHandler := handlers.NewCategoryHandler(categoryService, log)
+	feedHandler := handlers.NewFeedHandler(feedService, log, cfg.FeedTTL)
+	sitemapHandler := handlers.NewSitemapHandler(sitemapService, log)
+	promotionHandler := handlers.NewPromotionHandler(promotionService, log)
+	promoCodeHandler := handlers.NewPromoCodeHandler(promoCodeService, log)
+	cartHandler := handlers.NewCartHandler(cartService, log, cartCookie)
+	orderHandler := handlers.NewOrderHandler(orderService, paymentService, log)
+	stockHandler := handlers.NewStockHandler(stockService, log)
+	warehouseHandler := handlers.NewWarehouseHandler(warehouseService, log)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, log, cfg.Payment.WebhookRealIPHeader)
 
 	// Роутер
 	r := chi.NewRouter()
@@ -109,7 +258,7 @@ func SetupRouter(cfg *config.Config, log logger.Logger, conn *pgxpool.Pool) http
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
 	r.Handle("/uploads/*", http.StripPrefix("/uploads/", http.FileServer(http.Dir("./uploads"))))
 
-	RegisterRoutes(r, productHandler, authHandler, imageHandler, jwtManager)
+	RegisterRoutes(r, productHandler, authHandler, imageHandler, categoryHandler, feedHandler, sitemapHandler, promotionHandler, promoCodeHandler, cartHandler, orderHandler, stockHandler, warehouseHandler, paymentHandler, jwtManager)
 
 	return r
 }
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,11 +15,73 @@ type JWTConfig struct {
 	RefreshSecret string `mapstructure:"refresh_secret"`
 }
 
+// ShopConfig — данные магазина для выгрузок на маркетплейсы
+type ShopConfig struct {
+	Name    string `mapstructure:"name"`
+	Company string `mapstructure:"company"`
+	// Brand — бренд товаров в фидах, если у товара нет атрибута brand
+	Brand string `mapstructure:"brand"`
+	// BaseURL — адрес витрины, от которого строятся ссылки на товары
+	BaseURL string `mapstructure:"base_url"`
+	// MediaURL — адрес, с которого раздаются загруженные изображения (/uploads); по умолчанию BaseURL
+	MediaURL string `mapstructure:"media_url"`
+	Currency string `mapstructure:"currency"`
+}
+
 type Config struct {
+	// Env — окружение: production или development (по умолчанию)
+	Env         string    `mapstructure:"env"`
 	ServerPort  string    `mapstructure:"server_port"`
 	DatabaseDSN string    `mapstructure:"database_dsn"`
 	JWT         JWTConfig `mapstructure:"jwt"`
 	AuthEnabled bool      `mapstructure:"AUTH_ENABLED"`
+	// PublishInterval — период проверки расписания публикации товаров
+	PublishInterval time.Duration `mapstructure:"publish_interval"`
+	// TrashRetention — сколько удалённый товар хранится в корзине до окончательного удаления
+	TrashRetention     time.Duration `mapstructure:"trash_retention"`
+	TrashPurgeInterval time.Duration `mapstructure:"trash_purge_interval"`
+	Shop               ShopConfig    `mapstructure:"shop"`
+	// FeedDir — каталог, в котором хранятся сгенерированные фиды
+	FeedDir string `mapstructure:"feed_dir"`
+	// FeedTTL — возраст, после которого фид перегенерируется при следующем запросе
+	FeedTTL time.Duration `mapstructure:"feed_ttl"`
+	// SitemapDir — каталог, в котором хранятся сгенерированные файлы sitemap
+	SitemapDir string `mapstructure:"sitemap_dir"`
+	// RobotsTxtPath — файл с содержимым robots.txt; если не задан, отдаётся robots.txt по умолчанию
+	RobotsTxtPath string `mapstructure:"robots_txt_path"`
+	// RobotsDisallow — пути, закрытые от индексации в robots.txt по умолчанию
+	RobotsDisallow []string `mapstructure:"robots_disallow"`
+	// CartSecret — ключ подписи cookie гостевой корзины покупателя (CART_SECRET); обязателен и
+	// не должен совпадать с ключами JWT, иначе утечка одного ключа раскрывает оба
+	CartSecret string `mapstructure:"cart_secret"`
+	// GuestCartTTL — сколько хранится гостевая корзина покупателя без изменений
+	GuestCartTTL time.Duration `mapstructure:"guest_cart_ttl"`
+	// ReservationTTL — сколько товары оформленного заказа держатся в резерве; неоплаченный заказ затем отменяется
+	ReservationTTL time.Duration `mapstructure:"reservation_ttl"`
+	Payment        PaymentConfig `mapstructure:"payment"`
+}
+
+// PaymentConfig — приём оплаты заказов
+type PaymentConfig struct {
+	// Provider — платёжный провайдер: yookassa или fake (встроенный, для разработки; в production недоступен)
+	Provider string `mapstructure:"provider"`
+	// ReturnURL — страница витрины, на которую покупатель возвращается после оплаты
+	ReturnURL string `mapstructure:"return_url"`
+	// WebhookRealIPHeader — заголовок, в котором reverse proxy передаёт адрес отправителя уведомления
+	// (например, X-Real-IP); пустой — берётся адрес соединения
+	WebhookRealIPHeader string `mapstructure:"webhook_real_ip_header"`
+	// FakeAutoSucceed — платежи встроенного провайдера сразу считаются оплаченными
+	FakeAutoSucceed bool           `mapstructure:"fake_auto_succeed"`
+	YooKassa        YooKassaConfig `mapstructure:"yookassa"`
+}
+
+type YooKassaConfig struct {
+	// BaseURL — адрес API; в тестах можно указать локальный сервер
+	BaseURL   string `mapstructure:"base_url"`
+	ShopID    string `mapstructure:"shop_id"`
+	SecretKey string `mapstructure:"secret_key"`
+	// WebhookIPs — адреса и подсети, с которых YooKassa отправляет уведомления; с остальных уведомления отклоняются
+	WebhookIPs []string `mapstructure:"webhook_ips"`
 }
 
 func LoadConfig() *Config {
@@ -26,13 +91,64 @@ func LoadConfig() *Config {
 	}
 
 	return &Config{
+		Env:         getEnv("APP_ENV", "development"),
 		ServerPort:  getEnv("SERVER_PORT", "8080"),
 		DatabaseDSN: getEnv("DATABASE_URL", ""),
 		JWT: JWTConfig{
 			AccessSecret:  getEnv("JWT_ACCESS_SECRET", ""),
 			RefreshSecret: getEnv("JWT_REFRESH_SECRET", ""),
 		},
-		AuthEnabled: getEnv("AUTH_ENABLED", "true") == "true",
+		AuthEnabled:        getEnv("AUTH_ENABLED", "true") == "true",
+		PublishInterval:    getDuration("PUBLISH_SCHEDULER_INTERVAL", time.Minute),
+		TrashRetention:     time.Duration(getInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		TrashPurgeInterval: getDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		Shop:               loadShopConfig(),
+		FeedDir:            getEnv("FEED_DIR", "feeds"),
+		FeedTTL:            getDuration("FEED_TTL", time.Hour),
+		SitemapDir:         getEnv("SITEMAP_DIR", "sitemap"),
+		RobotsTxtPath:      getEnv("ROBOTS_TXT_PATH", ""),
+		RobotsDisallow:     getList("ROBOTS_DISALLOW", []string{"/api/", "/admin/", "/cart", "/checkout"}),
+		CartSecret:         getEnv("CART_SECRET", ""),
+		GuestCartTTL:       time.Duration(getInt("GUEST_CART_TTL_DAYS", 30)) * 24 * time.Hour,
+		ReservationTTL:     getDuration("RESERVATION_TTL", 30*time.Minute),
+		Payment:            loadPaymentConfig(),
+	}
+}
+
+// Production сообщает, запущен ли сервер в боевом окружении
+func (c *Config) Production() bool {
+	return c.Env == "production"
+}
+
+func loadPaymentConfig() PaymentConfig {
+	return PaymentConfig{
+		Provider:            getEnv("PAYMENT_PROVIDER", "fake"),
+		ReturnURL:           getEnv("PAYMENT_RETURN_URL", strings.TrimRight(getEnv("SHOP_BASE_URL", "http://localhost:3000"), "/")+"/orders"),
+		WebhookRealIPHeader: getEnv("PAYMENT_WEBHOOK_REAL_IP_HEADER", ""),
+		FakeAutoSucceed:     getEnv("PAYMENT_FAKE_AUTO_SUCCEED", "true") == "true",
+		YooKassa: YooKassaConfig{
+			BaseURL:   strings.TrimRight(getEnv("YOOKASSA_BASE_URL", "https://api.yookassa.ru/v3"), "/"),
+			ShopID:    getEnv("YOOKASSA_SHOP_ID", ""),
+			SecretKey: getEnv("YOOKASSA_SECRET_KEY", ""),
+			// адреса, опубликованные в документации YooKassa
+			WebhookIPs: getList("YOOKASSA_WEBHOOK_IPS", []string{
+				"185.71.76.0/27", "185.71.77.0/27", "77.75.153.0/25", "77.75.156.11",
+				"77.75.156.35", "77.75.154.128/25", "2a02:5180::/32",
+			}),
+		},
+	}
+}
+
+func loadShopConfig() ShopConfig {
+	baseURL := strings.TrimRight(getEnv("SHOP_BASE_URL", "http://localhost:3000"), "/")
+	name := getEnv("SHOP_NAME", "Dozen Chairs")
+	return ShopConfig{
+		Name:     name,
+		Company:  getEnv("SHOP_COMPANY", name),
+		Brand:    getEnv("SHOP_BRAND", name),
+		BaseURL:  baseURL,
+		MediaURL: strings.TrimRight(getEnv("SHOP_MEDIA_URL", baseURL), "/"),
+		Currency: getEnv("SHOP_CURRENCY", "RUB"),
 	}
 }
 
@@ -42,3 +158,33 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getList разбирает список значений через запятую
+func getList(key string, fallback []string) []string {
+	var items []string
+	for _, v := range strings.Split(getEnv(key, ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+	if len(items) == 0 {
+		return fallback
+	}
+	return items
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+func getInt(key string, fallback int) int {
+	n, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,43 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrEmptySecret — ключ подписи не задан: такую подпись может поставить кто угодно
+var ErrEmptySecret = errors.New("empty signing secret")
+
+// Sign добавляет к значению HMAC-SHA256 подпись: "value.signature"
+func Sign(value, secret string) (string, error) {
+	if secret == "" {
+		return "", ErrEmptySecret
+	}
+	return value + "." + signature(value, secret), nil
+}
+
+// Verify проверяет подпись, поставленную Sign, и возвращает исходное значение.
+// С пустым ключом никакая подпись не считается верной.
+func Verify(signed, secret string) (string, bool) {
+	if secret == "" {
+		return "", false
+	}
+	i := strings.LastIndexByte(signed, '.')
+	if i < 0 {
+		return "", false
+	}
+	value, sig := signed[:i], signed[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(signature(value, secret))) {
+		return "", false
+	}
+	return value, true
+}
+
+func signature(value, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,63 @@
+package slugify
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// gost — транслитерация кириллицы по ГОСТ 7.79-2000 (система Б) в варианте для URL:
+// апострофы твёрдого и мягкого знаков, а также «ы» и «э» передаются без диакритики.
+// Буква «ц» обрабатывается отдельно в Make.
+var gost = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "x", 'ч': "ch", 'ш': "sh", 'щ': "shh", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ѣ': "ye", 'ѳ': "fh", 'ѵ': "yh",
+}
+
+var pattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// Make строит slug из произвольной строки: кириллица транслитерируется по ГОСТ 7.79-2000,
+// всё, кроме латинских букв и цифр, заменяется одиночным дефисом.
+func Make(s string) string {
+	runes := []rune(strings.ToLower(s))
+
+	var b strings.Builder
+	dash := false
+	for i, r := range runes {
+		var part string
+		switch {
+		case r == 'ц':
+			// «c» перед i, e, y, j, иначе «cz»
+			part = "cz"
+			if i+1 < len(runes) && strings.ContainsRune("иеіыйeiyj", runes[i+1]) {
+				part = "c"
+			}
+		case gost[r] != "" || r == 'ъ' || r == 'ь':
+			part = gost[r]
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		default:
+			dash = b.Len() > 0
+			continue
+		}
+
+		if part == "" {
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// Valid сообщает, что s уже является корректным slug: латиница в нижнем регистре, цифры и одиночные дефисы
+func Valid(s string) bool {
+	return pattern.MatchString(s)
+}
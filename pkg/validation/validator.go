@@ -1,9 +1,52 @@
 package validation
 
-import "github.com/go-playground/validator/v10"
+import (
+	"dozenChairs/pkg/slugify"
+	"errors"
+	"fmt"
 
-var validate = validator.New()
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	_ = v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugify.Valid(fl.Field().String())
+	})
+	return v
+}
 
 func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
+
+// Messages раскладывает ошибку ValidateStruct на сообщения по полям вида "Price: failed on gte=0".
+// Ошибки, объединённые errors.Join, раскладываются по отдельности, прочие возвращаются одним сообщением.
+func Messages(err error) []string {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var msgs []string
+		for _, e := range joined.Unwrap() {
+			msgs = append(msgs, Messages(e)...)
+		}
+		return msgs
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []string{err.Error()}
+	}
+
+	msgs := make([]string, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		msgs[i] = fmt.Sprintf("%s: failed on %s", fe.Namespace(), rule)
+	}
+	return msgs
+}